@@ -0,0 +1,101 @@
+package barcode_pao
+
+import "fmt"
+
+// ─── Check digits ──────────────────────────────────────────────────────────
+//
+// JAN/EAN, UPC, ITF-14 and the other GS1 keys (GTIN, GLN, SSCC) share the
+// GS1 modulo-10 check digit. UPC-E carries the check digit of the UPC-A
+// number it zero-suppresses.
+
+// CalculateCheckDigit returns the GS1 modulo-10 check digit of body, a GS1
+// key without its check digit: 7 digits for JAN-8, 11 for UPC-A, 12 for
+// JAN-13, 13 for ITF-14 and GTIN-14, 17 for SSCC.
+func CalculateCheckDigit(body string) (string, error) {
+	if err := validateCharset(digitChars, "only digits are allowed")(body, nil); err != nil {
+		return "", inputError(-1, body, err)
+	}
+	return string(gs1CheckDigit(body)), nil
+}
+
+// VerifyCheckDigit checks the last digit of a GS1 key such as a JAN-13 or
+// ITF-14 number. A wrong digit is reported as a *DrawError with
+// ReasonBadCheckDigit.
+func VerifyCheckDigit(code string) error {
+	err := validateCharset(digitChars, "only digits are allowed")(code, nil)
+	if err == nil && len(code) < 2 {
+		err = errDraw(ReasonInvalidLength, "expected at least 2 digits, got %d", len(code))
+	}
+	if err == nil {
+		err = validateCheckDigit(code[:len(code)-1], code[len(code)-1], len(code)-1)
+	}
+	if err != nil {
+		return inputError(-1, code, err)
+	}
+	return nil
+}
+
+// ExpandUPCE returns the 12-digit UPC-A number, check digit included, that
+// a UPC-E code stands for. upce has 6 digits (number system 0), 7 digits
+// (number system and 6 digits) or 8 digits (with check digit, which is
+// verified).
+func ExpandUPCE(upce string) (string, error) {
+	if err := validateUPCE(upce, nil); err != nil {
+		return "", inputError(typeUPCE, upce, err)
+	}
+	if len(upce) == 6 {
+		upce = "0" + upce
+	}
+	body := expandUPCE(upce[:7])
+	return body + string(gs1CheckDigit(body)), nil
+}
+
+// CompressUPCA returns the 8-digit UPC-E code, check digit included, for a
+// UPC-A number of 11 digits or 12 with check digit. It returns an error
+// wrapping ErrNotCompressible if the number has no zero-suppressed form.
+func CompressUPCA(upca string) (string, error) {
+	if err := validateGTIN(11)(upca, nil); err != nil {
+		return "", inputError(typeUPCA, upca, err)
+	}
+	body := upca[:11]
+	ns, m, p := body[:1], body[1:6], body[6:11]
+	for _, d := range []string{
+		m[0:2] + p[2:5] + m[2:3], // manufacturer X0000 to X2000 (last digit 0-2)
+		m[0:3] + p[3:5] + "3",    // manufacturer XXX00
+		m[0:4] + p[4:5] + "4",    // manufacturer XXXX0
+		m + p[4:5],               // product 00005 to 00009
+	} {
+		if ns <= "1" && expandUPCE(ns+d) == body {
+			return ns + d + string(gs1CheckDigit(body)), nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrNotCompressible, upca)
+}
+
+// retailBodyLen is the number of digits before the check digit of the
+// types under a CheckDigitPolicy. UPC-E is handled separately.
+var retailBodyLen = map[int]int{typeJan8: 7, typeJan13: 12, typeUPCA: 11, typeITF14: 13}
+
+// applyCheckDigitPolicy checks a supplied check digit of the retail types.
+// Under CheckDigitLenient a wrong digit is replaced, under CheckDigitStrict
+// it is rejected. Codes without a check digit, malformed codes and other
+// types are returned unchanged for the regular validation.
+func applyCheckDigitPolicy(typeID int, code string, opts *settings) (string, error) {
+	var body string
+	switch n, ok := retailBodyLen[typeID]; {
+	case ok && len(code) == n+1:
+		body = code[:n]
+	case typeID == typeUPCE && len(code) == 8 && code[0] <= '1':
+		body = expandUPCE(code[:7])
+	default:
+		return code, nil
+	}
+	if validateCharset(digitChars, "")(code, nil) != nil {
+		return code, nil
+	}
+	got := code[len(code)-1]
+	if want := gs1CheckDigit(body); got != want && opts.checkDigitPolicy == CheckDigitLenient {
+		return code[:len(code)-1] + string(want), nil
+	}
+	return code, validateCheckDigit(body, got, len(code)-1)
+}
@@ -0,0 +1,96 @@
+package barcode_pao
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ─── Code 11 / MSI Plessey ─────────────────────────────────────────────────
+
+const code11Chars = "0123456789-"
+
+// code11Patterns are the element widths of code11Chars followed by the
+// start/stop character.
+var code11Patterns = [...]string{
+	"11112", "21112", "12112", "22111", "11212", "21211", "12211", "11122", "21121", "21111", // 0-9
+	"11211", // -
+	"11221", // start/stop
+}
+
+const code11StartStop = 11
+
+// code11Check returns the check character of data: the sum of the
+// character values weighted 1 to max from the right, cycling, modulo 11.
+// The C check digit uses max 10, the K check digit max 9.
+func code11Check(data string, max int) byte {
+	sum := 0
+	for i := 0; i < len(data); i++ {
+		sum += strings.IndexByte(code11Chars, data[len(data)-1-i]) * (i%max + 1)
+	}
+	return code11Chars[sum%11]
+}
+
+// encodeCode11 appends the number of check digits set in opts, C and then
+// K, and prints them.
+func encodeCode11(code string, opts *settings) (*Symbol, error) {
+	if err := validators[typeCode11](code, opts); err != nil {
+		return nil, err
+	}
+	data := code
+	if opts.code11Checks >= 1 {
+		data += string(code11Check(data, 10))
+	}
+	if opts.code11Checks >= 2 {
+		data += string(code11Check(data, 9))
+	}
+	bars := appendWidths(nil, code11Patterns[code11StartStop])
+	for i := 0; i < len(data); i++ {
+		bars = append(bars, 1)
+		bars = appendWidths(bars, code11Patterns[strings.IndexByte(code11Chars, data[i])])
+	}
+	bars = append(bars, 1)
+	bars = appendWidths(bars, code11Patterns[code11StartStop])
+	return &Symbol{Bars: bars, QuietZone: 10, Text: data}, nil
+}
+
+const (
+	msiStart = "21"
+	msiStop  = "121"
+)
+
+// msiCheckDigits returns the check digits of data under scheme.
+func msiCheckDigits(data string, scheme MSICheckScheme) string {
+	var check string
+	switch scheme {
+	case MSICheckMod11, MSICheckMod11Mod10:
+		check = strconv.Itoa((11 - mod11Remainder(data)) % 11)
+	case MSICheckMod10, MSICheckMod10Mod10:
+		check = string(luhnCheckDigit(data))
+	}
+	if scheme == MSICheckMod10Mod10 || scheme == MSICheckMod11Mod10 {
+		check += string(luhnCheckDigit(data + check))
+	}
+	return check
+}
+
+// encodeMSI encodes each digit as four bits, most significant first: a
+// 1 is a wide bar and narrow space, a 0 a narrow bar and wide space.
+func encodeMSI(code string, opts *settings) (*Symbol, error) {
+	if err := validators[typeMSI](code, opts); err != nil {
+		return nil, err
+	}
+	data := code + msiCheckDigits(code, opts.msiCheck)
+	bars := appendWidths(nil, msiStart)
+	for i := 0; i < len(data); i++ {
+		d := data[i] - '0'
+		for bit := 3; bit >= 0; bit-- {
+			if d&(1<<bit) != 0 {
+				bars = append(bars, 2, 1)
+			} else {
+				bars = append(bars, 1, 2)
+			}
+		}
+	}
+	bars = appendWidths(bars, msiStop)
+	return &Symbol{Bars: bars, QuietZone: 12, Text: data}, nil
+}
@@ -0,0 +1,107 @@
+package barcode_pao
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCode11Check(t *testing.T) {
+	tests := []struct {
+		code   string
+		checks int
+		text   string
+	}{
+		{"123-45", 2, "123-4552"},
+		{"123-45", 1, "123-455"},
+		{"123-45", 0, "123-45"},
+		// A check value of 10 is written as '-'.
+		{"0-", 1, "0--"},
+		// Weights cycle after 10 for C and after 9 for K.
+		{"12345678901", 2, "123456789014-"},
+	}
+	for _, tt := range tests {
+		o := testSettings()
+		o.code11Checks = tt.checks
+		sym, err := encodeCode11(tt.code, o)
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if sym.Text != tt.text {
+			t.Errorf("%s with %d checks: %s, want %s", tt.code, tt.checks, sym.Text, tt.text)
+		}
+		// Start, characters and stop, each followed by a narrow gap but
+		// the last.
+		if want := 6*(len(tt.text)+2) - 1; len(sym.Bars) != want {
+			t.Errorf("%s: %d elements, want %d", tt.code, len(sym.Bars), want)
+		}
+	}
+	if _, err := encodeCode11("12A", testSettings()); err == nil {
+		t.Error("letter accepted")
+	}
+}
+
+func TestCode11Bars(t *testing.T) {
+	o := testSettings()
+	o.code11Checks = 0
+	sym, err := encodeCode11("1-", o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int{
+		1, 1, 2, 2, 1, 1, // start
+		2, 1, 1, 1, 2, 1, // 1
+		1, 1, 2, 1, 1, 1, // -
+		1, 1, 2, 2, 1, // stop
+	}
+	if !reflect.DeepEqual(sym.Bars, want) {
+		t.Errorf("Bars = %v, want %v", sym.Bars, want)
+	}
+}
+
+func TestMSICheck(t *testing.T) {
+	tests := []struct {
+		code   string
+		scheme MSICheckScheme
+		check  string
+	}{
+		{"1234567", MSICheckMod10, "4"},
+		{"1234567", MSICheckMod11, "4"},
+		{"2468", MSICheckNone, ""},
+		{"2468", MSICheckMod10, "7"},
+		{"2468", MSICheckMod10Mod10, "76"},
+		{"2468", MSICheckMod11, "6"},
+		{"2468", MSICheckMod11Mod10, "68"},
+		// Remainders of 0 and 1 give check values 0 and 10.
+		{"0", MSICheckMod11, "0"},
+		{"6", MSICheckMod11, "10"},
+	}
+	for _, tt := range tests {
+		o := testSettings()
+		o.msiCheck = tt.scheme
+		sym, err := encodeMSI(tt.code, o)
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if sym.Text != tt.code+tt.check {
+			t.Errorf("%s with %s: %s, want %s", tt.code, tt.scheme, sym.Text, tt.code+tt.check)
+		}
+	}
+}
+
+func TestMSIBars(t *testing.T) {
+	sym, err := encodeMSI("1", testSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int{
+		2, 1, // start
+		1, 2, 1, 2, 1, 2, 2, 1, // 1 = 0001
+		2, 1, 1, 2, 1, 2, 1, 2, // check 8 = 1000
+		1, 2, 1, // stop
+	}
+	if sym.Text != "18" || !reflect.DeepEqual(sym.Bars, want) {
+		t.Errorf("%s: Bars = %v, want 18, %v", sym.Text, sym.Bars, want)
+	}
+}
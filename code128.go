@@ -0,0 +1,239 @@
+package barcode_pao
+
+import "strings"
+
+// ─── Code128 (pure Go) ─────────────────────────────────────────────────────
+
+// code128Patterns holds the bar/space widths of every Code128 symbol value.
+// Values 103–105 are START A/B/C; code128Stop is the stop pattern including
+// the final bar.
+var code128Patterns = [106]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232",
+}
+
+const code128Stop = "2331112"
+
+// Code128 special values.
+const (
+	c128Shift  = 98
+	c128CodeC  = 99
+	c128CodeB  = 100
+	c128CodeA  = 101
+	c128FNC1   = 102
+	c128StartA = 103
+)
+
+// runeFNC1 marks an FNC1 function character in encoder input.
+const runeFNC1 rune = -1
+
+// Code128 code sets.
+const (
+	c128SetA = iota
+	c128SetB
+	c128SetC
+)
+
+func c128InSet(r rune, set int) bool {
+	switch set {
+	case c128SetA:
+		return r >= 0 && r < 96
+	case c128SetB:
+		return r >= 32 && r < 128
+	}
+	return false
+}
+
+func c128Value(r rune, set int) int {
+	if set == c128SetA && r < 32 {
+		return int(r) + 64
+	}
+	return int(r) - 32
+}
+
+func isDigit(r rune) bool { return r >= '0' && r <= '9' }
+
+// code128Values encodes input into symbol values: start code, data and check
+// character, without the stop pattern. mode is AUTO, A, B or C.
+func code128Values(in []rune, mode string) ([]int, error) {
+	for i, r := range in {
+		if r != runeFNC1 && (r < 0 || r > 127) {
+			return nil, errInvalidChar(i, r, "not encodable in Code128")
+		}
+	}
+	var vals []int
+	switch strings.ToUpper(mode) {
+	case "", "AUTO":
+		vals = code128Auto(in)
+	case "A", "B":
+		set := c128SetA
+		if strings.EqualFold(mode, "B") {
+			set = c128SetB
+		}
+		vals = []int{c128StartA + set}
+		for i, r := range in {
+			switch {
+			case r == runeFNC1:
+				vals = append(vals, c128FNC1)
+			case c128InSet(r, set):
+				vals = append(vals, c128Value(r, set))
+			default:
+				return nil, errInvalidChar(i, r, "not in code set "+strings.ToUpper(mode))
+			}
+		}
+	case "C":
+		vals = []int{c128StartA + c128SetC}
+		for i := 0; i < len(in); {
+			if in[i] == runeFNC1 {
+				vals = append(vals, c128FNC1)
+				i++
+				continue
+			}
+			if !isDigit(in[i]) {
+				return nil, errInvalidChar(i, in[i], "code set C only encodes digits")
+			}
+			if i+1 >= len(in) || !isDigit(in[i+1]) {
+				return nil, errDraw(ReasonInvalidLength, "code set C needs digit pairs, odd digit at position %d", i)
+			}
+			vals = append(vals, int(in[i]-'0')*10+int(in[i+1]-'0'))
+			i += 2
+		}
+	default:
+		return nil, errDraw(ReasonInvalidOption, "unknown code mode %q", mode)
+	}
+
+	sum := vals[0]
+	for i := 1; i < len(vals); i++ {
+		sum += i * vals[i]
+	}
+	return append(vals, sum%103), nil
+}
+
+// code128Auto picks code sets to minimise symbol length, following the
+// selection rules of ISO/IEC 15417 Annex E.
+func code128Auto(in []rune) []int {
+	n := len(in)
+	digitRun := func(i int) int {
+		j := i
+		for j < n && isDigit(in[j]) {
+			j++
+		}
+		return j - i
+	}
+	// chooseAB picks A if a control character comes before any lower-case
+	// character, B otherwise.
+	chooseAB := func(i int) int {
+		for ; i < n; i++ {
+			if in[i] == runeFNC1 {
+				continue
+			}
+			if in[i] < 32 {
+				return c128SetA
+			}
+			if in[i] >= 96 {
+				return c128SetB
+			}
+		}
+		return c128SetB
+	}
+
+	first := 0
+	if n > 0 && in[0] == runeFNC1 {
+		first = 1
+	}
+	var set int
+	if r := digitRun(first); r >= 4 || (r == 2 && first+r == n) {
+		set = c128SetC
+	} else {
+		set = chooseAB(0)
+	}
+	vals := []int{c128StartA + set}
+
+	for i := 0; i < n; {
+		r := in[i]
+		if r == runeFNC1 {
+			vals = append(vals, c128FNC1)
+			i++
+			continue
+		}
+		if set == c128SetC {
+			if i+1 < n && isDigit(r) && isDigit(in[i+1]) {
+				vals = append(vals, int(r-'0')*10+int(in[i+1]-'0'))
+				i += 2
+				continue
+			}
+			set = chooseAB(i)
+			vals = append(vals, c128CodeA-set)
+			continue
+		}
+		// Switch to C for a long enough even run of digits; an odd run
+		// encodes its first digit in the current set first.
+		if run := digitRun(i); run >= 4 && (i+run == n || run >= 6) && run%2 == 0 {
+			set = c128SetC
+			vals = append(vals, c128CodeC)
+			continue
+		}
+		if c128InSet(r, set) {
+			vals = append(vals, c128Value(r, set))
+			i++
+			continue
+		}
+		other := 1 - set
+		if i+1 < n && in[i+1] != runeFNC1 && c128InSet(in[i+1], set) {
+			// A single character from the other set: SHIFT.
+			vals = append(vals, c128Shift, c128Value(r, other))
+			i++
+			continue
+		}
+		set = other
+		vals = append(vals, c128CodeA-set)
+	}
+	return vals
+}
+
+// code128Bars expands symbol values to bar/space widths including the stop
+// pattern.
+func code128Bars(vals []int) []int {
+	bars := make([]int, 0, len(vals)*6+7)
+	for _, v := range vals {
+		for _, c := range code128Patterns[v] {
+			bars = append(bars, int(c-'0'))
+		}
+	}
+	for _, c := range code128Stop {
+		bars = append(bars, int(c-'0'))
+	}
+	return bars
+}
+
+// printableText strips characters that have no human-readable form.
+func printableText(in []rune) string {
+	var sb strings.Builder
+	for _, r := range in {
+		if r >= 32 && r != 127 {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func encodeCode128(code string, opts *settings) (*Symbol, error) {
+	if code == "" {
+		return nil, errDraw(ReasonEmptyInput, "empty input")
+	}
+	in := []rune(code)
+	vals, err := code128Values(in, opts.codeMode)
+	if err != nil {
+		return nil, err
+	}
+	return &Symbol{Bars: code128Bars(vals), QuietZone: 10, Text: printableText(in)}, nil
+}
@@ -0,0 +1,146 @@
+package barcode_pao
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// fnc1 spells an input with FNC1 characters written as '|'.
+func fnc1(s string) []rune {
+	in := []rune(s)
+	for i, r := range in {
+		if r == '|' {
+			in[i] = runeFNC1
+		}
+	}
+	return in
+}
+
+// The expected values follow the selection rules of ISO/IEC 15417 Annex E;
+// every symbol was also checked with an independent decoder.
+func TestCode128AutoSets(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []int
+	}{
+		// Start C for two digits only when they are the whole input, or
+		// for four or more.
+		{"12", []int{105, 12, 14}},
+		{"123", []int{104, 17, 18, 19, 8}},
+		{"1234", []int{105, 12, 34, 82}},
+		// An odd run leaves C after the last pair.
+		{"12345", []int{105, 12, 34, c128CodeB, 21, 54}},
+		{"1234567", []int{105, 12, 34, 56, c128CodeB, 23, 44}},
+		// Six digits inside, or four at the end, switch to C.
+		{"ABC123456DEF", []int{104, 33, 34, 35, c128CodeC, 12, 34, 56, c128CodeB, 36, 37, 38, 81}},
+		{"X123456", []int{104, 56, c128CodeC, 12, 34, 56, 89}},
+		{"HI345678", []int{104, 40, 41, c128CodeC, 34, 56, 78, 68}},
+		// A control character before any lower case starts in A; a single
+		// character from the other set is shifted.
+		{"AB\x01cD", []int{103, 33, 34, 65, c128Shift, 67, 36, 3}},
+		{"a\x01b", []int{104, 65, c128Shift, 65, 66, 0}},
+		{"Ab\tc", []int{104, 33, 66, c128Shift, 73, 67, 57}},
+		// FNC1 does not count towards a digit run.
+		{"|0112", []int{105, c128FNC1, 1, 12, 39}},
+		{"|01ABC", []int{104, c128FNC1, 16, 17, 33, 34, 35, 80}},
+	}
+	for _, tt := range tests {
+		got, err := code128Values(fnc1(tt.in), "AUTO")
+		if err != nil {
+			t.Errorf("%q: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: values %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCode128FixedSets(t *testing.T) {
+	tests := []struct {
+		in, mode string
+		want     []int
+		reason   DrawReason // ReasonUnknown: no error
+		pos      int
+	}{
+		{"AB\x01", "A", []int{103, 33, 34, 65, 90}, ReasonUnknown, 0},
+		{"Ab", "A", nil, ReasonInvalidCharacter, 1},
+		{"Ab", "B", []int{104, 33, 66, 63}, ReasonUnknown, 0},
+		{"A\x01", "B", nil, ReasonInvalidCharacter, 1},
+		{"123456", "C", []int{105, 12, 34, 56, 44}, ReasonUnknown, 0},
+		{"12A4", "C", nil, ReasonInvalidCharacter, 2},
+		{"123", "C", nil, ReasonInvalidLength, 0},
+		{"12", "D", nil, ReasonInvalidOption, 0},
+		{"é", "B", nil, ReasonInvalidCharacter, 0},
+	}
+	for _, tt := range tests {
+		got, err := code128Values([]rune(tt.in), tt.mode)
+		if tt.reason == ReasonUnknown {
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q in %s: %v, %v; want %v", tt.in, tt.mode, got, err, tt.want)
+			}
+			continue
+		}
+		var de *DrawError
+		if !errors.As(err, &de) || de.Reason != tt.reason || (tt.reason == ReasonInvalidCharacter && de.Position != tt.pos) {
+			t.Errorf("%q in %s: %v, want %s at %d", tt.in, tt.mode, err, tt.reason, tt.pos)
+		}
+	}
+}
+
+func TestCode128Golden(t *testing.T) {
+	sym, err := encodeCode128("HI345678", testSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int{
+		2, 1, 1, 2, 1, 4, // START B
+		2, 3, 1, 1, 1, 3, // H
+		2, 3, 1, 3, 1, 1, // I
+		1, 1, 3, 1, 4, 1, // CODE C
+		1, 3, 1, 1, 2, 3, // 34
+		3, 3, 1, 1, 2, 1, // 56
+		2, 4, 1, 1, 1, 2, // 78
+		1, 4, 1, 2, 2, 1, // check 68
+		2, 3, 3, 1, 1, 1, 2, // STOP
+	}
+	if !reflect.DeepEqual(sym.Bars, want) {
+		t.Errorf("Bars = %v, want %v", sym.Bars, want)
+	}
+	if sym.Text != "HI345678" || sym.QuietZone != 10 {
+		t.Errorf("Text %q, QuietZone %d", sym.Text, sym.QuietZone)
+	}
+	if got := printableText([]rune("AB\x01cD\x7f")); got != "ABcD" {
+		t.Errorf("printableText = %q, want ABcD", got)
+	}
+
+	// Every symbol character is 11 modules wide, the stop pattern 13.
+	for _, tt := range []string{"AB\x01cD", "abc\t1234567890"} {
+		sym, err := encodeCode128(tt, testSettings())
+		if err != nil {
+			t.Fatalf("%q: %v", tt, err)
+		}
+		n := len(sym.Bars) - 7
+		if n%6 != 0 {
+			t.Fatalf("%q: %d elements", tt, len(sym.Bars))
+		}
+		for i := 0; i <= n; i += 6 {
+			end, want := i+6, 11
+			if i == n {
+				end, want = len(sym.Bars), 13
+			}
+			if w := sum(sym.Bars[i:end]); w != want {
+				t.Errorf("%q: character %d is %d modules wide, want %d", tt, i/6, w, want)
+			}
+		}
+	}
+}
+
+func sum(ws []int) int {
+	n := 0
+	for _, w := range ws {
+		n += w
+	}
+	return n
+}
@@ -0,0 +1,292 @@
+package barcode_pao
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ─── Code39 / Code93 ───────────────────────────────────────────────────────
+
+// code39Patterns are the wide/narrow patterns of code39Chars followed by the
+// '*' start/stop character: 9 elements, most significant bit first, set
+// bits wide.
+var code39Patterns = [...]int{
+	0x034, 0x121, 0x061, 0x160, 0x031, 0x130, 0x070, 0x025, 0x124, 0x064, // 0-9
+	0x109, 0x049, 0x148, 0x019, 0x118, 0x058, 0x00D, 0x10C, 0x04C, 0x01C, // A-J
+	0x103, 0x043, 0x142, 0x013, 0x112, 0x052, 0x007, 0x106, 0x046, 0x016, // K-T
+	0x181, 0x0C1, 0x1C0, 0x091, 0x190, 0x0D0, 0x085, 0x184, 0x0C4, 0x0A8, // U-Z - . SP $
+	0x0A2, 0x08A, 0x02A, // / + %
+	0x094, // *
+}
+
+const code39Star = 43
+
+// code39Wide is the wide element width of Code39 and NW-7 in modules.
+const code39Wide = 3
+
+func encodeCode39(code string, opts *settings) (*Symbol, error) {
+	if err := validators[typeCode39](code, opts); err != nil {
+		return nil, err
+	}
+	data, text := code, code
+	if opts.fullASCII {
+		data, text = code39FullASCII(code), printableText([]rune(code))
+	}
+	if opts.code39Check {
+		c := code39Check(data)
+		data += string(c)
+		text += string(c)
+	}
+	if opts.showStartStop {
+		text = "*" + text + "*"
+	}
+	return &Symbol{Bars: code39Bars(data), QuietZone: 10, Text: text}, nil
+}
+
+// code39Bars encodes data, which must be in code39Chars, between the '*'
+// start and stop characters.
+func code39Bars(data string) []int {
+	bars := appendWideNarrow(nil, code39Patterns[code39Star], 9, code39Wide)
+	for i := 0; i < len(data); i++ {
+		bars = append(bars, 1)
+		bars = appendWideNarrow(bars, code39Patterns[strings.IndexByte(code39Chars, data[i])], 9, code39Wide)
+	}
+	bars = append(bars, 1)
+	return appendWideNarrow(bars, code39Patterns[code39Star], 9, code39Wide)
+}
+
+// code39FullASCII replaces each ASCII character by its Full ASCII pair.
+func code39FullASCII(code string) string {
+	var sb strings.Builder
+	for i := 0; i < len(code); i++ {
+		sb.WriteString(fullASCII[code[i]])
+	}
+	return sb.String()
+}
+
+// code39Check returns the modulo-43 check character of data: the sum of
+// the character values (their indexes in code39Chars) modulo 43.
+func code39Check(data string) byte {
+	sum := 0
+	for i := 0; i < len(data); i++ {
+		sum += strings.IndexByte(code39Chars, data[i])
+	}
+	return code39Chars[sum%43]
+}
+
+// code93Patterns are the element widths of the 43 Code93 data characters
+// (in code39Chars order) and the four shift characters ($) (%) (/) (+).
+var code93Patterns = [...]string{
+	"131112", "111213", "111312", "111411", "121113", "121212", "121311", "111114", "131211", "141111", // 0-9
+	"211113", "211212", "211311", "221112", "221211", "231111", "112113", "112212", "112311", "122112", // A-J
+	"132111", "111123", "111222", "111321", "121122", "131121", "212112", "212211", "211122", "211221", // K-T
+	"221121", "222111", "112122", "112221", "122121", "123111", // U-Z
+	"121131", "311112", "311211", "321111", "112131", "113121", "211131", // - . SP $ / + %
+	"121221", "312111", "311121", "122211", // ($) (%) (/) (+)
+}
+
+const (
+	code93ShiftDollar = 43 + iota
+	code93ShiftPercent
+	code93ShiftSlash
+	code93ShiftPlus
+)
+
+const (
+	code93Start = "111141"
+	// code93Stop includes the termination bar.
+	code93Stop = "1111411"
+)
+
+// fullASCII is the Code39 Full ASCII table: the Code39 character pair that
+// stands for each ASCII character. Characters of the Code39 set map to
+// themselves, except those used as shift characters.
+var fullASCII = [128]string{
+	"%U", "$A", "$B", "$C", "$D", "$E", "$F", "$G", "$H", "$I", "$J", "$K", "$L", "$M", "$N", "$O",
+	"$P", "$Q", "$R", "$S", "$T", "$U", "$V", "$W", "$X", "$Y", "$Z", "%A", "%B", "%C", "%D", "%E",
+	" ", "/A", "/B", "/C", "/D", "/E", "/F", "/G", "/H", "/I", "/J", "/K", "/L", "-", ".", "/O",
+	"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "/Z", "%F", "%G", "%H", "%I", "%J",
+	"%V", "A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O",
+	"P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z", "%K", "%L", "%M", "%N", "%O",
+	"%W", "+A", "+B", "+C", "+D", "+E", "+F", "+G", "+H", "+I", "+J", "+K", "+L", "+M", "+N", "+O",
+	"+P", "+Q", "+R", "+S", "+T", "+U", "+V", "+W", "+X", "+Y", "+Z", "%P", "%Q", "%R", "%S", "%T",
+}
+
+// code93Values maps ASCII input to Code93 character values. Characters of
+// the Code39 set are encoded directly; the rest use the shift characters.
+func code93Values(code string) []int {
+	var vals []int
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		if j := strings.IndexByte(code39Chars, c); j >= 0 {
+			vals = append(vals, j)
+			continue
+		}
+		pair := fullASCII[c]
+		shift := code93ShiftDollar + strings.IndexByte("$%/+", pair[0])
+		vals = append(vals, shift, strings.IndexByte(code39Chars, pair[1]))
+	}
+	return vals
+}
+
+// code93Check returns the modulo-47 check character over vals with weights
+// cycling from 1 to max, counted from the right.
+func code93Check(vals []int, max int) int {
+	sum := 0
+	for i := range vals {
+		sum += vals[len(vals)-1-i] * (i%max + 1)
+	}
+	return sum % 47
+}
+
+func encodeCode93(code string, opts *settings) (*Symbol, error) {
+	if err := validators[typeCode93](code, opts); err != nil {
+		return nil, err
+	}
+	vals := code93Values(code)
+	vals = append(vals, code93Check(vals, 20))
+	vals = append(vals, code93Check(vals, 15))
+	bars := appendWidths(nil, code93Start)
+	for _, v := range vals {
+		bars = appendWidths(bars, code93Patterns[v])
+	}
+	bars = appendWidths(bars, code93Stop)
+	return &Symbol{Bars: bars, QuietZone: 10, Text: printableText([]rune(code))}, nil
+}
+
+// ─── Code 32 / HIBC ────────────────────────────────────────────────────────
+//
+// Code 32, the Italian pharmacode, and the HIBC (Health Industry Bar Code)
+// symbols are Code39 symbols carrying their own data structures.
+
+// code32Chars is the base-32 alphabet of Code 32: digits and consonants.
+const code32Chars = "0123456789BCDFGHJKLMNPQRSTUVWXYZ"
+
+// validateCode32 accepts the 8 digits of a pharmaceutical code (AIC), or 9
+// with the check digit, optionally preceded by the 'A' printed before it.
+func validateCode32(code string, _ *settings) error {
+	if err := validateNonEmpty(code); err != nil {
+		return err
+	}
+	rs := []rune(code)
+	off := 0
+	if rs[0] == 'A' {
+		off = 1
+	}
+	for i, r := range rs[off:] {
+		if !isDigit(r) {
+			return errInvalidChar(off+i, r, "only digits are allowed")
+		}
+	}
+	switch body := code[off:]; len(body) {
+	case 8:
+		return nil
+	case 9:
+		if want := code32CheckDigit(body[:8]); body[8] != want {
+			return errCheckDigit(off+8, body[8], want)
+		}
+		return nil
+	default:
+		return errDraw(ReasonInvalidLength, "expected 8 or 9 digits, got %d", len(body))
+	}
+}
+
+// code32CheckDigit returns the check digit of 8 digits: the digit sums of
+// the doubled digits in even positions and the digits in odd positions,
+// modulo 10.
+func code32CheckDigit(body string) byte {
+	sum := 0
+	for i := 0; i < 8; i++ {
+		d := int(body[i] - '0')
+		if i%2 == 1 {
+			d *= 2
+		}
+		sum += d/10 + d%10
+	}
+	return byte('0' + sum%10)
+}
+
+// encodeCode32 draws the 9 digits as a 6-character base-32 number in
+// Code39. The text is the digits after an 'A'.
+func encodeCode32(code string, opts *settings) (*Symbol, error) {
+	if err := validators[typeCode32](code, opts); err != nil {
+		return nil, err
+	}
+	digits := strings.TrimPrefix(code, "A")[:8]
+	digits += string(code32CheckDigit(digits))
+	n, _ := strconv.Atoi(digits)
+	data := make([]byte, 6)
+	for i := len(data) - 1; i >= 0; i-- {
+		data[i] = code32Chars[n%32]
+		n /= 32
+	}
+	return &Symbol{Bars: code39Bars(string(data)), QuietZone: 10, Text: "A" + digits}, nil
+}
+
+// validateHIBC checks HIBC data, with or without the leading '+', against
+// the standard set in opts.
+func validateHIBC(code string, opts *settings) error {
+	rs := []rune(code)
+	off := 0
+	if len(rs) > 0 && rs[0] == '+' {
+		off = 1
+	}
+	data := rs[off:]
+	if len(data) == 0 {
+		return errDraw(ReasonEmptyInput, "empty input")
+	}
+	for i, r := range data {
+		if !strings.ContainsRune(code39Chars, r) {
+			return errInvalidChar(off+i, r, "not in the Code39 character set")
+		}
+	}
+	if opts != nil && opts.hibcStandard == HIBCPAS {
+		if data[0] != '/' {
+			return &DrawError{Reason: ReasonInvalidCharacter, Position: off,
+				Detail: "PAS data starts with the '/' flag character"}
+		}
+		return nil
+	}
+	if data[0] == '$' {
+		return nil // secondary data only
+	}
+	primary := string(data)
+	if i := strings.IndexByte(primary, '/'); i >= 0 {
+		primary = primary[:i]
+	}
+	if n := len(primary); n < 6 || n > 23 {
+		return errDraw(ReasonInvalidLength, "LIC primary data has 6 to 23 characters, got %d", n)
+	}
+	if r := data[0]; r < 'A' || r > 'Z' {
+		return errInvalidChar(off, r, "the labeler identification code starts with a letter")
+	}
+	for i, r := range data[1 : len(primary)-1] {
+		if !isDigit(r) && (r < 'A' || r > 'Z') {
+			return errInvalidChar(off+1+i, r, "LIC primary data is alphanumeric")
+		}
+	}
+	if r := data[len(primary)-1]; !isDigit(r) {
+		return errInvalidChar(off+len(primary)-1, r, "the unit of measure is a digit")
+	}
+	return nil
+}
+
+// encodeHIBC prefixes the data with the '+' flag character and appends
+// the modulo-43 check character, which HIBC requires. The text shows a
+// space check character as '_'.
+func encodeHIBC(code string, opts *settings) (*Symbol, error) {
+	if err := validators[typeHIBC](code, opts); err != nil {
+		return nil, err
+	}
+	data := "+" + strings.TrimPrefix(code, "+")
+	c := code39Check(data)
+	data += string(c)
+	text := data
+	if c == ' ' {
+		text = text[:len(text)-1] + "_"
+	}
+	if opts.showStartStop {
+		text = "*" + text + "*"
+	}
+	return &Symbol{Bars: code39Bars(data), QuietZone: 10, Text: text}, nil
+}
@@ -0,0 +1,178 @@
+package barcode_pao
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// readCode39 decodes Code39 bars back to the characters between the start
+// and stop characters.
+func readCode39(bars []int) (string, error) {
+	if (len(bars)+1)%10 != 0 {
+		return "", fmt.Errorf("%d elements", len(bars))
+	}
+	var out []byte
+	for i := 0; i < len(bars); i += 10 {
+		p, wide := 0, 0
+		for _, w := range bars[i : i+9] {
+			p <<= 1
+			if w == code39Wide {
+				p |= 1
+				wide++
+			}
+		}
+		if wide != 3 || (i+9 < len(bars) && bars[i+9] != 1) {
+			return "", fmt.Errorf("character %d: %v", i/10, bars[i:i+9])
+		}
+		c := -1
+		for j, q := range code39Patterns {
+			if q == p {
+				c = j
+			}
+		}
+		switch {
+		case c < 0:
+			return "", fmt.Errorf("character %d: unknown pattern %09b", i/10, p)
+		case c == code39Star:
+			out = append(out, '*')
+		default:
+			out = append(out, code39Chars[c])
+		}
+	}
+	s := string(out)
+	if len(s) < 2 || s[0] != '*' || s[len(s)-1] != '*' {
+		return "", fmt.Errorf("%q lacks start or stop", s)
+	}
+	return s[1 : len(s)-1], nil
+}
+
+func TestCode39(t *testing.T) {
+	tests := []struct {
+		code           string
+		fullASCII, chk bool
+		data, text     string
+	}{
+		{"CODE39", false, false, "CODE39", "*CODE39*"},
+		{"CODE39", false, true, "CODE39W", "*CODE39W*"},
+		{"A-1 $/+%.", false, true, "A-1 $/+%.Q", "*A-1 $/+%.Q*"},
+		{"Ab\x01", true, false, "A+B$A", "*Ab*"},
+		{"Ab\x01", true, true, "A+B$AP", "*AbP*"},
+	}
+	for _, tt := range tests {
+		o := testSettings()
+		o.fullASCII, o.code39Check = tt.fullASCII, tt.chk
+		sym, err := encodeCode39(tt.code, o)
+		if err != nil {
+			t.Errorf("%q: %v", tt.code, err)
+			continue
+		}
+		if got, err := readCode39(sym.Bars); err != nil || got != tt.data {
+			t.Errorf("%q: reads %q, %v; want %q", tt.code, got, err, tt.data)
+		}
+		if sym.Text != tt.text {
+			t.Errorf("%q: Text %q, want %q", tt.code, sym.Text, tt.text)
+		}
+	}
+
+	// '*' is narrow, wide, narrow, narrow, wide, narrow, wide, narrow,
+	// narrow.
+	sym, _ := encodeCode39("1", testSettings())
+	if star := sym.Bars[:9]; !reflect.DeepEqual(star, []int{1, 3, 1, 1, 3, 1, 3, 1, 1}) {
+		t.Errorf("start character %v", star)
+	}
+}
+
+func TestCode32(t *testing.T) {
+	tests := []struct {
+		code, data, text string
+	}{
+		{"01234567", "0CSSBD", "A012345676"},
+		{"A012345676", "0CSSBD", "A012345676"},
+		{"00000000", "000000", "A000000000"},
+		{"99999999", "XTPLHS", "A999999992"},
+	}
+	for _, tt := range tests {
+		sym, err := encodeCode32(tt.code, testSettings())
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if got, err := readCode39(sym.Bars); err != nil || got != tt.data {
+			t.Errorf("%s: reads %q, %v; want %q", tt.code, got, err, tt.data)
+		}
+		if sym.Text != tt.text {
+			t.Errorf("%s: Text %q, want %q", tt.code, sym.Text, tt.text)
+		}
+	}
+
+	for code, reason := range map[string]DrawReason{
+		"012345670":  ReasonBadCheckDigit,
+		"1234567":    ReasonInvalidLength,
+		"0123456789": ReasonInvalidLength,
+		"0123A567":   ReasonInvalidCharacter,
+		"":           ReasonEmptyInput,
+	} {
+		_, err := encodeCode32(code, testSettings())
+		var de *DrawError
+		if !errors.As(err, &de) || de.Reason != reason {
+			t.Errorf("%q: %v, want %s", code, err, reason)
+		}
+	}
+}
+
+func TestHIBC(t *testing.T) {
+	tests := []struct {
+		code     string
+		standard HIBCStandard
+		data     string
+		text     string
+	}{
+		{"A123BJC5D6E71", HIBCLIC, "+A123BJC5D6E71G", "*+A123BJC5D6E71G*"},
+		{"+A123BJC5D6E71", HIBCLIC, "+A123BJC5D6E71G", "*+A123BJC5D6E71G*"},
+		{"A123BJC5D6E71/$$52001510X3", HIBCLIC, "+A123BJC5D6E71/$$52001510X3C", "*+A123BJC5D6E71/$$52001510X3C*"},
+		// A space check character is shown as '_'.
+		{"$1", HIBCLIC, "+$1 ", "*+$1_*"},
+		{"/A12", HIBCPAS, "+/A128", "*+/A128*"},
+	}
+	for _, tt := range tests {
+		o := testSettings()
+		o.hibcStandard = tt.standard
+		sym, err := encodeHIBC(tt.code, o)
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if got, err := readCode39(sym.Bars); err != nil || got != tt.data {
+			t.Errorf("%s: reads %q, %v; want %q", tt.code, got, err, tt.data)
+		}
+		if sym.Text != tt.text {
+			t.Errorf("%s: Text %q, want %q", tt.code, sym.Text, tt.text)
+		}
+	}
+
+	errTests := []struct {
+		code     string
+		standard HIBCStandard
+		reason   DrawReason
+		pos      int
+	}{
+		{"A123", HIBCLIC, ReasonInvalidLength, 0},
+		{"1234567", HIBCLIC, ReasonInvalidCharacter, 0},
+		{"+A12345B", HIBCLIC, ReasonInvalidCharacter, 7},
+		{"A12_456", HIBCLIC, ReasonInvalidCharacter, 3},
+		{"A123a5", HIBCLIC, ReasonInvalidCharacter, 4},
+		{"A12345", HIBCPAS, ReasonInvalidCharacter, 0},
+		{"+", HIBCLIC, ReasonEmptyInput, 0},
+	}
+	for _, tt := range errTests {
+		o := testSettings()
+		o.hibcStandard = tt.standard
+		_, err := encodeHIBC(tt.code, o)
+		var de *DrawError
+		if !errors.As(err, &de) || de.Reason != tt.reason || (tt.reason == ReasonInvalidCharacter && de.Position != tt.pos) {
+			t.Errorf("%s: %v, want %s at %d", tt.code, err, tt.reason, tt.pos)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package barcode_pao_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	barcode "github.com/pao-xx/barcode-pao"
+)
+
+// These tests exercise the concurrency guarantees of BarcodeBase and Pool.
+// Run them with the race detector:
+//
+//	go test -race -run 'Concurrent|Pool' .
+//
+// Code128 and QR have pure-Go encoders, so the tests run whether or not the
+// native library can be loaded.
+
+const (
+	workers    = 8
+	iterations = 40
+)
+
+// run starts n goroutines running f and waits for them, reporting the
+// first error.
+func run(t *testing.T, n int, f func(worker int) error) {
+	t.Helper()
+	errs := make(chan error, n)
+	var wg sync.WaitGroup
+	for w := 0; w < n; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			errs <- f(w)
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// isImage reports whether data is a whole PNG or SVG document, so that a
+// draw never returns the result of another call or a mix of two.
+func isImage(data []byte) bool {
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return bytes.HasSuffix(data, []byte("IEND\xaeB`\x82"))
+	case bytes.Contains(data[:min(len(data), 100)], []byte("<svg")):
+		return bytes.HasSuffix(bytes.TrimSpace(data), []byte("</svg>"))
+	}
+	return false
+}
+
+// A generator is safe for concurrent use: draws, Encode, setters and
+// getters on one generator may run in any goroutines.
+func TestConcurrentDrawAndSet(t *testing.T) {
+	c, err := barcode.NewCode128E(barcode.FormatPNG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	run(t, workers, func(w int) error {
+		for i := 0; i < iterations; i++ {
+			code := fmt.Sprintf("W%d-%04d", w, i)
+			switch (w + i) % 6 {
+			case 0:
+				data, err := c.DrawBytes(code, 300, 80)
+				if err != nil {
+					return err
+				}
+				if !isImage(data) {
+					return fmt.Errorf("DrawBytes(%q) returned a partial image", code)
+				}
+			case 1:
+				if _, err := c.Draw(code, 300, 80); err != nil {
+					return err
+				}
+			case 2:
+				img, err := c.DrawImage(code, 300, 80)
+				if err != nil {
+					return err
+				}
+				if b := img.Bounds(); b.Dx() != 300 || b.Dy() != 80 {
+					return fmt.Errorf("DrawImage(%q) is %v, want 300x80", code, b)
+				}
+			case 3:
+				sym, err := c.Encode(code)
+				if err != nil {
+					return err
+				}
+				if sym.Text != code {
+					return fmt.Errorf("Encode(%q).Text = %q", code, sym.Text)
+				}
+			case 4:
+				if err := c.SetShowText(i%2 == 0); err != nil {
+					return err
+				}
+				if err := c.SetForegroundColor(w*30, 0, i, 255); err != nil {
+					return err
+				}
+				_ = c.GetShowText()
+			case 5:
+				format := barcode.FormatPNG
+				if i%2 == 0 {
+					format = barcode.FormatSVG
+				}
+				if err := c.SetOutputFormat(format); err != nil {
+					return err
+				}
+				_ = c.Options()
+			}
+		}
+		return nil
+	})
+}
+
+// The 2D generators give the same guarantee.
+func TestConcurrentQR(t *testing.T) {
+	q, err := barcode.NewQRCodeE(barcode.FormatPNG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+	levels := []barcode.ECCLevel{barcode.ECCLevelL, barcode.ECCLevelM, barcode.ECCLevelQ, barcode.ECCLevelH}
+	run(t, workers, func(w int) error {
+		for i := 0; i < iterations; i++ {
+			if w%4 == 0 {
+				if err := q.SetErrorCorrectionLevel(levels[i%len(levels)]); err != nil {
+					return err
+				}
+				continue
+			}
+			data, err := q.DrawBytes(fmt.Sprintf("https://example.com/%d/%d", w, i), 200)
+			if err != nil {
+				return err
+			}
+			if !isImage(data) {
+				return errors.New("DrawBytes returned a partial image")
+			}
+		}
+		return nil
+	})
+}
+
+// Close may race with any other call. Each call either completes or
+// returns ErrClosed, Close itself can be called any number of times, and
+// every call after it returns ErrClosed.
+func TestConcurrentClose(t *testing.T) {
+	for round := 0; round < 10; round++ {
+		c, err := barcode.NewCode128E(barcode.FormatPNG)
+		if err != nil {
+			t.Fatal(err)
+		}
+		run(t, workers, func(w int) error {
+			for i := 0; i < iterations; i++ {
+				if w == 0 && i == iterations/2 {
+					if err := c.Close(); err != nil {
+						return fmt.Errorf("Close: %v", err)
+					}
+				}
+				var err error
+				switch i % 3 {
+				case 0:
+					_, err = c.DrawBytes("CLOSE", 200, 60)
+				case 1:
+					_, err = c.Encode("CLOSE")
+				case 2:
+					err = c.SetShowText(true)
+				}
+				if err != nil && !errors.Is(err, barcode.ErrClosed) {
+					return fmt.Errorf("call during Close: %v", err)
+				}
+			}
+			return c.Close()
+		})
+		if _, err := c.Draw("AFTER", 200, 60); !errors.Is(err, barcode.ErrClosed) {
+			t.Fatalf("Draw after Close: got %v, want ErrClosed", err)
+		}
+		if err := c.SetShowText(false); !errors.Is(err, barcode.ErrClosed) {
+			t.Fatalf("SetShowText after Close: got %v, want ErrClosed", err)
+		}
+	}
+}
+
+// A Pool never hands the same generator to two goroutines at once, and
+// reuses generators that were put back.
+func TestPoolGetPut(t *testing.T) {
+	var created atomic.Int32
+	pool := barcode.NewPool(func() (*barcode.Code128, error) {
+		created.Add(1)
+		return barcode.NewCode128E(barcode.FormatPNG)
+	})
+	var mu sync.Mutex
+	inUse := make(map[*barcode.Code128]bool)
+	run(t, workers, func(w int) error {
+		for i := 0; i < iterations; i++ {
+			c, err := pool.Get()
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			busy := inUse[c]
+			inUse[c] = true
+			mu.Unlock()
+			if busy {
+				return errors.New("Get returned a generator that is in use")
+			}
+			if _, err := c.DrawBytes(fmt.Sprintf("P%d-%d", w, i), 200, 60); err != nil {
+				return err
+			}
+			mu.Lock()
+			delete(inUse, c)
+			mu.Unlock()
+			pool.Put(c)
+		}
+		return nil
+	})
+	if n := created.Load(); n > workers*iterations/2 {
+		t.Errorf("created %d generators for %d draws; idle generators are not reused", n, workers*iterations)
+	}
+}
+
+// Do returns the generator to the pool and passes through the errors of
+// the constructor and of f.
+func TestPoolDo(t *testing.T) {
+	pool := barcode.NewPool(func() (*barcode.QR, error) {
+		return barcode.NewQRCodeE(barcode.FormatSVG)
+	})
+	run(t, workers, func(w int) error {
+		for i := 0; i < iterations; i++ {
+			err := pool.Do(func(q *barcode.QR) error {
+				data, err := q.DrawBytes(fmt.Sprintf("DO-%d-%d", w, i), 120)
+				if err == nil && !isImage(data) {
+					err = errors.New("Do: partial image")
+				}
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	errBoom := errors.New("boom")
+	if err := pool.Do(func(*barcode.QR) error { return errBoom }); !errors.Is(err, errBoom) {
+		t.Errorf("Do: got %v, want the error of f", err)
+	}
+	failing := barcode.NewPool(func() (*barcode.QR, error) { return nil, errBoom })
+	if err := failing.Do(func(*barcode.QR) error { return nil }); !errors.Is(err, errBoom) {
+		t.Errorf("Do with failing constructor: got %v, want its error", err)
+	}
+}
@@ -0,0 +1,93 @@
+package barcode_pao
+
+import (
+	"strings"
+	"time"
+)
+
+// ─── Convenience-store payment codes ───────────────────────────────────────
+//
+// 標準料金代理収納 (convenience-store bill payment) slips carry a GS1-128
+// symbol with AI (91) and 42 data digits:
+//
+//	91 | 9xxxxx  | 21 digits | 1        | YYMMDD   | 1     | 6      | 1
+//	AI | company | free use  | reissue  | deadline | stamp | amount | check
+//
+// The check digit is the GS1 modulo-10 digit over the preceding 43 digits,
+// AI included. It may be omitted, in which case the engine appends it.
+
+// Digit offsets of the fields of a convenience payment code.
+const (
+	convCompany  = 2
+	convFree     = 8
+	convReissue  = 29
+	convDeadline = 30
+	convStamp    = 36
+	convAmount   = 37
+	convCheck    = 43
+	convLength   = 44
+)
+
+// ValidateConvenience checks a convenience-store payment code the way
+// GS1128.DrawConvenience does: 43 or 44 digits, optionally preceded by
+// "{FNC1}", starting with AI 91 and a company code beginning with 9, with a
+// valid payment deadline, stamp flag and check digit. It returns a
+// *DrawError describing the first problem found.
+func ValidateConvenience(code string) error {
+	if _, err := validateConvenience(code); err != nil {
+		return inputError(typeGS1128, code, err)
+	}
+	return nil
+}
+
+// validateConvenience returns the digits of a convenience payment code, or
+// a *DrawError with positions relative to code.
+func validateConvenience(code string) (string, error) {
+	off := 0
+	if strings.HasPrefix(code, "{FNC1}") {
+		off = len("{FNC1}")
+	}
+	digits := code[off:]
+	if err := validateNonEmpty(digits); err != nil {
+		return "", err
+	}
+	for i, r := range digits {
+		if !isDigit(r) {
+			return "", errInvalidChar(off+i, r, "only digits are allowed")
+		}
+	}
+	if len(digits) != convLength-1 && len(digits) != convLength {
+		return "", errDraw(ReasonInvalidLength, "expected %d or %d digits, got %d", convLength-1, convLength, len(digits))
+	}
+	field := func(pos int, detail string) error {
+		return &DrawError{Reason: ReasonInvalidCharacter, Position: off + pos, Detail: detail}
+	}
+	if digits[:convCompany] != "91" {
+		return "", field(0, "convenience payment codes start with AI 91")
+	}
+	if digits[convCompany] != '9' {
+		return "", field(convCompany, "company code must start with 9")
+	}
+	if !validConvenienceDeadline(digits[convDeadline:convStamp]) {
+		return "", field(convDeadline, "invalid payment deadline "+digits[convDeadline:convStamp]+", expected YYMMDD")
+	}
+	if digits[convStamp] > '1' {
+		return "", field(convStamp, "stamp flag must be 0 or 1")
+	}
+	if len(digits) == convLength {
+		if err := validateCheckDigit(digits[:convCheck], digits[convCheck], off+convCheck); err != nil {
+			return "", err
+		}
+	}
+	return digits, nil
+}
+
+// validConvenienceDeadline accepts a YYMMDD date, or all zeros or all nines
+// for slips without a deadline.
+func validConvenienceDeadline(d string) bool {
+	if d == "000000" || d == "999999" {
+		return true
+	}
+	_, err := time.Parse("060102", d)
+	return err == nil
+}
@@ -0,0 +1,129 @@
+package barcode_pao
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"strings"
+	"unsafe"
+)
+
+// Marshalling between Go memory and the char* / pointer parameters of
+// barcode_ffi.h.
+//
+// Native calls take their arguments as uintptr, which the garbage collector
+// does not trace: a buffer whose only reference is a uintptr may be freed
+// while the engine still reads it. Every Go buffer handed to the engine is
+// therefore pinned by a cArgs until the call has returned.
+
+// maxCStringLen bounds the strings read back from the engine. A result
+// without a NUL byte within this length is reported as an error instead of
+// running off the end of the buffer.
+const maxCStringLen = 1 << 30
+
+// cPageSize is the smallest page size of the supported platforms. Scans of
+// native memory never cross a page boundary beyond the terminating NUL, so
+// they cannot fault on an unmapped page after the string.
+const cPageSize = 4096
+
+// errNativeString is returned when the engine hands back a string that is
+// not NUL-terminated within maxCStringLen bytes.
+var errNativeString = errors.New("native string exceeds size limit or is not terminated")
+
+// cArgs holds the Go memory passed to one native call. Use it as
+//
+//	var a cArgs
+//	defer a.free()
+//	procX.Call(b.handle, a.str(s), a.out(&n))
+type cArgs struct {
+	pinner runtime.Pinner
+}
+
+// str returns a pointer to a pinned NUL-terminated copy of s.
+func (a *cArgs) str(s string) uintptr {
+	buf := make([]byte, len(s)+1)
+	copy(buf, s)
+	a.pinner.Pin(&buf[0])
+	return uintptr(unsafe.Pointer(&buf[0]))
+}
+
+// out returns a pointer to v, pinned so the engine can write through it.
+func (a *cArgs) out(v *int32) uintptr {
+	a.pinner.Pin(v)
+	return uintptr(unsafe.Pointer(v))
+}
+
+// free unpins the memory once the call has returned.
+func (a *cArgs) free() {
+	a.pinner.Unpin()
+}
+
+// nativePtr converts an address returned by the engine.
+//
+// The unsafe.Pointer rules forbid turning an arbitrary uintptr back into a
+// Pointer because the garbage collector may have moved or freed the Go
+// object it referred to. The engine's results live in memory the engine
+// allocated itself, outside the Go heap, which the collector neither moves
+// nor frees, so the address stays valid until the engine releases it.
+// unsafe.Add states the conversion as arithmetic from nil, which checkptr
+// (enabled by -race) verifies at run time: it aborts if the result points
+// into the Go heap, the one case in which the conversion would be invalid.
+func nativePtr(ptr uintptr) unsafe.Pointer {
+	return unsafe.Add(nil, ptr)
+}
+
+// cStrlen returns the length of the NUL-terminated string at p, or -1 if
+// there is no NUL within max bytes.
+func cStrlen(p unsafe.Pointer, max int) int {
+	for n := 0; n < max; {
+		chunk := cPageSize - int((uintptr(p)+uintptr(n))%cPageSize)
+		if chunk > max-n {
+			chunk = max - n
+		}
+		if i := bytes.IndexByte(unsafe.Slice((*byte)(unsafe.Add(p, n)), chunk), 0); i >= 0 {
+			return n + i
+		}
+		n += chunk
+	}
+	return -1
+}
+
+// goString copies the NUL-terminated string returned by the engine.
+func goString(ptr uintptr) (string, error) {
+	if ptr == 0 {
+		return "", nil
+	}
+	p := nativePtr(ptr)
+	n := cStrlen(p, maxCStringLen)
+	if n < 0 {
+		return "", errNativeString
+	}
+	return string(unsafe.Slice((*byte)(p), n)), nil
+}
+
+// goStringBytes is goString for callers that want a byte slice.
+func goStringBytes(ptr uintptr) ([]byte, error) {
+	if ptr == 0 {
+		return nil, nil
+	}
+	p := nativePtr(ptr)
+	n := cStrlen(p, maxCStringLen)
+	if n < 0 {
+		return nil, errNativeString
+	}
+	return goBytes(ptr, n), nil
+}
+
+// goBytes copies n bytes of native memory.
+func goBytes(ptr uintptr, n int) []byte {
+	return append([]byte(nil), unsafe.Slice((*byte)(nativePtr(ptr)), n)...)
+}
+
+// checkNativeCode rejects codes the engine would silently truncate: it
+// reads its input up to the first NUL byte.
+func (b *BarcodeBase) checkNativeCode(code string) error {
+	if i := strings.IndexByte(code, 0); i >= 0 {
+		return b.drawError(code, errInvalidChar(i, 0, "the native engine does not accept NUL"))
+	}
+	return nil
+}
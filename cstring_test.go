@@ -0,0 +1,198 @@
+//go:build linux || darwin
+
+package barcode_pao
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"syscall"
+	"testing"
+	"unsafe"
+)
+
+// These tests run the marshalling helpers while the garbage collector runs
+// almost continuously. The kernel stands in for the engine: write(2) and
+// read(2) access the pinned Go buffers through their raw addresses, and
+// mmap provides memory outside the Go heap for the results read back.
+
+const stressRounds = 2000
+
+// churn allocates garbage so that SetGCPercent(1) keeps collections going.
+var churn [][]byte
+
+func stressGC(t *testing.T) {
+	t.Helper()
+	old := debug.SetGCPercent(1)
+	t.Cleanup(func() {
+		debug.SetGCPercent(old)
+		churn = nil
+	})
+}
+
+func allocGarbage(i int) {
+	churn = append(churn, make([]byte, 512+i%4096))
+	if len(churn) > 64 {
+		churn = churn[:0]
+	}
+}
+
+// sysCall issues a read or write on fd with a raw address, the way the
+// engine receives the arguments built by cArgs.
+func sysCall(t *testing.T, trap, fd, addr uintptr, n int) {
+	t.Helper()
+	got, _, errno := syscall.Syscall(trap, fd, addr, uintptr(n))
+	if errno != 0 {
+		t.Fatalf("syscall %d: %v", trap, errno)
+	}
+	if int(got) != n {
+		t.Fatalf("syscall %d transferred %d bytes, want %d", trap, got, n)
+	}
+}
+
+func TestCArgsUnderGC(t *testing.T) {
+	stressGC(t)
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	buf := make([]byte, 1024)
+	for i := 0; i < stressRounds; i++ {
+		s := strings.Repeat(fmt.Sprint(i), 1+i%50)
+		v := new(int32)
+
+		var a cArgs
+		ps := a.str(s)
+		pv := a.out(v)
+		allocGarbage(i)
+		if i%100 == 0 {
+			runtime.GC()
+		}
+
+		// The kernel reads the string, then writes the int32 through the
+		// out pointer.
+		sysCall(t, syscall.SYS_WRITE, w.Fd(), ps, len(s)+1)
+		if _, err := r.Read(buf[:len(s)+1]); err != nil {
+			t.Fatal(err)
+		}
+		want := int32(i*7919 - 1)
+		if _, err := w.Write(unsafe.Slice((*byte)(unsafe.Pointer(&want)), 4)); err != nil {
+			t.Fatal(err)
+		}
+		sysCall(t, syscall.SYS_READ, r.Fd(), pv, 4)
+		a.free()
+
+		if got := string(buf[:len(s)+1]); got != s+"\x00" {
+			t.Fatalf("round %d: str passed %q, want %q", i, got, s+"\x00")
+		}
+		if *v != want {
+			t.Fatalf("round %d: out received %d, want %d", i, *v, want)
+		}
+	}
+}
+
+// nativePages maps two pages outside the Go heap and makes the second one
+// inaccessible, so that reading past the first page faults.
+func nativePages(t *testing.T) (page []byte, base uintptr) {
+	t.Helper()
+	size := os.Getpagesize()
+	mem, err := syscall.Mmap(-1, 0, 2*size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		t.Skipf("mmap: %v", err)
+	}
+	t.Cleanup(func() { syscall.Munmap(mem) })
+	if err := syscall.Mprotect(mem[size:], syscall.PROT_NONE); err != nil {
+		t.Skipf("mprotect: %v", err)
+	}
+	return mem[:size], uintptr(unsafe.Pointer(&mem[0]))
+}
+
+func TestNativeStringsUnderGC(t *testing.T) {
+	stressGC(t)
+	page, base := nativePages(t)
+	for i := 0; i < stressRounds; i++ {
+		s := strings.Repeat(fmt.Sprint(i), 1+i%50)
+		// Alternate between the start of the page and its very end, where
+		// the NUL is the last readable byte.
+		off := 0
+		if i%2 == 1 {
+			off = len(page) - len(s) - 1
+		}
+		copy(page[off:], s)
+		page[off+len(s)] = 0
+		ptr := base + uintptr(off)
+		allocGarbage(i)
+
+		got, err := goString(ptr)
+		if err != nil || got != s {
+			t.Fatalf("round %d: goString = %q, %v; want %q", i, got, err, s)
+		}
+		gotBytes, err := goStringBytes(ptr)
+		if err != nil || string(gotBytes) != s {
+			t.Fatalf("round %d: goStringBytes = %q, %v; want %q", i, gotBytes, err, s)
+		}
+		raw := goBytes(ptr, len(s))
+		if string(raw) != s {
+			t.Fatalf("round %d: goBytes = %q, want %q", i, raw, s)
+		}
+		// The copies must not alias native memory.
+		page[off] ^= 0xff
+		if got[0] != s[0] || gotBytes[0] != s[0] || raw[0] != s[0] {
+			t.Fatalf("round %d: result aliases native memory", i)
+		}
+	}
+	if s, err := goString(0); s != "" || err != nil {
+		t.Errorf("goString(0) = %q, %v; want \"\", nil", s, err)
+	}
+	if b, err := goStringBytes(0); b != nil || err != nil {
+		t.Errorf("goStringBytes(0) = %q, %v; want nil, nil", b, err)
+	}
+}
+
+func TestCStrlenBounded(t *testing.T) {
+	stressGC(t)
+	page, base := nativePages(t)
+	for i := range page {
+		page[i] = 'x'
+	}
+	for i := 0; i < stressRounds; i++ {
+		off := i % len(page)
+		p := nativePtr(base + uintptr(off))
+		rest := len(page) - off
+
+		// Without a NUL the scan stops at max, before the guard page.
+		if n := cStrlen(p, rest); n != -1 {
+			t.Fatalf("offset %d: cStrlen of unterminated string = %d, want -1", off, n)
+		}
+		if n := cStrlen(p, rest/2); n != -1 {
+			t.Fatalf("offset %d: cStrlen(max %d) = %d, want -1", off, rest/2, n)
+		}
+
+		// With a NUL as the last byte of the page, an unbounded scan never
+		// touches the guard page.
+		page[len(page)-1] = 0
+		if n := cStrlen(p, maxCStringLen); n != rest-1 {
+			t.Fatalf("offset %d: cStrlen = %d, want %d", off, n, rest-1)
+		}
+		page[len(page)-1] = 'x'
+		allocGarbage(i)
+	}
+
+	// A result longer than the caller's bound is an error, not a fault.
+	page[len(page)-1] = 0
+	if n := cStrlen(nativePtr(base), len(page)-1); n != -1 {
+		t.Errorf("cStrlen beyond its bound = %d, want -1", n)
+	}
+	if _, err := goString(base); err != nil {
+		t.Errorf("goString of a page-long string: %v", err)
+	}
+	if !bytes.Equal(goBytes(base, 3), []byte("xxx")) {
+		t.Error("goBytes did not copy the first bytes of the page")
+	}
+}
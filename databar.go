@@ -0,0 +1,782 @@
+package barcode_pao
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ─── GS1 DataBar ───────────────────────────────────────────────────────────
+//
+// Element widths of every DataBar character come from the RSS subset width
+// algorithm of ISO/IEC 24724. All variants start with a space, so their
+// Bars begin with a 0-wide bar.
+
+// combins returns the binomial coefficient n over r.
+func combins(n, r int) int {
+	minDenom, maxDenom := r, n-r
+	if n-r <= r {
+		minDenom, maxDenom = n-r, r
+	}
+	val, j := 1, 1
+	for i := n; i > maxDenom; i-- {
+		val *= i
+		if j <= minDenom {
+			val /= j
+			j++
+		}
+	}
+	for ; j <= minDenom; j++ {
+		val /= j
+	}
+	return val
+}
+
+// rssWidths returns the widths of the elements of one parity of a DataBar
+// character: val spread over n modules and the given number of elements,
+// none wider than maxWidth. With noNarrow, at least one element is narrow.
+func rssWidths(val, n, elements, maxWidth int, noNarrow bool) []int {
+	widths := make([]int, elements)
+	narrowMask := 0
+	bar := 0
+	for ; bar < elements-1; bar++ {
+		elmWidth := 1
+		narrowMask |= 1 << bar
+		var subVal int
+		for {
+			subVal = combins(n-elmWidth-1, elements-bar-2)
+			if !noNarrow && narrowMask == 0 && n-elmWidth-(elements-bar-1) >= elements-bar-1 {
+				subVal -= combins(n-elmWidth-(elements-bar), elements-bar-2)
+			}
+			if elements-bar-1 > 1 {
+				lessVal := 0
+				for mxw := n - elmWidth - (elements - bar - 2); mxw > maxWidth; mxw-- {
+					lessVal += combins(n-elmWidth-mxw-1, elements-bar-3)
+				}
+				subVal -= lessVal * (elements - 1 - bar)
+			} else if n-elmWidth > maxWidth {
+				subVal--
+			}
+			val -= subVal
+			if val < 0 {
+				break
+			}
+			elmWidth++
+			narrowMask &^= 1 << bar
+		}
+		val += subVal
+		n -= elmWidth
+		widths[bar] = elmWidth
+	}
+	widths[bar] = n
+	return widths
+}
+
+// interleave merges odd and even element widths into one character,
+// starting with the odd elements.
+func interleave(odd, even []int) []int {
+	w := make([]int, 0, len(odd)+len(even))
+	for i := range odd {
+		w = append(w, odd[i], even[i])
+	}
+	return w
+}
+
+// reversed returns a reversed copy of widths.
+func reversed(widths []int) []int {
+	r := make([]int, len(widths))
+	for i, w := range widths {
+		r[len(r)-1-i] = w
+	}
+	return r
+}
+
+// dataBarKey validates a GTIN for DataBar 14 or Limited and returns its 13
+// digits without check digit.
+func dataBarKey(code string, opts *settings, typeID int) (string, error) {
+	if err := validators[typeID](code, opts); err != nil {
+		return "", err
+	}
+	return code[:13], nil
+}
+
+// ─── GS1 DataBar Omnidirectional (DataBar 14) ──────────────────────────────
+
+var (
+	db14GSum        = [9]int{0, 161, 961, 2015, 2715, 0, 336, 1036, 1516}
+	db14T           = [9]int{1, 10, 34, 70, 126, 4, 20, 48, 81}
+	db14ModulesOdd  = [9]int{12, 10, 8, 6, 4, 5, 7, 9, 11}
+	db14ModulesEven = [9]int{4, 6, 8, 10, 12, 10, 8, 6, 4}
+	db14WidestOdd   = [9]int{8, 6, 4, 3, 1, 2, 4, 6, 8}
+	db14WidestEven  = [9]int{1, 3, 5, 6, 8, 7, 5, 3, 1}
+	db14Weights     = [32]int{
+		1, 3, 9, 27, 2, 6, 18, 54, 4, 12, 36, 29, 8, 24, 72, 58,
+		16, 48, 65, 37, 32, 17, 51, 74, 64, 34, 23, 69, 49, 68, 46, 59,
+	}
+	db14Finders = [9][5]int{
+		{3, 8, 2, 1, 1}, {3, 5, 5, 1, 1}, {3, 3, 7, 1, 1}, {3, 1, 9, 1, 1}, {2, 7, 4, 1, 1},
+		{2, 5, 6, 1, 1}, {2, 3, 8, 1, 1}, {1, 5, 7, 1, 1}, {1, 3, 9, 1, 1},
+	}
+)
+
+// dataBar14Elements returns the 46 elements of a DataBar 14 symbol for a
+// 13-digit key, starting with a space.
+func dataBar14Elements(key string) []int {
+	accum, _ := strconv.ParseInt(key, 10, 64)
+	left, right := int(accum/4537077), int(accum%4537077)
+	values := [4]int{left / 1597, left % 1597, right / 1597, right % 1597}
+
+	// chars[i][j] is element j of data character i; outside characters
+	// (0 and 2) use groups 0–4, inside characters (1 and 3) groups 5–8.
+	var chars [4][]int
+	for i, v := range values {
+		outside := i%2 == 0
+		g := 0
+		if !outside {
+			g = 5
+		}
+		for g+1 < len(db14GSum) && db14GSum[g+1] > 0 && v >= db14GSum[g+1] {
+			g++
+		}
+		v -= db14GSum[g]
+		odd, even := v/db14T[g], v%db14T[g]
+		if !outside {
+			odd, even = even, odd
+		}
+		chars[i] = interleave(
+			rssWidths(odd, db14ModulesOdd[g], 4, db14WidestOdd[g], outside),
+			rssWidths(even, db14ModulesEven[g], 4, db14WidestEven[g], !outside))
+	}
+
+	check := 0
+	for i := range chars {
+		for j, w := range chars[i] {
+			check += db14Weights[8*i+j] * w
+		}
+	}
+	check %= 79
+	if check >= 8 {
+		check++
+	}
+	if check >= 72 {
+		check++
+	}
+	finderLeft := db14Finders[check/9]
+	finderRight := db14Finders[check%9]
+
+	e := make([]int, 0, 46)
+	e = append(e, 1, 1)
+	e = append(e, chars[0]...)
+	e = append(e, finderLeft[:]...)
+	e = append(e, reversed(chars[1])...)
+	e = append(e, chars[3]...)
+	e = append(e, reversed(finderRight[:])...)
+	e = append(e, reversed(chars[2])...)
+	return append(e, 1, 1)
+}
+
+func encodeDataBar14(code string, opts *settings) (*Symbol, error) {
+	key, err := dataBarKey(code, opts, typeGS1DataBar14)
+	if err != nil {
+		return nil, err
+	}
+	text := "(01)" + key + string(gs1CheckDigit(key))
+	e := dataBar14Elements(key)
+	switch strings.ToUpper(opts.symbolType14) {
+	case "", "OMNIDIRECTIONAL":
+		return &Symbol{Bars: append([]int{0}, e...), Text: text}, nil
+	case "STACKED":
+		top, bottom := dataBar14Rows(e)
+		sep := make([]bool, len(top))
+		for i := 4; i < len(top)-4; i++ {
+			if top[i] == bottom[i] {
+				sep[i] = !top[i]
+			} else {
+				sep[i] = !sep[i-1]
+			}
+		}
+		return &Symbol{Modules: [][]bool{top, sep, bottom}, RowHeights: []int{5, 1, 7}, QuietZone: 1, Text: text}, nil
+	case "STACKED_OMNIDIRECTIONAL":
+		top, bottom := dataBar14Rows(e)
+		mid := make([]bool, len(top))
+		for i := 5; i < len(mid)-4; i += 2 {
+			mid[i] = true
+		}
+		return &Symbol{
+			Modules: [][]bool{
+				top,
+				dataBarSeparator(top, [][2]int{{18, 33}}),
+				mid,
+				dataBarSeparator(bottom, [][2]int{{17, 32}}),
+				bottom,
+			},
+			RowHeights: []int{33, 1, 1, 1, 33},
+			QuietZone:  1,
+			Text:       text,
+		}, nil
+	}
+	return nil, errDraw(ReasonInvalidOption, "unknown symbol type %q", opts.symbolType14)
+}
+
+// dataBar14Rows splits the 46 elements of a DataBar 14 symbol into the two
+// rows of the stacked variants. Each row is closed by a bar and a space at
+// its outer end.
+func dataBar14Rows(e []int) (top, bottom []bool) {
+	top = append(widthsToRow(append([]int{0}, e[:23]...)), true, false)
+	bottom = append([]bool{true, false}, widthsToRow(e[23:])...)
+	return top, bottom
+}
+
+// dataBarSeparator returns the separator pattern next to a row: the
+// complement of the row, except over finder patterns, where light modules
+// alternate starting dark. finders lists module ranges [start, end).
+func dataBarSeparator(row []bool, finders [][2]int) []bool {
+	sep := make([]bool, len(row))
+	for i := 4; i < len(row)-4; i++ {
+		sep[i] = !row[i]
+	}
+	for _, f := range finders {
+		dark := true
+		for i := f[0]; i < f[1] && i < len(row); i++ {
+			if row[i] {
+				sep[i] = false
+				dark = true
+				continue
+			}
+			sep[i] = dark
+			dark = !dark
+		}
+	}
+	return sep
+}
+
+// ─── GS1 DataBar Limited ───────────────────────────────────────────────────
+
+var (
+	dbLtdGSum        = [7]int{0, 183064, 820064, 1000776, 1491021, 1979845, 1996939}
+	dbLtdTEven       = [7]int{28, 728, 6454, 203, 2408, 1, 16632}
+	dbLtdModulesOdd  = [7]int{17, 13, 9, 15, 11, 19, 7}
+	dbLtdModulesEven = [7]int{9, 13, 17, 11, 15, 7, 19}
+	dbLtdWidestOdd   = [7]int{6, 5, 3, 5, 4, 8, 1}
+	dbLtdWidestEven  = [7]int{3, 4, 6, 4, 5, 1, 8}
+	dbLtdWeights     = [28]int{
+		1, 3, 9, 27, 81, 65, 17, 51, 64, 14, 42, 37, 22, 66,
+		20, 60, 2, 6, 18, 54, 73, 41, 34, 13, 39, 28, 84, 74,
+	}
+)
+
+// dbLtdChecks holds the 89 check character patterns of DataBar Limited.
+var dbLtdChecks = [89][14]int{
+	{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 3, 3, 1, 1}, {1, 1, 1, 1, 1, 1, 1, 1, 1, 2, 3, 2, 1, 1},
+	{1, 1, 1, 1, 1, 1, 1, 1, 1, 3, 3, 1, 1, 1}, {1, 1, 1, 1, 1, 1, 1, 2, 1, 1, 3, 2, 1, 1},
+	{1, 1, 1, 1, 1, 1, 1, 2, 1, 2, 3, 1, 1, 1}, {1, 1, 1, 1, 1, 1, 1, 3, 1, 1, 3, 1, 1, 1},
+	{1, 1, 1, 1, 1, 2, 1, 1, 1, 1, 3, 2, 1, 1}, {1, 1, 1, 1, 1, 2, 1, 1, 1, 2, 3, 1, 1, 1},
+	{1, 1, 1, 1, 1, 2, 1, 2, 1, 1, 3, 1, 1, 1}, {1, 1, 1, 1, 1, 3, 1, 1, 1, 1, 3, 1, 1, 1},
+	{1, 1, 1, 2, 1, 1, 1, 1, 1, 1, 3, 2, 1, 1}, {1, 1, 1, 2, 1, 1, 1, 1, 1, 2, 3, 1, 1, 1},
+	{1, 1, 1, 2, 1, 1, 1, 2, 1, 1, 3, 1, 1, 1}, {1, 1, 1, 2, 1, 2, 1, 1, 1, 1, 3, 1, 1, 1},
+	{1, 1, 1, 3, 1, 1, 1, 1, 1, 1, 3, 1, 1, 1}, {1, 2, 1, 1, 1, 1, 1, 1, 1, 1, 3, 2, 1, 1},
+	{1, 2, 1, 1, 1, 1, 1, 1, 1, 2, 3, 1, 1, 1}, {1, 2, 1, 1, 1, 1, 1, 2, 1, 1, 3, 1, 1, 1},
+	{1, 2, 1, 1, 1, 2, 1, 1, 1, 1, 3, 1, 1, 1}, {1, 2, 1, 2, 1, 1, 1, 1, 1, 1, 3, 1, 1, 1},
+	{1, 3, 1, 1, 1, 1, 1, 1, 1, 1, 3, 1, 1, 1}, {1, 1, 1, 1, 1, 1, 1, 1, 2, 1, 3, 2, 1, 1},
+	{1, 1, 1, 1, 1, 1, 1, 1, 2, 2, 3, 1, 1, 1}, {1, 1, 1, 1, 1, 1, 1, 2, 2, 1, 3, 1, 1, 1},
+	{1, 1, 1, 1, 1, 2, 1, 1, 2, 1, 3, 1, 1, 1}, {1, 1, 1, 2, 1, 1, 1, 1, 2, 1, 3, 1, 1, 1},
+	{1, 2, 1, 1, 1, 1, 1, 1, 2, 1, 3, 1, 1, 1}, {1, 1, 1, 1, 1, 1, 2, 1, 1, 1, 3, 2, 1, 1},
+	{1, 1, 1, 1, 1, 1, 2, 1, 1, 2, 3, 1, 1, 1}, {1, 1, 1, 2, 1, 1, 2, 1, 1, 1, 3, 1, 1, 1},
+	{1, 2, 1, 1, 1, 1, 2, 1, 1, 1, 3, 1, 1, 1}, {1, 1, 1, 1, 2, 1, 1, 1, 1, 1, 3, 2, 1, 1},
+	{1, 1, 1, 1, 2, 1, 1, 1, 1, 2, 3, 1, 1, 1}, {1, 1, 1, 1, 2, 1, 1, 2, 1, 1, 3, 1, 1, 1},
+	{1, 1, 1, 1, 2, 1, 2, 1, 1, 1, 3, 1, 1, 1}, {1, 1, 1, 1, 2, 2, 1, 1, 1, 1, 3, 1, 1, 1},
+	{1, 2, 1, 1, 2, 1, 1, 1, 1, 1, 3, 1, 1, 1}, {1, 1, 1, 1, 1, 1, 2, 1, 2, 1, 3, 1, 1, 1},
+	{1, 1, 1, 1, 1, 1, 2, 2, 1, 1, 3, 1, 1, 1}, {1, 1, 1, 2, 1, 1, 2, 1, 1, 1, 3, 1, 1, 1},
+	{1, 1, 1, 1, 1, 2, 2, 1, 1, 1, 3, 1, 1, 1}, {1, 1, 1, 2, 2, 1, 1, 1, 1, 1, 3, 1, 1, 1},
+	{1, 2, 1, 1, 1, 1, 2, 1, 1, 1, 3, 1, 1, 1}, {1, 1, 2, 1, 1, 1, 1, 1, 1, 1, 3, 2, 1, 1},
+	{1, 1, 2, 1, 1, 1, 1, 1, 1, 2, 3, 1, 1, 1}, {1, 1, 2, 1, 1, 1, 1, 2, 1, 1, 3, 1, 1, 1},
+	{1, 1, 2, 1, 1, 2, 1, 1, 1, 1, 3, 1, 1, 1}, {1, 1, 2, 2, 1, 1, 1, 1, 1, 1, 3, 1, 1, 1},
+	{1, 2, 2, 1, 1, 1, 1, 1, 1, 1, 3, 1, 1, 1}, {1, 1, 1, 1, 2, 1, 1, 1, 2, 1, 3, 1, 1, 1},
+	{1, 1, 1, 1, 2, 1, 1, 2, 2, 1, 3, 1, 1, 1}, {1, 1, 1, 2, 2, 1, 1, 1, 2, 1, 3, 1, 1, 1},
+	{1, 1, 1, 1, 2, 2, 1, 1, 2, 1, 3, 1, 1, 1}, {1, 2, 1, 1, 2, 1, 1, 1, 2, 1, 3, 1, 1, 1},
+	{1, 1, 1, 1, 2, 1, 2, 1, 2, 1, 3, 1, 1, 1}, {1, 1, 1, 1, 2, 1, 2, 2, 1, 1, 3, 1, 1, 1},
+	{1, 1, 1, 2, 2, 1, 2, 1, 1, 1, 3, 1, 1, 1}, {1, 2, 1, 1, 2, 1, 2, 1, 1, 1, 3, 1, 1, 1},
+	{1, 1, 1, 1, 3, 1, 1, 1, 2, 1, 3, 1, 1, 1}, {1, 2, 1, 1, 2, 2, 1, 1, 1, 1, 3, 1, 1, 1},
+	{1, 2, 1, 1, 1, 1, 2, 1, 2, 1, 3, 1, 1, 1}, {1, 1, 2, 1, 1, 1, 2, 1, 1, 1, 3, 1, 1, 1},
+	{1, 1, 2, 1, 2, 1, 1, 1, 1, 1, 3, 1, 1, 1}, {1, 1, 2, 2, 1, 1, 1, 1, 1, 1, 3, 1, 1, 1},
+	{1, 2, 2, 1, 2, 1, 1, 1, 1, 1, 3, 1, 1, 1}, {1, 2, 2, 1, 1, 1, 1, 1, 2, 1, 3, 1, 1, 1},
+	{1, 2, 2, 1, 1, 1, 2, 1, 1, 1, 3, 1, 1, 1}, {1, 1, 2, 1, 2, 1, 2, 1, 1, 1, 3, 1, 1, 1},
+	{1, 1, 2, 1, 2, 2, 1, 1, 1, 1, 3, 1, 1, 1}, {1, 2, 2, 1, 2, 1, 2, 1, 1, 1, 3, 1, 1, 1},
+	{1, 1, 1, 1, 2, 1, 2, 1, 2, 2, 3, 1, 1, 1}, {1, 1, 1, 1, 2, 2, 2, 1, 2, 1, 3, 1, 1, 1},
+	{1, 1, 1, 2, 2, 1, 2, 1, 2, 1, 3, 1, 1, 1}, {1, 1, 1, 1, 2, 2, 2, 2, 1, 1, 3, 1, 1, 1},
+	{1, 2, 1, 1, 2, 1, 2, 1, 2, 1, 3, 1, 1, 1}, {1, 1, 1, 1, 3, 1, 2, 1, 2, 1, 3, 1, 1, 1},
+	{1, 2, 1, 1, 2, 2, 2, 1, 1, 1, 3, 1, 1, 1}, {1, 2, 1, 1, 3, 1, 2, 1, 1, 1, 3, 1, 1, 1},
+	{1, 1, 2, 1, 2, 1, 2, 1, 2, 1, 3, 1, 1, 1}, {1, 1, 2, 1, 2, 2, 2, 1, 1, 1, 3, 1, 1, 1},
+	{1, 1, 2, 2, 2, 1, 2, 1, 1, 1, 3, 1, 1, 1}, {1, 2, 2, 1, 2, 1, 2, 1, 2, 1, 3, 1, 1, 1},
+	{1, 1, 2, 2, 2, 1, 2, 2, 1, 1, 3, 1, 1, 1}, {1, 2, 2, 1, 2, 2, 2, 1, 1, 1, 3, 1, 1, 1},
+	{1, 2, 2, 2, 2, 1, 2, 1, 1, 1, 3, 1, 1, 1}, {1, 1, 1, 1, 3, 1, 2, 1, 2, 2, 3, 1, 1, 1},
+	{1, 1, 1, 1, 3, 1, 2, 2, 2, 1, 3, 1, 1, 1}, {1, 1, 1, 2, 3, 1, 2, 1, 2, 1, 3, 1, 1, 1},
+	{1, 1, 1, 1, 3, 2, 2, 1, 2, 1, 3, 1, 1, 1},
+}
+
+// dbLtdChar returns the 14 elements of one DataBar Limited data character.
+func dbLtdChar(v int) []int {
+	g := 0
+	for g+1 < len(dbLtdGSum) && v >= dbLtdGSum[g+1] {
+		g++
+	}
+	v -= dbLtdGSum[g]
+	return interleave(
+		rssWidths(v/dbLtdTEven[g], dbLtdModulesOdd[g], 7, dbLtdWidestOdd[g], true),
+		rssWidths(v%dbLtdTEven[g], dbLtdModulesEven[g], 7, dbLtdWidestEven[g], false))
+}
+
+func encodeDataBarLimited(code string, opts *settings) (*Symbol, error) {
+	key, err := dataBarKey(code, opts, typeGS1DataBarLimited)
+	if err != nil {
+		return nil, err
+	}
+	accum, _ := strconv.ParseInt(key, 10, 64)
+	left := dbLtdChar(int(accum / 2013571))
+	right := dbLtdChar(int(accum % 2013571))
+	check := 0
+	for i := 0; i < 14; i++ {
+		check += dbLtdWeights[i]*left[i] + dbLtdWeights[i+14]*right[i]
+	}
+	bars := []int{0, 1, 1}
+	bars = append(bars, left...)
+	bars = append(bars, dbLtdChecks[check%89][:]...)
+	bars = append(bars, right...)
+	bars = append(bars, 1, 1)
+	return &Symbol{Bars: bars, Text: "(01)" + key + string(gs1CheckDigit(key))}, nil
+}
+
+// ─── GS1 DataBar Expanded ──────────────────────────────────────────────────
+
+var (
+	dbExpGSum        = [5]int{0, 348, 1388, 2948, 3988}
+	dbExpTEven       = [5]int{4, 20, 52, 104, 204}
+	dbExpModulesOdd  = [5]int{12, 10, 8, 6, 4}
+	dbExpModulesEven = [5]int{5, 7, 9, 11, 13}
+	dbExpWidestOdd   = [5]int{7, 5, 4, 3, 1}
+	dbExpWidestEven  = [5]int{2, 4, 5, 6, 8}
+	// dbExpFinders are the finder patterns A1, A1 reversed, B1, … F1
+	// reversed, numbered 1–12 in dbExpSequences.
+	dbExpFinders = [12][5]int{
+		{1, 8, 4, 1, 1}, {1, 1, 4, 8, 1}, {3, 6, 4, 1, 1}, {1, 1, 4, 6, 3},
+		{3, 4, 6, 1, 1}, {1, 1, 6, 4, 3}, {3, 2, 8, 1, 1}, {1, 1, 8, 2, 3},
+		{2, 6, 5, 1, 1}, {1, 1, 5, 6, 2}, {2, 2, 9, 1, 1}, {1, 1, 9, 2, 2},
+	}
+	// dbExpSequences lists the finder sequence for 2 to 11 finders.
+	dbExpSequences = [10][]int{
+		{1, 2},
+		{1, 4, 3},
+		{1, 6, 3, 8},
+		{1, 10, 3, 8, 5},
+		{1, 10, 3, 8, 7, 12},
+		{1, 10, 3, 8, 9, 12, 11},
+		{1, 2, 3, 4, 5, 6, 7, 8},
+		{1, 2, 3, 4, 5, 6, 7, 10, 9},
+		{1, 2, 3, 4, 5, 6, 7, 10, 11, 12},
+		{1, 2, 3, 4, 5, 8, 7, 10, 9, 12, 11},
+	}
+)
+
+const (
+	// dbExpMaxBits is the data capacity: 21 data characters of 12 bits.
+	dbExpMaxBits = 252
+	// dbExpRowHeight is the height of a row of stacked DataBar Expanded.
+	dbExpRowHeight = 34
+)
+
+// dbExpWeight returns checksum weight j of weight row r: successive powers
+// of 3 modulo 211.
+func dbExpWeight(r, j int) int {
+	w := 1
+	for i := 0; i < 8*r+j; i++ {
+		w = w * 3 % 211
+	}
+	return w
+}
+
+// dbExpChar returns the 8 elements of a 12-bit DataBar Expanded character.
+func dbExpChar(v int) []int {
+	g := 0
+	for g+1 < len(dbExpGSum) && v >= dbExpGSum[g+1] {
+		g++
+	}
+	v -= dbExpGSum[g]
+	return interleave(
+		rssWidths(v/dbExpTEven[g], dbExpModulesOdd[g], 4, dbExpWidestOdd[g], false),
+		rssWidths(v%dbExpTEven[g], dbExpModulesEven[g], 4, dbExpWidestEven[g], true))
+}
+
+// General purpose field encodation modes.
+const (
+	dbModeNumeric = iota
+	dbModeAlpha
+	dbModeISO646
+)
+
+// dbISO646Specials are the 8-bit ISO/IEC 646 mode characters, valued from
+// 232 up.
+const dbISO646Specials = "!\"%&'()*+,-./:;<=>?_ "
+
+func dbIsNumeric(r rune) bool { return isDigit(r) || r == runeFNC1 }
+
+func dbIsAlpha(r rune) bool {
+	return dbIsNumeric(r) || r >= 'A' && r <= 'Z' || strings.ContainsRune("*,-./", r)
+}
+
+func dbIsISO646(r rune) bool {
+	return dbIsAlpha(r) || r >= 'a' && r <= 'z' || strings.ContainsRune(dbISO646Specials, r)
+}
+
+// dbNumericAhead reports whether a latch to numeric mode pays off at i: at
+// least 6 numeric characters follow, or 4 or more end the data.
+func dbNumericAhead(data []rune, i int) bool {
+	n := 0
+	for i+n < len(data) && dbIsNumeric(data[i+n]) {
+		n++
+	}
+	return n >= 6 || n >= 4 && i+n == len(data)
+}
+
+// dbAlphaAhead reports whether a latch from ISO/IEC 646 to alphanumeric
+// mode pays off at i.
+func dbAlphaAhead(data []rune, i int) bool {
+	n := 0
+	for i+n < len(data) && dbIsAlpha(data[i+n]) {
+		n++
+	}
+	return n >= 10 || n >= 5 && i+n == len(data)
+}
+
+// dbExpGeneral appends the general purpose field for data and returns the
+// final mode. A lone final digit in numeric mode is written last by the
+// caller, which knows the symbol size.
+func dbExpGeneral(bits *bitBuffer, data []rune) (mode int, lastDigit rune, err error) {
+	mode = dbModeNumeric
+	for i := 0; i < len(data); {
+		r := data[i]
+		switch mode {
+		case dbModeNumeric:
+			if i+1 < len(data) && dbIsNumeric(r) && dbIsNumeric(data[i+1]) && (r != runeFNC1 || data[i+1] != runeFNC1) {
+				bits.appendBits(11*dbNumericValue(r)+dbNumericValue(data[i+1])+8, 7)
+				i += 2
+				continue
+			}
+			if i == len(data)-1 && isDigit(r) {
+				return mode, r, nil
+			}
+			bits.appendBits(0, 4)
+			mode = dbModeAlpha
+			continue
+		case dbModeAlpha:
+			if dbNumericAhead(data, i) {
+				bits.appendBits(0, 3)
+				mode = dbModeNumeric
+				continue
+			}
+			if !dbIsAlpha(r) {
+				if !dbIsISO646(r) {
+					return mode, 0, errInvalidChar(i, r, "not encodable in GS1 DataBar Expanded")
+				}
+				bits.appendBits(4, 5)
+				mode = dbModeISO646
+				continue
+			}
+			switch {
+			case r == runeFNC1:
+				bits.appendBits(15, 5)
+			case isDigit(r):
+				bits.appendBits(int(r-'0')+5, 5)
+			case r >= 'A' && r <= 'Z':
+				bits.appendBits(int(r-'A')+32, 6)
+			default:
+				bits.appendBits(strings.IndexRune("*,-./", r)+58, 6)
+			}
+		case dbModeISO646:
+			if dbNumericAhead(data, i) {
+				bits.appendBits(0, 3)
+				mode = dbModeNumeric
+				continue
+			}
+			if dbAlphaAhead(data, i) {
+				bits.appendBits(4, 5)
+				mode = dbModeAlpha
+				continue
+			}
+			switch {
+			case r == runeFNC1:
+				bits.appendBits(15, 5)
+			case isDigit(r):
+				bits.appendBits(int(r-'0')+5, 5)
+			case r >= 'A' && r <= 'Z':
+				bits.appendBits(int(r-'A')+64, 7)
+			case r >= 'a' && r <= 'z':
+				bits.appendBits(int(r-'a')+90, 7)
+			case strings.ContainsRune(dbISO646Specials, r):
+				bits.appendBits(strings.IndexRune(dbISO646Specials, r)+232, 8)
+			default:
+				return mode, 0, errInvalidChar(i, r, "not encodable in GS1 DataBar Expanded")
+			}
+		}
+		i++
+	}
+	return mode, 0, nil
+}
+
+func dbNumericValue(r rune) int {
+	if r == runeFNC1 {
+		return 10
+	}
+	return int(r - '0')
+}
+
+// dbExpBits encodes a GS1 element string into the DataBar Expanded binary
+// string. Data starting with AI (01) uses encodation method 1; anything
+// else uses the general purpose method 2. stackedCols is the number of
+// segment pairs per row, or 0 for the unstacked symbol.
+func dbExpBits(data []rune, stackedCols int) (bitBuffer, error) {
+	var bits bitBuffer
+	bits.appendBits(0, 1) // linkage flag: no composite component
+	rest := data
+	method1 := len(data) >= 16 && string(data[:2]) == "01" && strings.Trim(string(data[2:16]), digitChars) == ""
+	if method1 {
+		gtin := string(data[2:16])
+		if err := validateCheckDigit(gtin[:13], gtin[13], 15); err != nil {
+			return nil, err
+		}
+		bits.appendBits(1, 1)
+		bits.appendBits(0, 2) // variable length field, set below
+		bits.appendBits(int(gtin[0]-'0'), 4)
+		for i := 1; i < 13; i += 3 {
+			v, _ := strconv.Atoi(gtin[i : i+3])
+			bits.appendBits(v, 10)
+		}
+		rest = data[16:]
+	} else {
+		bits.appendBits(0, 2)
+		bits.appendBits(0, 2) // variable length field, set below
+	}
+	lengthAt := 2
+	if !method1 {
+		lengthAt = 3
+	}
+
+	mode, lastDigit, err := dbExpGeneral(&bits, rest)
+	if err != nil {
+		return nil, err
+	}
+	remainder := func() int {
+		if len(bits) < 36 {
+			return 36 - len(bits)
+		}
+		return (12 - len(bits)%12) % 12
+	}
+	// A stacked symbol may not end with a row of one character.
+	oneCharRow := func(n int) bool {
+		return stackedCols > 0 && n/12%(2*stackedCols) == 0
+	}
+	if lastDigit != 0 {
+		// The short 4-bit form is only read back when no further
+		// padding follows it.
+		if r := remainder(); r >= 4 && r <= 6 && !oneCharRow(len(bits)+r) {
+			bits.appendBits(int(lastDigit-'0')+1, 4)
+		} else {
+			bits.appendBits(11*int(lastDigit-'0')+10+8, 7)
+		}
+	}
+
+	pad := remainder()
+	if oneCharRow(len(bits) + pad) {
+		pad += 12
+	}
+	if len(bits)+pad > dbExpMaxBits {
+		return nil, errDraw(ReasonDataTooLong, "data needs %d bits, GS1 DataBar Expanded holds %d", len(bits)+pad, dbExpMaxBits)
+	}
+	var padding bitBuffer
+	if mode == dbModeNumeric {
+		padding.appendBits(0, 4)
+	}
+	for len(padding) < pad {
+		padding.appendBits(4, 5)
+	}
+	bits = append(bits, padding[:pad]...)
+
+	symChars := len(bits)/12 + 1
+	bits[lengthAt] = symChars%2 != 0
+	bits[lengthAt+1] = len(bits) > 156
+	return bits, nil
+}
+
+// dataBarExpElements lays out a DataBar Expanded symbol: guards, the check
+// character and data characters in pairs around finder patterns. It also
+// returns the element index of every finder pattern.
+func dataBarExpElements(bits bitBuffer) (elems []int, finders []int) {
+	var chars [][]int
+	for i := 0; i < len(bits); i += 12 {
+		v := 0
+		for _, b := range bits[i : i+12] {
+			v <<= 1
+			if b {
+				v |= 1
+			}
+		}
+		chars = append(chars, dbExpChar(v))
+	}
+	dataChars := len(chars)
+	nFinders := (dataChars + 2) / 2
+	seq := dbExpSequences[nFinders-2]
+
+	// The weight row of a character follows from the finder it sits next
+	// to: finder k has row 2k-3 on its left and 2k-2 on its right.
+	sum := 0
+	for i, c := range chars {
+		p := i + 1
+		row := 2*seq[p/2] - 3 + p%2
+		for j, w := range c {
+			sum += w * dbExpWeight(row, j)
+		}
+	}
+	check := dbExpChar(211*(dataChars+1-4) + sum%211)
+
+	elems = make([]int, 4+8*(dataChars+1)+5*nFinders)
+	elems[0], elems[1] = 1, 1
+	elems[len(elems)-2], elems[len(elems)-1] = 1, 1
+	copy(elems[2:], check)
+	for i, k := range seq {
+		copy(elems[21*i+10:], dbExpFinders[k-1][:])
+		finders = append(finders, 21*i+10)
+	}
+	for i, c := range chars {
+		if i%2 == 0 {
+			copy(elems[i/2*21+15:], reversed(c))
+		} else {
+			copy(elems[(i-1)/2*21+23:], c)
+		}
+	}
+	return elems, finders
+}
+
+func encodeDataBarExpanded(code string, opts *settings) (*Symbol, error) {
+	data, text, _, err := gs1Data(code)
+	if err != nil {
+		return nil, err
+	}
+	stacked := false
+	switch strings.ToUpper(opts.symbolTypeExp) {
+	case "", "UNSTACKED":
+	case "STACKED":
+		stacked = true
+		if opts.expColumns < 1 || opts.expColumns > 11 {
+			return nil, errDraw(ReasonInvalidOption, "number of columns must be 1 to 11, got %d", opts.expColumns)
+		}
+	default:
+		return nil, errDraw(ReasonInvalidOption, "unknown symbol type %q", opts.symbolTypeExp)
+	}
+	cols := 0
+	if stacked {
+		cols = opts.expColumns
+	}
+	bits, err := dbExpBits(data, cols)
+	if err != nil {
+		return nil, err
+	}
+	elems, finders := dataBarExpElements(bits)
+	if !stacked || len(finders) <= cols {
+		return &Symbol{Bars: append([]int{0}, elems...), Text: text}, nil
+	}
+	return dataBarExpStacked(elems, finders, cols, text), nil
+}
+
+// dataBarExpStacked splits a DataBar Expanded symbol into rows of cols
+// segment pairs, joined by separator patterns. With an even number of
+// columns, every second row reads right to left.
+func dataBarExpStacked(elems, finders []int, cols int, text string) *Symbol {
+	type row struct {
+		mods    []bool
+		finders [][2]int
+	}
+	var rows []row
+	width := 0
+	blocks := len(finders)
+	for b0 := 0; b0 < blocks; b0 += cols {
+		b1 := b0 + cols
+		if b1 > blocks {
+			b1 = blocks
+		}
+		start, end := 2+21*b0, 2+21*b1
+		if end > len(elems)-2 {
+			end = len(elems) - 2
+		}
+		seg := append([]int(nil), elems[start:end]...)
+		inFinder := make([]bool, len(seg))
+		for _, f := range finders[b0:b1] {
+			for j := f; j < f+5; j++ {
+				inFinder[j-start] = true
+			}
+		}
+		// Element colours are fixed by their index in the whole symbol:
+		// even indices are spaces.
+		darkFirst := start%2 != 0
+		r := len(rows)
+		if cols%2 == 0 && r%2 == 1 {
+			seg = reversed(seg)
+			for i, j := 0, len(inFinder)-1; i < j; i, j = i+1, j-1 {
+				inFinder[i], inFinder[j] = inFinder[j], inFinder[i]
+			}
+			darkFirst = darkFirst != ((len(seg)-1)%2 != 0)
+		}
+		lead := []int{1, 1}
+		if r == (blocks-1)/cols && b1-b0 < cols && cols%2 == 0 && r%2 == 1 && !darkFirst {
+			// A short reversed last row starting with a space is shifted
+			// one module right.
+			lead[0] = 2
+		}
+		var rw row
+		dark := darkFirst
+		add := func(w int, finder bool) {
+			if finder && (len(rw.finders) == 0 || rw.finders[len(rw.finders)-1][1] != len(rw.mods)) {
+				rw.finders = append(rw.finders, [2]int{len(rw.mods), len(rw.mods)})
+			}
+			for i := 0; i < w; i++ {
+				rw.mods = append(rw.mods, dark)
+			}
+			if finder {
+				rw.finders[len(rw.finders)-1][1] = len(rw.mods)
+			}
+			dark = !dark
+		}
+		add(lead[0], false)
+		add(lead[1], false)
+		for i, w := range seg {
+			add(w, inFinder[i])
+		}
+		add(1, false)
+		add(1, false)
+		if len(rw.mods) > width {
+			width = len(rw.mods)
+		}
+		rows = append(rows, rw)
+	}
+
+	var grid [][]bool
+	var heights []int
+	line := func(mods []bool, h int) {
+		full := make([]bool, width)
+		copy(full, mods)
+		grid = append(grid, full)
+		heights = append(heights, h)
+	}
+	for i, rw := range rows {
+		if i > 0 {
+			mid := make([]bool, width)
+			for j := 5; j < 49*cols && j < width-4; j += 2 {
+				mid[j] = true
+			}
+			line(mid, 1)
+			line(dataBarSeparator(rw.mods, rw.finders), 1)
+		}
+		line(rw.mods, dbExpRowHeight)
+		if i < len(rows)-1 {
+			line(dataBarSeparator(rw.mods, rw.finders), 1)
+		}
+	}
+	return &Symbol{Modules: grid, RowHeights: heights, QuietZone: 1, Text: text}
+}
@@ -0,0 +1,818 @@
+package barcode_pao
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ─── DataMatrix ECC200 ─────────────────────────────────────────────────────
+
+// dmSize describes one ECC200 symbol size: overall rows and columns, the
+// size of each data region, and its data and error correction capacity.
+type dmSize struct {
+	rows, cols             int
+	regionRows, regionCols int
+	data, ecc, blocks      int
+}
+
+func (s *dmSize) name() string { return fmt.Sprintf("%dx%d", s.rows, s.cols) }
+
+// dmSizes lists the square sizes, smallest first, then the rectangular
+// ones. AUTO picks the smallest square size that holds the data.
+var dmSizes = [...]dmSize{
+	{10, 10, 8, 8, 3, 5, 1},
+	{12, 12, 10, 10, 5, 7, 1},
+	{14, 14, 12, 12, 8, 10, 1},
+	{16, 16, 14, 14, 12, 12, 1},
+	{18, 18, 16, 16, 18, 14, 1},
+	{20, 20, 18, 18, 22, 18, 1},
+	{22, 22, 20, 20, 30, 20, 1},
+	{24, 24, 22, 22, 36, 24, 1},
+	{26, 26, 24, 24, 44, 28, 1},
+	{32, 32, 14, 14, 62, 36, 1},
+	{36, 36, 16, 16, 86, 42, 1},
+	{40, 40, 18, 18, 114, 48, 1},
+	{44, 44, 20, 20, 144, 56, 1},
+	{48, 48, 22, 22, 174, 68, 1},
+	{52, 52, 24, 24, 204, 84, 2},
+	{64, 64, 14, 14, 280, 112, 2},
+	{72, 72, 16, 16, 368, 144, 4},
+	{80, 80, 18, 18, 456, 192, 4},
+	{88, 88, 20, 20, 576, 224, 4},
+	{96, 96, 22, 22, 696, 272, 4},
+	{104, 104, 24, 24, 816, 336, 6},
+	{120, 120, 18, 18, 1050, 408, 6},
+	{132, 132, 20, 20, 1304, 496, 8},
+	{144, 144, 22, 22, 1558, 620, 10},
+	{8, 18, 6, 16, 5, 7, 1},
+	{8, 32, 6, 14, 10, 11, 1},
+	{12, 26, 10, 24, 16, 14, 1},
+	{12, 36, 10, 16, 22, 18, 1},
+	{16, 36, 14, 16, 32, 24, 1},
+	{16, 48, 14, 22, 49, 28, 1},
+}
+
+const dmSquareSizes = 24
+
+// Encodation schemes, in the order of dmSchemeNames.
+const (
+	dmASCII = iota
+	dmC40
+	dmText
+	dmX12
+	dmEDIFACT
+	dmBase256
+)
+
+var dmSchemeNames = [...]string{"ASCII", "C40", "TEXT", "X12", "EDIFACT", "BASE256"}
+
+// dmLatch holds the latch codeword of each scheme, from ASCII.
+var dmLatch = [...]byte{0, 230, 239, 238, 240, 231}
+
+const (
+	dmPad            = 129
+	dmUpperShift     = 235
+	dmUnlatch        = 254
+	dmEdifactUnlatch = 31
+	dmDigitPairs     = 130
+	dmASCIIOffset    = 1
+	dmFNC1           = 232
+)
+
+// dmEncoder converts data to codewords. With auto set it switches schemes
+// following the look-ahead test of ISO/IEC 16022 Annex P.
+type dmEncoder struct {
+	msg   []byte
+	pos   int
+	cw    []byte
+	auto  bool
+	fixed *dmSize
+	// minLen is the least symbol capacity the codewords need: an EDIFACT
+	// unlatch must be followed by at least three codewords.
+	minLen int
+}
+
+// capacity returns the data capacity of the symbol that holds n codewords,
+// or -1 if none does.
+func (e *dmEncoder) capacity(n int) int {
+	if n < e.minLen {
+		n = e.minLen
+	}
+	if e.fixed != nil {
+		if n > e.fixed.data {
+			return -1
+		}
+		return e.fixed.data
+	}
+	for _, s := range dmSizes[:dmSquareSizes] {
+		if s.data >= n {
+			return s.data
+		}
+	}
+	return -1
+}
+
+// fillsSymbol reports whether n codewords fill their symbol exactly.
+func (e *dmEncoder) fillsSymbol(n int) bool { return e.capacity(n) == n }
+
+func (e *dmEncoder) encode(scheme int) error {
+	mode := dmASCII
+	if !e.auto && scheme != dmASCII {
+		mode = scheme
+		e.cw = append(e.cw, dmLatch[scheme])
+	}
+	for e.pos < len(e.msg) {
+		var err error
+		switch mode {
+		case dmASCII:
+			mode = e.ascii()
+		case dmC40, dmText:
+			mode = e.c40(mode)
+		case dmX12:
+			mode, err = e.x12()
+		case dmEDIFACT:
+			mode, err = e.edifact()
+		case dmBase256:
+			mode = e.base256()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *dmEncoder) ascii() int {
+	c := e.msg[e.pos]
+	if e.pos+1 < len(e.msg) && isDigit(rune(c)) && isDigit(rune(e.msg[e.pos+1])) {
+		e.cw = append(e.cw, dmDigitPairs+(c-'0')*10+e.msg[e.pos+1]-'0')
+		e.pos += 2
+		return dmASCII
+	}
+	if e.auto {
+		if m := dmLookAhead(e.msg, e.pos, dmASCII); m != dmASCII {
+			e.cw = append(e.cw, dmLatch[m])
+			return m
+		}
+	}
+	e.cw = append(e.cw, dmASCIICodewords(e.msg[e.pos:e.pos+1])...)
+	e.pos++
+	return dmASCII
+}
+
+// dmASCIICodewords encodes data in ASCII encodation.
+func dmASCIICodewords(data []byte) []byte {
+	var cw []byte
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case i+1 < len(data) && isDigit(rune(c)) && isDigit(rune(data[i+1])):
+			cw = append(cw, dmDigitPairs+(c-'0')*10+data[i+1]-'0')
+			i++
+		case c >= 128:
+			cw = append(cw, dmUpperShift, c-128+dmASCIIOffset)
+		default:
+			cw = append(cw, c+dmASCIIOffset)
+		}
+	}
+	return cw
+}
+
+// dmGS1Codewords encodes a GS1 element string in ASCII encodation, with
+// FNC1 in first position and as separator. Other schemes cannot carry the
+// separators, and the mostly numeric GS1 data packs into digit pairs.
+func dmGS1Codewords(code string, scheme int) ([]byte, error) {
+	if scheme > dmASCII {
+		return nil, errDraw(ReasonInvalidOption, "GS1 mode needs the AUTO or ASCII encode scheme, not %s", dmSchemeNames[scheme])
+	}
+	data, err := gs1Message(code)
+	if err != nil {
+		return nil, err
+	}
+	cw := []byte{dmFNC1}
+	start := 0
+	for i := 0; i <= len(data); i++ {
+		if i < len(data) && data[i] != runeFNC1 {
+			continue
+		}
+		cw = append(cw, dmASCIICodewords([]byte(string(data[start:i])))...)
+		if i < len(data) {
+			cw = append(cw, dmFNC1)
+		}
+		start = i + 1
+	}
+	return cw, nil
+}
+
+// dmC40Values returns the C40 (or Text) values for one byte, with shifts.
+func dmC40Values(c byte, text bool) []byte {
+	if c >= 128 {
+		return append([]byte{1, 30}, dmC40Values(c-128, text)...)
+	}
+	upper, lower := c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z'
+	switch {
+	case c == ' ':
+		return []byte{3}
+	case c >= '0' && c <= '9':
+		return []byte{c - '0' + 4}
+	case upper && !text:
+		return []byte{c - 'A' + 14}
+	case lower && text:
+		return []byte{c - 'a' + 14}
+	case upper:
+		return []byte{2, c - 'A' + 1}
+	case lower:
+		return []byte{2, c - 'a' + 1}
+	case c < 32:
+		return []byte{0, c}
+	case c <= '/':
+		return []byte{1, c - '!'}
+	case c <= '@':
+		return []byte{1, c - ':' + 15}
+	case c <= '_':
+		return []byte{1, c - '[' + 22}
+	case c == '`':
+		return []byte{2, 0}
+	}
+	return []byte{2, c - '{' + 27}
+}
+
+// dmX12Value returns the X12 value of c, or -1.
+func dmX12Value(c byte) int {
+	switch {
+	case c == '\r':
+		return 0
+	case c == '*':
+		return 1
+	case c == '>':
+		return 2
+	case c == ' ':
+		return 3
+	case c >= '0' && c <= '9':
+		return int(c-'0') + 4
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 14
+	}
+	return -1
+}
+
+func (e *dmEncoder) writeTriplets(vals []byte) {
+	for i := 0; i+2 < len(vals); i += 3 {
+		v := 1600*int(vals[i]) + 40*int(vals[i+1]) + int(vals[i+2]) + 1
+		e.cw = append(e.cw, byte(v/256), byte(v%256))
+	}
+}
+
+func (e *dmEncoder) c40(mode int) int {
+	var vals []byte
+	var sizes []int
+	for e.pos < len(e.msg) {
+		v := dmC40Values(e.msg[e.pos], mode == dmText)
+		vals = append(vals, v...)
+		sizes = append(sizes, len(v))
+		e.pos++
+		if len(vals)%3 == 0 {
+			e.writeTriplets(vals)
+			vals, sizes = nil, nil
+			if e.auto && dmLookAhead(e.msg, e.pos, mode) != mode {
+				break
+			}
+		}
+	}
+	e.endTriplets(vals, sizes, true)
+	return dmASCII
+}
+
+func (e *dmEncoder) x12() (int, error) {
+	var vals []byte
+	var sizes []int
+	for e.pos < len(e.msg) {
+		v := dmX12Value(e.msg[e.pos])
+		if v < 0 {
+			if !e.auto {
+				return 0, errInvalidChar(e.pos, rune(e.msg[e.pos]), "not encodable in X12")
+			}
+			break
+		}
+		vals = append(vals, byte(v))
+		sizes = append(sizes, 1)
+		e.pos++
+		if len(vals)%3 == 0 {
+			e.writeTriplets(vals)
+			vals, sizes = nil, nil
+			if e.auto && dmLookAhead(e.msg, e.pos, dmX12) != dmX12 {
+				break
+			}
+		}
+	}
+	e.endTriplets(vals, sizes, false)
+	return dmASCII, nil
+}
+
+// endTriplets closes a C40, Text or X12 segment. Values that do not make up
+// a whole triplet go back to ASCII, except that two C40 values are padded
+// with Shift 1. The unlatch is left out when the symbol is full, or when a
+// single ASCII codeword fills it.
+func (e *dmEncoder) endTriplets(vals []byte, sizes []int, padShift bool) {
+	for len(vals)%3 == 1 || !padShift && len(vals)%3 != 0 {
+		n := sizes[len(sizes)-1]
+		vals, sizes = vals[:len(vals)-n], sizes[:len(sizes)-1]
+		e.pos--
+	}
+	if len(vals)%3 == 2 {
+		vals = append(vals, 0)
+	}
+	e.writeTriplets(vals)
+	rest := dmASCIICodewords(e.msg[e.pos:])
+	switch {
+	case len(rest) == 0 && e.fillsSymbol(len(e.cw)):
+	case len(rest) == 1 && e.fillsSymbol(len(e.cw)+1):
+		e.cw = append(e.cw, rest...)
+		e.pos = len(e.msg)
+	default:
+		e.cw = append(e.cw, dmUnlatch)
+	}
+}
+
+func (e *dmEncoder) writeEdifact(vals []byte) {
+	v := 0
+	for i := 0; i < 4; i++ {
+		v <<= 6
+		if i < len(vals) {
+			v |= int(vals[i])
+		}
+	}
+	n := (6*len(vals) + 7) / 8
+	e.cw = append(e.cw, byte(v>>16), byte(v>>8), byte(v))[:len(e.cw)+n]
+}
+
+func (e *dmEncoder) edifact() (int, error) {
+	var vals []byte
+	for e.pos < len(e.msg) {
+		c := e.msg[e.pos]
+		if c < 32 || c > 94 {
+			if !e.auto {
+				return 0, errInvalidChar(e.pos, rune(c), "not encodable in EDIFACT")
+			}
+			break
+		}
+		vals = append(vals, c&0x3F)
+		e.pos++
+		if len(vals) == 4 {
+			e.writeEdifact(vals)
+			vals = nil
+			if e.auto && dmLookAhead(e.msg, e.pos, dmEDIFACT) != dmEDIFACT {
+				break
+			}
+		}
+	}
+
+	// At the end of data, up to two codewords left in the symbol are read
+	// as ASCII without an unlatch.
+	if e.pos == len(e.msg) {
+		rest := dmASCIICodewords(e.msg[e.pos-len(vals):])
+		if n := len(e.cw) + len(rest); len(rest) <= 2 && e.capacity(n)-len(e.cw) <= 2 {
+			e.pos -= len(vals)
+			return dmASCII, nil
+		}
+	}
+	e.minLen = len(e.cw) + 3
+	e.writeEdifact(append(vals, dmEdifactUnlatch))
+	return dmASCII, nil
+}
+
+func (e *dmEncoder) base256() int {
+	start := e.pos
+	for e.pos < len(e.msg) {
+		e.pos++
+		if e.auto && dmLookAhead(e.msg, e.pos, dmBase256) != dmBase256 {
+			break
+		}
+	}
+	n := e.pos - start
+	var field []byte
+	if n <= 249 {
+		field = []byte{byte(n)}
+	} else {
+		field = []byte{byte(249 + n/250), byte(n % 250)}
+	}
+	for _, b := range append(field, e.msg[start:e.pos]...) {
+		r := 149*(len(e.cw)+1)%255 + 1
+		e.cw = append(e.cw, byte((int(b)+r)%256))
+	}
+	return dmASCII
+}
+
+// dmLookAhead returns the scheme to continue with at pos, following the
+// look-ahead test of ISO/IEC 16022 Annex P.
+func dmLookAhead(msg []byte, pos, mode int) int {
+	if pos >= len(msg) {
+		return mode
+	}
+	counts := [6]float64{0, 1, 1, 1, 1, 1.25}
+	if mode != dmASCII {
+		counts = [6]float64{1, 2, 2, 2, 2, 2.25}
+		counts[mode] = 0
+	}
+	for n := 1; ; n++ {
+		c := msg[pos+n-1]
+		ext := c >= 128
+		switch {
+		case isDigit(rune(c)):
+			counts[dmASCII] += 0.5
+		case ext:
+			counts[dmASCII] = math.Ceil(counts[dmASCII]) + 2
+		default:
+			counts[dmASCII] = math.Ceil(counts[dmASCII]) + 1
+		}
+		native := [...]bool{
+			dmC40:     c == ' ' || isDigit(rune(c)) || c >= 'A' && c <= 'Z',
+			dmText:    c == ' ' || isDigit(rune(c)) || c >= 'a' && c <= 'z',
+			dmX12:     dmX12Value(c) >= 0,
+			dmEDIFACT: c >= 32 && c <= 94,
+		}
+		for _, m := range []int{dmC40, dmText} {
+			switch {
+			case native[m]:
+				counts[m] += 2.0 / 3
+			case ext:
+				counts[m] += 8.0 / 3
+			default:
+				counts[m] += 4.0 / 3
+			}
+		}
+		switch {
+		case native[dmX12]:
+			counts[dmX12] += 2.0 / 3
+		case ext:
+			counts[dmX12] += 13.0 / 3
+		default:
+			counts[dmX12] += 10.0 / 3
+		}
+		switch {
+		case native[dmEDIFACT]:
+			counts[dmEDIFACT] += 3.0 / 4
+		case ext:
+			counts[dmEDIFACT] += 17.0 / 4
+		default:
+			counts[dmEDIFACT] += 13.0 / 4
+		}
+		counts[dmBase256]++
+
+		var ic [6]int
+		for i, v := range counts {
+			ic[i] = int(math.Ceil(v))
+		}
+		min := ic[0]
+		for _, v := range ic {
+			if v < min {
+				min = v
+			}
+		}
+		var isMin [6]bool
+		nMin := 0
+		for i, v := range ic {
+			if v == min {
+				isMin[i] = true
+				nMin++
+			}
+		}
+
+		if pos+n == len(msg) {
+			switch {
+			case isMin[dmASCII]:
+				return dmASCII
+			case nMin == 1 && isMin[dmBase256]:
+				return dmBase256
+			case nMin == 1 && isMin[dmEDIFACT]:
+				return dmEDIFACT
+			case nMin == 1 && isMin[dmText]:
+				return dmText
+			case nMin == 1 && isMin[dmX12]:
+				return dmX12
+			}
+			return dmC40
+		}
+		if n < 4 {
+			continue
+		}
+		// beats reports whether scheme m, plus extra codewords, is below
+		// every other scheme except skip.
+		beats := func(m, extra, skip int) bool {
+			for i, v := range ic {
+				if i != m && i != skip && ic[m]+extra >= v {
+					return false
+				}
+			}
+			return true
+		}
+		switch {
+		case beats(dmASCII, 0, -1):
+			return dmASCII
+		case ic[dmBase256] < ic[dmASCII] || !isMin[dmC40] && !isMin[dmText] && !isMin[dmX12] && !isMin[dmEDIFACT]:
+			return dmBase256
+		case nMin == 1 && isMin[dmEDIFACT]:
+			return dmEDIFACT
+		case nMin == 1 && isMin[dmText]:
+			return dmText
+		case nMin == 1 && isMin[dmX12]:
+			return dmX12
+		case beats(dmC40, 1, dmX12):
+			if ic[dmC40] < ic[dmX12] {
+				return dmC40
+			}
+			if ic[dmC40] == ic[dmX12] {
+				// Prefer X12 if an X12 terminator follows within native
+				// X12 characters.
+				for p := pos + n + 1; p < len(msg) && dmX12Value(msg[p]) >= 0; p++ {
+					if msg[p] == '\r' || msg[p] == '*' || msg[p] == '>' {
+						return dmX12
+					}
+				}
+				return dmC40
+			}
+		}
+	}
+}
+
+// ─── Reed-Solomon over GF(256), polynomial 0x12D ───────────────────────────
+
+var dmExp, dmLog = func() (exp [255]byte, log [256]int) {
+	v := 1
+	for i := 0; i < 255; i++ {
+		exp[i] = byte(v)
+		log[v] = i
+		v <<= 1
+		if v >= 256 {
+			v ^= 0x12D
+		}
+	}
+	return
+}()
+
+func dmMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return dmExp[(dmLog[a]+dmLog[b])%255]
+}
+
+// dmECC returns n error correction codewords for data.
+func dmECC(data []byte, n int) []byte {
+	// Generator polynomial (x - a^1)(x - a^2)…(x - a^n), highest degree
+	// first, leading coefficient omitted.
+	gen := make([]byte, n)
+	gen[n-1] = 1
+	poly := []byte{1}
+	for i := 1; i <= n; i++ {
+		next := make([]byte, len(poly)+1)
+		for j, c := range poly {
+			next[j] ^= c
+			next[j+1] ^= dmMul(c, dmExp[i%255])
+		}
+		poly = next
+	}
+	copy(gen, poly[1:])
+
+	ecc := make([]byte, n)
+	for _, d := range data {
+		f := d ^ ecc[0]
+		copy(ecc, ecc[1:])
+		ecc[n-1] = 0
+		for j := range ecc {
+			ecc[j] ^= dmMul(gen[j], f)
+		}
+	}
+	return ecc
+}
+
+// dmAddECC interleaves data over the blocks of size s and appends their
+// error correction codewords. In 144x144 the first eight blocks are one
+// codeword longer than the last two, and readers expect the error
+// correction interleave rotated by two blocks to match.
+func dmAddECC(data []byte, s *dmSize) []byte {
+	eccPerBlock := s.ecc / s.blocks
+	out := make([]byte, s.data+s.ecc)
+	copy(out, data)
+	skew := s.rows == 144
+	for b := 0; b < s.blocks; b++ {
+		var block []byte
+		for i := b; i < len(data); i += s.blocks {
+			block = append(block, data[i])
+		}
+		slot := b
+		if skew {
+			slot = (b + 2) % s.blocks
+		}
+		for j, c := range dmECC(block, eccPerBlock) {
+			out[s.data+j*s.blocks+slot] = c
+		}
+	}
+	return out
+}
+
+// ─── Module placement (ISO/IEC 16022 Annex F) ──────────────────────────────
+
+type dmPlacer struct {
+	rows, cols int
+	bits       [][]bool
+	set        [][]bool
+	cw         []byte
+}
+
+func (p *dmPlacer) module(row, col, pos, bit int) {
+	if row < 0 {
+		row += p.rows
+		col += 4 - (p.rows+4)%8
+	}
+	if col < 0 {
+		col += p.cols
+		row += 4 - (p.cols+4)%8
+	}
+	p.set[row][col] = true
+	p.bits[row][col] = p.cw[pos]&(1<<(8-bit)) != 0
+}
+
+func (p *dmPlacer) utah(row, col, pos int) {
+	p.module(row-2, col-2, pos, 1)
+	p.module(row-2, col-1, pos, 2)
+	p.module(row-1, col-2, pos, 3)
+	p.module(row-1, col-1, pos, 4)
+	p.module(row-1, col, pos, 5)
+	p.module(row, col-2, pos, 6)
+	p.module(row, col-1, pos, 7)
+	p.module(row, col, pos, 8)
+}
+
+// corner places a codeword in one of the four special corner shapes, given
+// as (row, col) pairs relative to the bottom (negative rows) and right
+// (negative cols) edges.
+func (p *dmPlacer) corner(pos int, cells [8][2]int) {
+	for i, c := range cells {
+		r, col := c[0], c[1]
+		if r < 0 {
+			r += p.rows
+		}
+		if col < 0 {
+			col += p.cols
+		}
+		p.module(r, col, pos, i+1)
+	}
+}
+
+var dmCorners = [4][8][2]int{
+	{{-1, 0}, {-1, 1}, {-1, 2}, {0, -2}, {0, -1}, {1, -1}, {2, -1}, {3, -1}},
+	{{-3, 0}, {-2, 0}, {-1, 0}, {0, -4}, {0, -3}, {0, -2}, {0, -1}, {1, -1}},
+	{{-3, 0}, {-2, 0}, {-1, 0}, {0, -2}, {0, -1}, {1, -1}, {2, -1}, {3, -1}},
+	{{-1, 0}, {-1, -1}, {0, -3}, {0, -2}, {0, -1}, {1, -3}, {1, -2}, {1, -1}},
+}
+
+// dmPlace maps codewords into the rows × cols data area.
+func dmPlace(cw []byte, rows, cols int) [][]bool {
+	p := &dmPlacer{rows: rows, cols: cols, bits: newGrid(rows, cols), set: newGrid(rows, cols), cw: cw}
+	pos, row, col := 0, 4, 0
+	for row < rows || col < cols {
+		switch {
+		case row == rows && col == 0:
+			p.corner(pos, dmCorners[0])
+			pos++
+		case row == rows-2 && col == 0 && cols%4 != 0:
+			p.corner(pos, dmCorners[1])
+			pos++
+		case row == rows-2 && col == 0 && cols%8 == 4:
+			p.corner(pos, dmCorners[2])
+			pos++
+		case row == rows+4 && col == 2 && cols%8 == 0:
+			p.corner(pos, dmCorners[3])
+			pos++
+		}
+		for {
+			if row < rows && col >= 0 && !p.set[row][col] {
+				p.utah(row, col, pos)
+				pos++
+			}
+			row -= 2
+			col += 2
+			if row < 0 || col >= cols {
+				break
+			}
+		}
+		row++
+		col += 3
+		for {
+			if row >= 0 && col < cols && !p.set[row][col] {
+				p.utah(row, col, pos)
+				pos++
+			}
+			row += 2
+			col -= 2
+			if row >= rows || col < 0 {
+				break
+			}
+		}
+		row += 3
+		col++
+	}
+	if !p.set[rows-1][cols-1] {
+		p.bits[rows-1][cols-1] = true
+		p.bits[rows-2][cols-2] = true
+	}
+	return p.bits
+}
+
+// dmSymbol adds the finder and timing patterns of every data region.
+func dmSymbol(data [][]bool, s *dmSize) [][]bool {
+	grid := newGrid(s.rows, s.cols)
+	rh, rw := s.regionRows+2, s.regionCols+2
+	for y := 0; y < s.rows; y++ {
+		for x := 0; x < s.cols; x++ {
+			ry, rx := y%rh, x%rw
+			switch {
+			case ry == rh-1 || rx == 0:
+				grid[y][x] = true
+			case ry == 0:
+				grid[y][x] = rx%2 == 0
+			case rx == rw-1:
+				grid[y][x] = ry%2 == 1
+			default:
+				grid[y][x] = data[y/rh*s.regionRows+ry-1][x/rw*s.regionCols+rx-1]
+			}
+		}
+	}
+	return grid
+}
+
+func encodeDataMatrix(code string, opts *settings) (*Symbol, error) {
+	if err := validateNonEmpty(code); err != nil {
+		return nil, err
+	}
+	scheme := -1
+	if name := strings.ToUpper(opts.dmEncodeScheme); name != "" && name != "AUTO" {
+		for i, n := range dmSchemeNames {
+			if n == name {
+				scheme = i
+			}
+		}
+		if scheme < 0 {
+			return nil, errDraw(ReasonInvalidOption, "unknown encode scheme %q", opts.dmEncodeScheme)
+		}
+	}
+	var fixed *dmSize
+	if name := strings.ToLower(opts.dmCodeSize); name != "" && name != "auto" {
+		for i := range dmSizes {
+			if dmSizes[i].name() == name {
+				fixed = &dmSizes[i]
+			}
+		}
+		if fixed == nil {
+			return nil, errDraw(ReasonInvalidOption, "unknown code size %q", opts.dmCodeSize)
+		}
+	}
+
+	e := &dmEncoder{msg: []byte(code), auto: scheme < 0, fixed: fixed}
+	if opts.gs1 {
+		cw, err := dmGS1Codewords(code, scheme)
+		if err != nil {
+			return nil, err
+		}
+		e.cw = cw
+	} else if err := e.encode(scheme); err != nil {
+		return nil, err
+	}
+	capacity := e.capacity(len(e.cw))
+	if capacity < 0 {
+		max := dmSizes[dmSquareSizes-1].data
+		if fixed != nil {
+			max = fixed.data
+		}
+		return nil, errDraw(ReasonDataTooLong, "data needs %d codewords, the symbol holds %d", len(e.cw), max)
+	}
+	s := fixed
+	if s == nil {
+		for i := range dmSizes[:dmSquareSizes] {
+			if dmSizes[i].data == capacity {
+				s = &dmSizes[i]
+				break
+			}
+		}
+	}
+
+	data := e.cw
+	for i := len(data); i < s.data; i++ {
+		if i == len(e.cw) {
+			data = append(data, dmPad)
+			continue
+		}
+		r := 149*(i+1)%253 + 1
+		v := dmPad + r
+		if v > 254 {
+			v -= 254
+		}
+		data = append(data, byte(v))
+	}
+	rows := s.rows / (s.regionRows + 2) * s.regionRows
+	cols := s.cols / (s.regionCols + 2) * s.regionCols
+	grid := dmSymbol(dmPlace(dmAddECC(data, s), rows, cols), s)
+	return &Symbol{Modules: grid, QuietZone: 1}, nil
+}
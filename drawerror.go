@@ -0,0 +1,178 @@
+package barcode_pao
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DrawReason classifies why a barcode could not be drawn.
+type DrawReason int
+
+// Draw failure reasons.
+const (
+	// ReasonUnknown means the engine rejected the input without a
+	// diagnosable cause.
+	ReasonUnknown DrawReason = iota
+	// ReasonEmptyInput means the symbology needs at least one character.
+	ReasonEmptyInput
+	// ReasonInvalidCharacter means a character is not encodable; Position
+	// holds its index.
+	ReasonInvalidCharacter
+	// ReasonInvalidLength means the input has a length the symbology does
+	// not allow (e.g. 11 digits for JAN-13).
+	ReasonInvalidLength
+	// ReasonDataTooLong means the input exceeds the symbol capacity, e.g.
+	// for the chosen QR version.
+	ReasonDataTooLong
+	// ReasonBadCheckDigit means a supplied check digit is wrong.
+	ReasonBadCheckDigit
+	// ReasonSizeTooSmall means the requested image size cannot hold the
+	// symbol.
+	ReasonSizeTooSmall
+	// ReasonInvalidOption means a setting has a value the encoder does
+	// not recognise.
+	ReasonInvalidOption
+)
+
+var drawReasonNames = [...]string{
+	ReasonUnknown:          "unknown",
+	ReasonEmptyInput:       "empty input",
+	ReasonInvalidCharacter: "invalid character",
+	ReasonInvalidLength:    "invalid length",
+	ReasonDataTooLong:      "data too long",
+	ReasonBadCheckDigit:    "bad check digit",
+	ReasonSizeTooSmall:     "size too small",
+	ReasonInvalidOption:    "invalid option",
+}
+
+func (r DrawReason) String() string {
+	if r >= 0 && int(r) < len(drawReasonNames) {
+		return drawReasonNames[r]
+	}
+	return fmt.Sprintf("DrawReason(%d)", int(r))
+}
+
+// DrawError is returned by the Draw methods when a barcode cannot be drawn.
+type DrawError struct {
+	// Symbology is the barcode type, e.g. "Code128" or "QR".
+	Symbology string
+	// Input is the code passed to Draw.
+	Input string
+	// Reason classifies the failure.
+	Reason DrawReason
+	// Position is the character index the failure refers to, or -1.
+	Position int
+	// Detail is a human-readable description of the failure.
+	Detail string
+
+	// err is the error the failure was diagnosed from, if any.
+	err error
+}
+
+func (e *DrawError) Error() string {
+	detail := e.Detail
+	if detail == "" {
+		detail = e.Reason.String()
+	}
+	if e.Symbology == "" {
+		return detail
+	}
+	return e.Symbology + ": " + detail
+}
+
+// Unwrap returns the underlying error, e.g. ErrUnknownISBNRange, or nil.
+func (e *DrawError) Unwrap() error {
+	return e.err
+}
+
+// typeNames are the symbology names reported in DrawError, by type ID.
+var typeNames = map[int]string{
+	typeCode39:             "Code39",
+	typeCode93:             "Code93",
+	typeCode128:            "Code128",
+	typeGS1128:             "GS1-128",
+	typeNW7:                "NW-7",
+	typeMatrix2of5:         "Matrix2of5",
+	typeNEC2of5:            "NEC2of5",
+	typeJan8:               "JAN-8",
+	typeJan13:              "JAN-13",
+	typeUPCA:               "UPC-A",
+	typeUPCE:               "UPC-E",
+	typeITF:                "ITF",
+	typeGS1DataBar14:       "GS1 DataBar 14",
+	typeGS1DataBarLimited:  "GS1 DataBar Limited",
+	typeGS1DataBarExpanded: "GS1 DataBar Expanded",
+	typeYubinCustomer:      "YubinCustomer",
+	typeQR:                 "QR",
+	typeDataMatrix:         "DataMatrix",
+	typePDF417:             "PDF417",
+	typeITF14:              "ITF-14",
+	typeCode32:             "Code 32",
+	typeHIBC:               "HIBC",
+	typeCodabar:            "Codabar",
+	typeCode11:             "Code 11",
+	typeMSI:                "MSI Plessey",
+	typePharma:             "Pharmacode",
+	typeTelepen:            "Telepen",
+}
+
+func errInvalidChar(pos int, r rune, detail string) *DrawError {
+	d := fmt.Sprintf("invalid character %q at position %d", r, pos)
+	if detail != "" {
+		d += ": " + detail
+	}
+	return &DrawError{Reason: ReasonInvalidCharacter, Position: pos, Detail: d}
+}
+
+func errCheckDigit(pos int, got, want byte) *DrawError {
+	return &DrawError{Reason: ReasonBadCheckDigit, Position: pos,
+		Detail: "bad check digit " + string(got) + ", expected " + string(want)}
+}
+
+func errDraw(reason DrawReason, format string, args ...interface{}) *DrawError {
+	return &DrawError{Reason: reason, Position: -1, Detail: fmt.Sprintf(format, args...)}
+}
+
+// drawError completes err with the symbology and input. Errors that are
+// not a *DrawError are wrapped as ReasonUnknown.
+func (b *BarcodeBase) drawError(code string, err error) error {
+	if b.ident != nil {
+		return namedError(b.ident.name, code, err)
+	}
+	return inputError(b.typeID, code, err)
+}
+
+// inputError is drawError for checks that run without a generator.
+func inputError(typeID int, code string, err error) error {
+	return namedError(typeNames[typeID], code, err)
+}
+
+// namedError is inputError for a symbology without a type ID.
+func namedError(symbology, code string, err error) error {
+	de, ok := err.(*DrawError)
+	if !ok {
+		de = &DrawError{Reason: ReasonUnknown, Position: -1, Detail: err.Error(), err: err}
+	}
+	if de.Symbology == "" {
+		de.Symbology = symbology
+	}
+	de.Input = code
+	return de
+}
+
+// drawFailure explains a failed native draw call: the input is checked on
+// the Go side to find out what the engine rejected.
+func (b *BarcodeBase) drawFailure(code string, sizes ...int) error {
+	for _, s := range sizes {
+		if s <= 0 {
+			dims := strings.Trim(strings.Join(strings.Fields(fmt.Sprint(sizes)), "x"), "[]")
+			return b.drawError(code, errDraw(ReasonSizeTooSmall, "invalid size %s", dims))
+		}
+	}
+	if v, ok := validators[b.typeID]; ok {
+		if err := v(code, &b.opts); err != nil {
+			return b.drawError(code, err)
+		}
+	}
+	return b.drawError(code, errDraw(ReasonUnknown, "rejected by the native engine"))
+}
@@ -0,0 +1,277 @@
+package barcode_pao
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ─── EAN/UPC: JAN-8, JAN-13, UPC-A, UPC-E ──────────────────────────────────
+
+// eanL holds the element widths of the odd-parity (set A) digits, starting
+// with a space. Set C uses the same widths starting with a bar; the
+// even-parity set B is set C reversed.
+var eanL = [10]string{"3211", "2221", "2122", "1411", "1132", "1231", "1114", "1312", "1213", "3112"}
+
+// ean13Parity selects set A or B for the six left-half digits of JAN-13
+// from its leading digit.
+var ean13Parity = [10]string{"AAAAAA", "AABABB", "AABBAB", "AABBBA", "ABAABB", "ABBAAB", "ABBBAA", "ABABAB", "ABABBA", "ABBABA"}
+
+// upceParity selects set A or B for the six UPC-E digits of number system 0
+// from the check digit; number system 1 inverts it.
+var upceParity = [10]string{"BBBAAA", "BBABAA", "BBAABA", "BBAAAB", "BABBAA", "BAABBA", "BAAABB", "BABABA", "BABAAB", "BAABAB"}
+
+// addOn2Parity and addOn5Parity select set A or B for the add-on digits:
+// EAN-2 from its value modulo 4, EAN-5 from its checksum.
+var (
+	addOn2Parity = [4]string{"AA", "AB", "BA", "BB"}
+	addOn5Parity = [10]string{"BBAAA", "BABAA", "BAABA", "BAAAB", "ABBAA", "AABBA", "AAABB", "ABABA", "ABAAB", "AABAB"}
+)
+
+const (
+	eanGuard  = "111"
+	eanCenter = "11111"
+	upceStop  = "111111"
+
+	addOnGuard     = "112"
+	addOnSeparator = "11"
+	// addOnGap is the light gap before the add-on: 7 to 12 modules are
+	// allowed after EAN, 9 to 12 after UPC.
+	addOnGap       = 9
+	addOnQuietZone = 5
+)
+
+// eanDigit returns the widths of digit d in set 'A', 'B' or 'C'.
+func eanDigit(d byte, set byte) string {
+	w := eanL[d-'0']
+	if set == 'B' {
+		return string([]byte{w[3], w[2], w[1], w[0]})
+	}
+	return w
+}
+
+// withCheckDigit validates code as a GS1 key of n digits plus optional
+// check digit and returns it with the check digit.
+func withCheckDigit(code string, n int, opts *settings) (string, error) {
+	if err := validateGTIN(n)(code, opts); err != nil {
+		return "", err
+	}
+	if len(code) == n {
+		code += string(gs1CheckDigit(code))
+	}
+	return code, nil
+}
+
+// eanBars lays out the two halves of an EAN/UPC symbol between guards.
+func eanBars(left, leftSets, right string) []int {
+	bars := appendWidths(nil, eanGuard)
+	for i := 0; i < len(left); i++ {
+		bars = appendWidths(bars, eanDigit(left[i], leftSets[i]))
+	}
+	bars = appendWidths(bars, eanCenter)
+	for i := 0; i < len(right); i++ {
+		bars = appendWidths(bars, eanDigit(right[i], 'C'))
+	}
+	return appendWidths(bars, eanGuard)
+}
+
+func encodeJan13(code string, opts *settings) (*Symbol, error) {
+	code, err := withCheckDigit(code, 12, opts)
+	if err != nil {
+		return nil, err
+	}
+	bars := eanBars(code[1:7], ean13Parity[code[0]-'0'], code[7:])
+	return eanSymbol(bars, 11, code, opts), nil
+}
+
+func encodeJan8(code string, opts *settings) (*Symbol, error) {
+	code, err := withCheckDigit(code, 7, opts)
+	if err != nil {
+		return nil, err
+	}
+	bars := eanBars(code[:4], "AAAA", code[4:])
+	return eanSymbol(bars, 7, code, opts), nil
+}
+
+func encodeUPCA(code string, opts *settings) (*Symbol, error) {
+	code, err := withCheckDigit(code, 11, opts)
+	if err != nil {
+		return nil, err
+	}
+	bars := eanBars(code[:6], "AAAAAA", code[6:])
+	return eanSymbol(bars, 9, code, opts), nil
+}
+
+func encodeUPCE(code string, opts *settings) (*Symbol, error) {
+	if err := validateUPCE(code, opts); err != nil {
+		return nil, err
+	}
+	if len(code) == 6 {
+		code = "0" + code
+	}
+	if len(code) == 7 {
+		code += string(gs1CheckDigit(expandUPCE(code)))
+	}
+	parity := upceParity[code[7]-'0']
+	bars := appendWidths(nil, eanGuard)
+	for i := 0; i < 6; i++ {
+		set := parity[i]
+		if code[0] == '1' {
+			set ^= 'A' ^ 'B'
+		}
+		bars = appendWidths(bars, eanDigit(code[1+i], set))
+	}
+	bars = appendWidths(bars, upceStop)
+	return eanSymbol(bars, 9, code, opts), nil
+}
+
+// eanSymbol returns the symbol of an EAN/UPC code followed by the add-on
+// set in opts, if any.
+func eanSymbol(bars []int, quietZone int, code string, opts *settings) *Symbol {
+	return &Symbol{Bars: appendAddOn(bars, opts.addOn), QuietZone: quietZone, Text: code, AddOn: opts.addOn}
+}
+
+// validateAddOn accepts an EAN-2 or EAN-5 add-on, or "" for none.
+func validateAddOn(addOn string) error {
+	if addOn != "" && (len(addOn) != 2 && len(addOn) != 5 || strings.Trim(addOn, digitChars) != "") {
+		return fmt.Errorf("%w: add-on must be 2 or 5 digits, got %q", ErrInvalidOption, addOn)
+	}
+	return nil
+}
+
+// appendAddOn appends the gap and the bars of an EAN-2 or EAN-5 add-on.
+func appendAddOn(bars []int, addOn string) []int {
+	var parity string
+	switch len(addOn) {
+	case 2:
+		parity = addOn2Parity[(int(addOn[0]-'0')*10+int(addOn[1]-'0'))%4]
+	case 5:
+		sum := 0
+		for i := 0; i < 5; i++ {
+			if i%2 == 0 {
+				sum += 3 * int(addOn[i]-'0')
+			} else {
+				sum += 9 * int(addOn[i]-'0')
+			}
+		}
+		parity = addOn5Parity[sum%10]
+	default:
+		return bars
+	}
+	bars = append(bars, addOnGap)
+	bars = appendWidths(bars, addOnGuard)
+	for i := 0; i < len(addOn); i++ {
+		if i > 0 {
+			bars = appendWidths(bars, addOnSeparator)
+		}
+		bars = appendWidths(bars, eanDigit(addOn[i], parity[i]))
+	}
+	return bars
+}
+
+// eanGeometry describes an EAN/UPC symbol in modules from its left edge.
+type eanGeometry struct {
+	width  int       // the main symbol, without add-on
+	guards [][2]int  // the bars extended by extendedGuard
+	groups []eanText // the HRI digits
+}
+
+// eanText places Text[from:to] in the modules [x0, x1). The leading digit
+// of JAN-13, UPC-A and UPC-E and the UPC check digit sit in the quiet zone.
+type eanText struct{ from, to, x0, x1 int }
+
+var eanGeometries = map[int]eanGeometry{
+	typeJan13: {95, [][2]int{{0, 3}, {45, 50}, {92, 95}}, []eanText{{0, 1, -8, -1}, {1, 7, 3, 45}, {7, 13, 50, 92}}},
+	typeJan8:  {67, [][2]int{{0, 3}, {31, 36}, {64, 67}}, []eanText{{0, 4, 3, 31}, {4, 8, 36, 64}}},
+	typeUPCA:  {95, [][2]int{{0, 10}, {45, 50}, {85, 95}}, []eanText{{0, 1, -8, -1}, {1, 6, 10, 45}, {6, 11, 50, 85}, {11, 12, 96, 103}}},
+	typeUPCE:  {51, [][2]int{{0, 3}, {45, 51}}, []eanText{{0, 1, -8, -1}, {1, 7, 3, 45}, {7, 8, 52, 59}}},
+}
+
+func (g *eanGeometry) isGuard(module int) bool {
+	for _, r := range g.guards {
+		if module >= r[0] && module < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// layoutEAN places an EAN/UPC symbol in a width × height box. The HRI
+// digits are grouped under the halves of the symbol with the guard bars
+// extended between them; the add-on digits and the header are printed
+// above the add-on and the main bars.
+func layoutEAN(sym *Symbol, g eanGeometry, width, height int, o *settings) (*drawing, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errDraw(ReasonSizeTooSmall, "invalid size %dx%d", width, height)
+	}
+	rightQuiet := sym.QuietZone
+	if sym.AddOn != "" {
+		rightQuiet = addOnQuietZone
+	}
+	total := sym.QuietZone + sym.Width() + rightQuiet
+	mw := float64(width) / float64(total)
+	if !o.fitWidth && mw >= 1 {
+		mw = math.Floor(mw)
+	}
+	left := (float64(width)-mw*float64(total))/2 + float64(sym.QuietZone)*mw
+	addOnX := g.width + addOnGap
+
+	d := &drawing{width: width, height: height, fg: o.fg, bg: o.bg}
+	h := float64(height)
+	// Bars run from mainTop (addOnTop for the add-on) down to barBottom,
+	// guard bars to guardBottom.
+	mainTop, addOnTop, barBottom, guardBottom := 0.0, 0.0, h, h
+	if o.showText {
+		// fit returns the largest glyph height for n characters in w pixels.
+		fit := func(w float64, n int) float64 { return w * glyphH / (float64(n)*glyphAdv - 1) }
+		size := math.Max(glyphH, math.Round(h*0.15*o.textFontScale))
+		for _, t := range g.groups {
+			size = math.Min(size, fit(float64(t.x1-t.x0)*mw, t.to-t.from))
+		}
+		if sym.AddOn != "" {
+			size = math.Min(size, fit(float64(sym.Width()-addOnX)*mw, len(sym.AddOn)))
+		}
+		gap := o.textGap * size / 3
+		if sym.AddOn != "" {
+			addOnTop = size + gap
+			d.labels = append(d.labels, label{text: sym.AddOn, x: left + float64(addOnX)*mw, y: 0,
+				w: float64(sym.Width()-addOnX) * mw, size: size, even: o.textEvenSpacing})
+		}
+		if sym.Header != "" {
+			hs := math.Min(size, fit(float64(g.width)*mw, len([]rune(sym.Header))))
+			mainTop = hs + o.textGap*hs/3
+			d.labels = append(d.labels, label{text: sym.Header, x: left, y: 0, w: float64(g.width) * mw, size: hs})
+		}
+		barBottom = h - size - gap
+		guardBottom = barBottom
+		if o.extendedGuard {
+			guardBottom += gap + size/2
+		}
+		if barBottom-math.Max(mainTop, addOnTop) < 1 {
+			return nil, errDraw(ReasonSizeTooSmall, "height %d is too small to fit bars and text", height)
+		}
+		for _, t := range g.groups {
+			d.labels = append(d.labels, label{text: sym.Text[t.from:t.to], x: left + float64(t.x0)*mw, y: h - size,
+				w: float64(t.x1-t.x0) * mw, size: size, even: o.textEvenSpacing})
+		}
+	}
+
+	adj := float64(o.pxAdjustBlack - o.pxAdjustWhite)
+	x, m := left, 0
+	for i, e := range sym.Bars {
+		w := float64(e) * mw
+		if i%2 == 0 && w+adj > 0 {
+			top, bottom := mainTop, barBottom
+			switch {
+			case m >= addOnX:
+				top, bottom = addOnTop, guardBottom
+			case g.isGuard(m):
+				bottom = guardBottom
+			}
+			d.rects = append(d.rects, rect{x: x - adj/2, y: top, w: w + adj, h: bottom - top})
+		}
+		x += w
+		m += e
+	}
+	return d, nil
+}
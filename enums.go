@@ -0,0 +1,225 @@
+package barcode_pao
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ─── Typed settings ────────────────────────────────────────────────────────
+//
+// The string-valued settings take one of the types below. The setters match
+// values case-insensitively, pass the engine's spelling on, and reject
+// anything else with an error wrapping ErrInvalidOption: the engine would
+// otherwise fall back to its default without notice.
+
+// ECCLevel is a QR error correction level.
+type ECCLevel string
+
+// QR error correction levels, by the share of codewords they can restore.
+const (
+	ECCLevelL ECCLevel = "L" // about 7%
+	ECCLevelM ECCLevel = "M" // about 15%
+	ECCLevelQ ECCLevel = "Q" // about 25%
+	ECCLevelH ECCLevel = "H" // about 30%
+)
+
+// QREncodeMode is the QR data encoding mode.
+type QREncodeMode string
+
+// QR encode modes.
+const (
+	QREncodeNumeric      QREncodeMode = "NUMERIC"
+	QREncodeAlphanumeric QREncodeMode = "ALPHANUMERIC"
+	QREncodeByte         QREncodeMode = "BYTE"
+	QREncodeKanji        QREncodeMode = "KANJI"
+)
+
+// Code128Mode selects the Code128 code set.
+type Code128Mode string
+
+// Code128 modes. Code128Auto switches code sets to minimise the length.
+const (
+	Code128Auto Code128Mode = "AUTO"
+	Code128A    Code128Mode = "A"
+	Code128B    Code128Mode = "B"
+	Code128C    Code128Mode = "C"
+)
+
+// DataMatrixSize is a DataMatrix ECC200 symbol size, rows x columns.
+type DataMatrixSize string
+
+// DataMatrixSizeAuto picks the smallest square size that holds the data.
+const DataMatrixSizeAuto DataMatrixSize = "AUTO"
+
+// Square DataMatrix sizes.
+const (
+	DataMatrixSize10x10   DataMatrixSize = "10x10"
+	DataMatrixSize12x12   DataMatrixSize = "12x12"
+	DataMatrixSize14x14   DataMatrixSize = "14x14"
+	DataMatrixSize16x16   DataMatrixSize = "16x16"
+	DataMatrixSize18x18   DataMatrixSize = "18x18"
+	DataMatrixSize20x20   DataMatrixSize = "20x20"
+	DataMatrixSize22x22   DataMatrixSize = "22x22"
+	DataMatrixSize24x24   DataMatrixSize = "24x24"
+	DataMatrixSize26x26   DataMatrixSize = "26x26"
+	DataMatrixSize32x32   DataMatrixSize = "32x32"
+	DataMatrixSize36x36   DataMatrixSize = "36x36"
+	DataMatrixSize40x40   DataMatrixSize = "40x40"
+	DataMatrixSize44x44   DataMatrixSize = "44x44"
+	DataMatrixSize48x48   DataMatrixSize = "48x48"
+	DataMatrixSize52x52   DataMatrixSize = "52x52"
+	DataMatrixSize64x64   DataMatrixSize = "64x64"
+	DataMatrixSize72x72   DataMatrixSize = "72x72"
+	DataMatrixSize80x80   DataMatrixSize = "80x80"
+	DataMatrixSize88x88   DataMatrixSize = "88x88"
+	DataMatrixSize96x96   DataMatrixSize = "96x96"
+	DataMatrixSize104x104 DataMatrixSize = "104x104"
+	DataMatrixSize120x120 DataMatrixSize = "120x120"
+	DataMatrixSize132x132 DataMatrixSize = "132x132"
+	DataMatrixSize144x144 DataMatrixSize = "144x144"
+)
+
+// Rectangular DataMatrix sizes.
+const (
+	DataMatrixSize8x18  DataMatrixSize = "8x18"
+	DataMatrixSize8x32  DataMatrixSize = "8x32"
+	DataMatrixSize12x26 DataMatrixSize = "12x26"
+	DataMatrixSize12x36 DataMatrixSize = "12x36"
+	DataMatrixSize16x36 DataMatrixSize = "16x36"
+	DataMatrixSize16x48 DataMatrixSize = "16x48"
+)
+
+// DataMatrixScheme is a DataMatrix encodation scheme.
+type DataMatrixScheme string
+
+// DataMatrix encodation schemes. DataMatrixSchemeAuto mixes schemes to
+// minimise the length.
+const (
+	DataMatrixSchemeAuto    DataMatrixScheme = "AUTO"
+	DataMatrixSchemeASCII   DataMatrixScheme = "ASCII"
+	DataMatrixSchemeC40     DataMatrixScheme = "C40"
+	DataMatrixSchemeText    DataMatrixScheme = "TEXT"
+	DataMatrixSchemeX12     DataMatrixScheme = "X12"
+	DataMatrixSchemeEDIFACT DataMatrixScheme = "EDIFACT"
+	DataMatrixSchemeBase256 DataMatrixScheme = "BASE256"
+)
+
+// DataBarSymbolType is the layout of a GS1 DataBar symbol.
+type DataBarSymbolType string
+
+// GS1 DataBar symbol types. GS1 DataBar 14 takes the first three, GS1
+// DataBar Expanded DataBarUnstacked and DataBarStacked.
+const (
+	DataBarOmnidirectional        DataBarSymbolType = "OMNIDIRECTIONAL"
+	DataBarStacked                DataBarSymbolType = "STACKED"
+	DataBarStackedOmnidirectional DataBarSymbolType = "STACKED_OMNIDIRECTIONAL"
+	DataBarUnstacked              DataBarSymbolType = "UNSTACKED"
+)
+
+// BearerStyle selects the bearer bars of ITF14, which keep a skewed scan
+// line from reading a partial symbol and spread the printing plate's
+// pressure.
+type BearerStyle string
+
+// ITF-14 bearer bar styles.
+const (
+	BearerFrame     BearerStyle = "FRAME"      // a frame around the symbol and quiet zones
+	BearerTopBottom BearerStyle = "TOP_BOTTOM" // bars along the top and bottom edges
+	BearerNone      BearerStyle = "NONE"
+)
+
+// CheckDigitPolicy decides how Jan8, Jan13, UPCA, UPCE and ITF14 treat a
+// supplied check digit that is wrong.
+type CheckDigitPolicy string
+
+// Check digit policies.
+const (
+	// CheckDigitStrict rejects a wrong check digit with a *DrawError of
+	// ReasonBadCheckDigit before the engine is called. It is the default.
+	// Earlier versions passed the input to the engine unchecked, and the
+	// engine drew some of it with the wrong digit; such input now fails
+	// unless CheckDigitLenient is set.
+	CheckDigitStrict CheckDigitPolicy = "STRICT"
+	// CheckDigitLenient replaces a wrong check digit with the correct one.
+	CheckDigitLenient CheckDigitPolicy = "LENIENT"
+)
+
+// NW7CheckScheme is the check character scheme of NW7. The check character
+// goes before the stop character.
+type NW7CheckScheme string
+
+// NW-7 check schemes. All but NW7CheckMod16 take digits only and add a
+// digit.
+const (
+	NW7CheckNone     NW7CheckScheme = "NONE"
+	NW7CheckMod16    NW7CheckScheme = "MOD16"     // modulus 16 over all characters, start/stop included
+	NW7CheckMod11    NW7CheckScheme = "MOD11"     // modulus 11, weights 2–7; remainders 0 and 1 give 0
+	NW7CheckMod10W21 NW7CheckScheme = "MOD10_W21" // modulus 10, weights 2 and 1 with digit sums (Luhn)
+	NW7CheckMod10W31 NW7CheckScheme = "MOD10_W31" // modulus 10, weights 3 and 1
+	NW7Check7DR      NW7CheckScheme = "7DR"       // remainder of the number divided by 7
+	NW7Check7DSR     NW7CheckScheme = "7DSR"      // 7 minus that remainder
+	NW7Check9DR      NW7CheckScheme = "9DR"       // remainder of the number divided by 9
+	NW7Check9DSR     NW7CheckScheme = "9DSR"      // 9 minus that remainder
+)
+
+// MSICheckScheme is the check digit scheme of MSI Plessey. The check
+// digits are printed.
+type MSICheckScheme string
+
+// MSI Plessey check schemes.
+const (
+	MSICheckNone       MSICheckScheme = "NONE"
+	MSICheckMod10      MSICheckScheme = "MOD10"       // modulus 10 (Luhn); the default
+	MSICheckMod10Mod10 MSICheckScheme = "MOD10_MOD10" // two modulus 10 digits
+	MSICheckMod11      MSICheckScheme = "MOD11"       // modulus 11, weights 2–7; a check value of 10 is written "10"
+	MSICheckMod11Mod10 MSICheckScheme = "MOD11_MOD10" // modulus 11, then modulus 10 over the result
+)
+
+// HIBCStandard is the data structure of an HIBC symbol.
+type HIBCStandard string
+
+// HIBC standards.
+const (
+	// HIBCLIC is the Labeler Identification Code standard: primary data
+	// (labeler code, product number, unit of measure), secondary data
+	// starting with '$', or both joined by '/'. It is the default.
+	HIBCLIC HIBCStandard = "LIC"
+	// HIBCPAS is the Provider Applications Standard, whose data starts
+	// with the '/' flag character.
+	HIBCPAS HIBCStandard = "PAS"
+)
+
+var (
+	eccLevels          = []ECCLevel{ECCLevelL, ECCLevelM, ECCLevelQ, ECCLevelH}
+	qrEncodeModes      = []QREncodeMode{QREncodeNumeric, QREncodeAlphanumeric, QREncodeByte, QREncodeKanji}
+	code128Modes       = []Code128Mode{Code128Auto, Code128A, Code128B, Code128C}
+	dataMatrixSchemes  = []DataMatrixScheme{DataMatrixSchemeAuto, DataMatrixSchemeASCII, DataMatrixSchemeC40, DataMatrixSchemeText, DataMatrixSchemeX12, DataMatrixSchemeEDIFACT, DataMatrixSchemeBase256}
+	dataBar14Types     = []DataBarSymbolType{DataBarOmnidirectional, DataBarStacked, DataBarStackedOmnidirectional}
+	dataBarExpTypes    = []DataBarSymbolType{DataBarUnstacked, DataBarStacked}
+	checkDigitPolicies = []CheckDigitPolicy{CheckDigitStrict, CheckDigitLenient}
+	bearerStyles       = []BearerStyle{BearerFrame, BearerTopBottom, BearerNone}
+	hibcStandards      = []HIBCStandard{HIBCLIC, HIBCPAS}
+	msiCheckSchemes    = []MSICheckScheme{MSICheckNone, MSICheckMod10, MSICheckMod10Mod10, MSICheckMod11, MSICheckMod11Mod10}
+	nw7CheckSchemes    = []NW7CheckScheme{NW7CheckNone, NW7CheckMod16, NW7CheckMod11, NW7CheckMod10W21, NW7CheckMod10W31, NW7Check7DR, NW7Check7DSR, NW7Check9DR, NW7Check9DSR}
+	dataMatrixSizeList = func() []DataMatrixSize {
+		sizes := []DataMatrixSize{DataMatrixSizeAuto}
+		for i := range dmSizes {
+			sizes = append(sizes, DataMatrixSize(dmSizes[i].name()))
+		}
+		return sizes
+	}()
+)
+
+// parseOption returns the value of known that matches v, ignoring case.
+func parseOption[T ~string](what string, v T, known []T) (T, error) {
+	for _, k := range known {
+		if strings.EqualFold(string(v), string(k)) {
+			return k, nil
+		}
+	}
+	names := make([]string, len(known))
+	for i, k := range known {
+		names[i] = string(k)
+	}
+	return v, fmt.Errorf("%w: unknown %s %q (want one of %s)", ErrInvalidOption, what, string(v), strings.Join(names, ", "))
+}
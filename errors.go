@@ -0,0 +1,45 @@
+package barcode_pao
+
+import "errors"
+
+// Errors returned by the NewXxxE constructors. They are wrapped with
+// details such as the library path or type ID; test them with errors.Is.
+var (
+	// ErrLibraryNotFound means the native library could not be loaded, or
+	// does not export the barcode API. Types with a pure-Go encoder do not
+	// report it.
+	ErrLibraryNotFound = errors.New("failed to load barcode native library")
+
+	// ErrHandleCreation means barcode_create returned no handle.
+	ErrHandleCreation = errors.New("failed to create barcode handle")
+)
+
+// ErrInvalidOption is wrapped by the setters when a value is not one the
+// engine recognises, such as an unknown QR error correction level.
+var ErrInvalidOption = errors.New("invalid option")
+
+// ErrUnknownKind is returned by New for a kind name that is not
+// registered.
+var ErrUnknownKind = errors.New("unknown barcode kind")
+
+// ErrNotCompressible is returned by CompressUPCA for UPC-A numbers that
+// have no UPC-E form.
+var ErrNotCompressible = errors.New("UPC-A number cannot be zero-suppressed to UPC-E")
+
+// ErrUnknownISBNRange is returned by HyphenateISBN and the ISBN generator
+// for ISBNs whose registration group or registrant range is not in the
+// embedded table.
+var ErrUnknownISBNRange = errors.New("ISBN range not in the range table")
+
+// ErrClosed is returned by the setters, Encode and the Draw methods of a
+// generator after Close.
+var ErrClosed = errors.New("barcode generator is closed")
+
+// must panics if err is non-nil. It backs the constructors that keep the
+// original panicking signature.
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
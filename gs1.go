@@ -0,0 +1,162 @@
+package barcode_pao
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/pao-xx/barcode-pao/gs1"
+)
+
+// ─── GS1 element strings ───────────────────────────────────────────────────
+//
+// GS1-128 and GS1 DataBar Expanded take GS1 element strings, either with
+// parenthesised AIs, "(01)04912345123459(10)ABC123", or raw with FNC1
+// separators written as "{FNC1}" or GS (ASCII 29).
+
+// gs1FixedPrefixes are the AI prefixes with a predefined data length; their
+// elements need no FNC1 separator before the next AI.
+var gs1FixedPrefixes = []string{
+	"00", "01", "02", "03", "04", "11", "12", "13", "14", "15", "16", "17", "18", "19", "20",
+	"31", "32", "33", "34", "35", "36", "41",
+}
+
+const gs1GS = '\x1d'
+
+// gs1Element is one Application Identifier and its data.
+type gs1Element struct {
+	ai, data string
+}
+
+func gs1FixedLength(ai string) bool {
+	for _, p := range gs1FixedPrefixes {
+		if strings.HasPrefix(ai, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseGS1Parens splits "(AI)data(AI)data…" into elements.
+func parseGS1Parens(code string) ([]gs1Element, error) {
+	var elems []gs1Element
+	for i := 0; i < len(code); {
+		if code[i] != '(' {
+			return nil, errInvalidChar(i, rune(code[i]), "expected '(' to open an AI")
+		}
+		end := strings.IndexByte(code[i:], ')')
+		if end < 0 {
+			return nil, errDraw(ReasonInvalidCharacter, "unterminated AI at position %d", i)
+		}
+		ai := code[i+1 : i+end]
+		if len(ai) < 2 || len(ai) > 4 || strings.Trim(ai, digitChars) != "" {
+			return nil, errDraw(ReasonInvalidCharacter, "invalid AI %q at position %d", ai, i)
+		}
+		i += end + 1
+		next := strings.IndexByte(code[i:], '(')
+		if next < 0 {
+			next = len(code) - i
+		}
+		if next == 0 {
+			return nil, errDraw(ReasonInvalidLength, "AI (%s) has no data", ai)
+		}
+		elems = append(elems, gs1Element{ai: ai, data: code[i : i+next]})
+		i += next
+	}
+	return elems, nil
+}
+
+// gs1Data parses a GS1 element string. It returns the encodable data, with
+// FNC1 separators as runeFNC1 but without the leading FNC1, the
+// human-readable text, and the elements when AIs were parenthesised.
+func gs1Data(code string) ([]rune, string, []gs1Element, error) {
+	if code == "" {
+		return nil, "", nil, errDraw(ReasonEmptyInput, "empty input")
+	}
+	for i, r := range code {
+		if r > 127 {
+			return nil, "", nil, errInvalidChar(i, r, "only ASCII is allowed")
+		}
+	}
+	if code[0] == '(' {
+		elems, err := parseGS1Parens(code)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		var data []rune
+		for i, e := range elems {
+			data = append(data, []rune(e.ai+e.data)...)
+			if i < len(elems)-1 && !gs1FixedLength(e.ai) {
+				data = append(data, runeFNC1)
+			}
+		}
+		return data, code, elems, nil
+	}
+	var data []rune
+	var text strings.Builder
+	for i := 0; i < len(code); {
+		switch {
+		case strings.HasPrefix(code[i:], "{FNC1}"):
+			i += len("{FNC1}")
+		case code[i] == gs1GS:
+			i++
+		default:
+			data = append(data, rune(code[i]))
+			text.WriteByte(code[i])
+			i++
+			continue
+		}
+		if len(data) > 0 && data[len(data)-1] != runeFNC1 {
+			data = append(data, runeFNC1)
+		}
+	}
+	for len(data) > 0 && data[len(data)-1] == runeFNC1 {
+		data = data[:len(data)-1]
+	}
+	if len(data) == 0 {
+		return nil, "", nil, errDraw(ReasonEmptyInput, "no data besides FNC1")
+	}
+	return data, text.String(), nil, nil
+}
+
+// gs1Message validates a GS1 element string against the GS1 AI table and
+// returns its data like gs1Data. It backs the GS1 modes of the 2D
+// symbologies.
+func gs1Message(code string) ([]rune, error) {
+	s, err := gs1.Parse(code)
+	if err != nil {
+		return nil, gs1DrawError(err)
+	}
+	data, _, _, err := gs1Data(s.Data())
+	return data, err
+}
+
+// gs1DrawError converts a *gs1.Error to a *DrawError. Positions are only
+// kept for syntax errors; for the others they refer to an element's data.
+func gs1DrawError(err error) error {
+	var e *gs1.Error
+	if !errors.As(err, &e) {
+		return err
+	}
+	d := &DrawError{Reason: ReasonInvalidCharacter, Position: -1, Detail: strings.TrimPrefix(e.Error(), "gs1: ")}
+	switch {
+	case e.AI == "":
+		d.Position = e.Position
+	case errors.Is(e, gs1.ErrInvalidLength):
+		d.Reason = ReasonInvalidLength
+	case errors.Is(e, gs1.ErrCheckDigit):
+		d.Reason = ReasonBadCheckDigit
+	}
+	return d
+}
+
+func encodeGS1128(code string, opts *settings) (*Symbol, error) {
+	data, text, _, err := gs1Data(code)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := code128Values(append([]rune{runeFNC1}, data...), "AUTO")
+	if err != nil {
+		return nil, err
+	}
+	return &Symbol{Bars: code128Bars(vals), QuietZone: 10, Text: text}, nil
+}
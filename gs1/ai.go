@@ -0,0 +1,431 @@
+package gs1
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ─── AI table ──────────────────────────────────────────────────────────────
+//
+// Formats follow the notation of the GS1 General Specifications: components
+// joined by "+", each a character set and a length, optionally followed by
+// a check:
+//
+//	N14,csum    14 digits ending in a GS1 check digit
+//	X..20       1 to 20 characters of GS1 character set 82
+//	N0..6       up to 6 digits, possibly none
+//	Y..30       1 to 30 characters of GS1 character set 39
+//
+// Only the last component of a format has a variable length.
+
+// AIInfo describes an application identifier.
+type AIInfo struct {
+	// AI is the application identifier, e.g. "3103".
+	AI string
+	// Title is the GS1 data title, e.g. "NET WEIGHT (kg)".
+	Title string
+	// Format is the data format in GS1 notation, e.g. "N6".
+	Format string
+	// Predefined is true if the AI has a predefined length, so that no
+	// FNC1 separator follows its data.
+	Predefined bool
+	// Decimals is the implied decimal point position of measure and
+	// amount AIs (the last digit of the AI), or -1.
+	Decimals int
+
+	comps []component
+}
+
+type component struct {
+	charset  byte // 'N', 'X' or 'Y'
+	min, max int
+	check    string
+}
+
+// aiDef is a row of the AI table. Rows with variants stand for AIs ai+"0"
+// through ai+strconv.Itoa(variants-1).
+type aiDef struct {
+	ai       string
+	variants int
+	title    string
+	format   string
+}
+
+var aiDefs = []aiDef{
+	{"00", 0, "SSCC", "N18,csum"},
+	{"01", 0, "GTIN", "N14,csum"},
+	{"02", 0, "CONTENT", "N14,csum"},
+	{"03", 0, "MTO GTIN", "N14,csum"},
+	{"10", 0, "BATCH/LOT", "X..20"},
+	{"11", 0, "PROD DATE", "N6,yymmd0"},
+	{"12", 0, "DUE DATE", "N6,yymmd0"},
+	{"13", 0, "PACK DATE", "N6,yymmd0"},
+	{"15", 0, "BEST BEFORE or BEST BY", "N6,yymmd0"},
+	{"16", 0, "SELL BY", "N6,yymmd0"},
+	{"17", 0, "USE BY OR EXPIRY", "N6,yymmd0"},
+	{"20", 0, "VARIANT", "N2"},
+	{"21", 0, "SERIAL", "X..20"},
+	{"22", 0, "CPV", "X..20"},
+	{"235", 0, "TPX", "X..28"},
+	{"240", 0, "ADDITIONAL ID", "X..30"},
+	{"241", 0, "CUST. PART No.", "X..30"},
+	{"242", 0, "MTO VARIANT", "N..6"},
+	{"243", 0, "PCN", "X..20"},
+	{"250", 0, "SECONDARY SERIAL", "X..30"},
+	{"251", 0, "REF. TO SOURCE", "X..30"},
+	{"253", 0, "GDTI", "N13,csum+X0..17"},
+	{"254", 0, "GLN EXTENSION COMPONENT", "X..20"},
+	{"255", 0, "GCN", "N13,csum+N0..12"},
+	{"30", 0, "VAR. COUNT", "N..8"},
+	{"310", 6, "NET WEIGHT (kg)", "N6"},
+	{"311", 6, "LENGTH (m)", "N6"},
+	{"312", 6, "WIDTH (m)", "N6"},
+	{"313", 6, "HEIGHT (m)", "N6"},
+	{"314", 6, "AREA (m2)", "N6"},
+	{"315", 6, "NET VOLUME (l)", "N6"},
+	{"316", 6, "NET VOLUME (m3)", "N6"},
+	{"320", 6, "NET WEIGHT (lb)", "N6"},
+	{"321", 6, "LENGTH (in)", "N6"},
+	{"322", 6, "LENGTH (ft)", "N6"},
+	{"323", 6, "LENGTH (yd)", "N6"},
+	{"324", 6, "WIDTH (in)", "N6"},
+	{"325", 6, "WIDTH (ft)", "N6"},
+	{"326", 6, "WIDTH (yd)", "N6"},
+	{"327", 6, "HEIGHT (in)", "N6"},
+	{"328", 6, "HEIGHT (ft)", "N6"},
+	{"329", 6, "HEIGHT (yd)", "N6"},
+	{"330", 6, "GROSS WEIGHT (kg)", "N6"},
+	{"331", 6, "LENGTH (m), log", "N6"},
+	{"332", 6, "WIDTH (m), log", "N6"},
+	{"333", 6, "HEIGHT (m), log", "N6"},
+	{"334", 6, "AREA (m2), log", "N6"},
+	{"335", 6, "VOLUME (l), log", "N6"},
+	{"336", 6, "VOLUME (m3), log", "N6"},
+	{"337", 6, "KG PER m2", "N6"},
+	{"340", 6, "GROSS WEIGHT (lb)", "N6"},
+	{"341", 6, "LENGTH (in), log", "N6"},
+	{"342", 6, "LENGTH (ft), log", "N6"},
+	{"343", 6, "LENGTH (yd), log", "N6"},
+	{"344", 6, "WIDTH (in), log", "N6"},
+	{"345", 6, "WIDTH (ft), log", "N6"},
+	{"346", 6, "WIDTH (yd), log", "N6"},
+	{"347", 6, "HEIGHT (in), log", "N6"},
+	{"348", 6, "HEIGHT (ft), log", "N6"},
+	{"349", 6, "HEIGHT (yd), log", "N6"},
+	{"350", 6, "AREA (in2)", "N6"},
+	{"351", 6, "AREA (ft2)", "N6"},
+	{"352", 6, "AREA (yd2)", "N6"},
+	{"353", 6, "AREA (in2), log", "N6"},
+	{"354", 6, "AREA (ft2), log", "N6"},
+	{"355", 6, "AREA (yd2), log", "N6"},
+	{"356", 6, "NET WEIGHT (t oz)", "N6"},
+	{"357", 6, "NET VOLUME (oz)", "N6"},
+	{"360", 6, "NET VOLUME (qt)", "N6"},
+	{"361", 6, "NET VOLUME (gal.)", "N6"},
+	{"362", 6, "VOLUME (qt), log", "N6"},
+	{"363", 6, "VOLUME (gal.), log", "N6"},
+	{"364", 6, "VOLUME (in3)", "N6"},
+	{"365", 6, "VOLUME (ft3)", "N6"},
+	{"366", 6, "VOLUME (yd3)", "N6"},
+	{"367", 6, "VOLUME (in3), log", "N6"},
+	{"368", 6, "VOLUME (ft3), log", "N6"},
+	{"369", 6, "VOLUME (yd3), log", "N6"},
+	{"37", 0, "COUNT", "N..8"},
+	{"390", 10, "AMOUNT", "N..15"},
+	{"391", 10, "AMOUNT", "N3+N..15"},
+	{"392", 10, "PRICE", "N..15"},
+	{"393", 10, "PRICE", "N3+N..15"},
+	{"394", 4, "PRCNT OFF", "N4"},
+	{"395", 6, "PRICE/UoM", "N6"},
+	{"400", 0, "ORDER NUMBER", "X..30"},
+	{"401", 0, "GINC", "X..30"},
+	{"402", 0, "GSIN", "N17,csum"},
+	{"403", 0, "ROUTE", "X..30"},
+	{"410", 0, "SHIP TO LOC", "N13,csum"},
+	{"411", 0, "BILL TO", "N13,csum"},
+	{"412", 0, "PURCHASE FROM", "N13,csum"},
+	{"413", 0, "SHIP FOR LOC", "N13,csum"},
+	{"414", 0, "LOC No.", "N13,csum"},
+	{"415", 0, "PAY TO", "N13,csum"},
+	{"416", 0, "PROD/SERV LOC", "N13,csum"},
+	{"417", 0, "PARTY", "N13,csum"},
+	{"420", 0, "SHIP TO POST", "X..20"},
+	{"421", 0, "SHIP TO POST", "N3+X..9"},
+	{"422", 0, "ORIGIN", "N3"},
+	{"423", 0, "COUNTRY - INITIAL PROCESS.", "N3+N0..12"},
+	{"424", 0, "COUNTRY - PROCESS.", "N3"},
+	{"425", 0, "COUNTRY - DISASSEMBLY", "N3+N0..12"},
+	{"426", 0, "COUNTRY - FULL PROCESS", "N3"},
+	{"427", 0, "ORIGIN SUBDIVISION", "X..3"},
+	{"4300", 0, "SHIP TO COMP", "X..35"},
+	{"4301", 0, "SHIP TO NAME", "X..35"},
+	{"4302", 0, "SHIP TO ADD1", "X..70"},
+	{"4303", 0, "SHIP TO ADD2", "X..70"},
+	{"4304", 0, "SHIP TO SUB", "X..70"},
+	{"4305", 0, "SHIP TO LOC", "X..70"},
+	{"4306", 0, "SHIP TO REG", "X..70"},
+	{"4307", 0, "SHIP TO COUNTRY", "X2"},
+	{"4308", 0, "SHIP TO PHONE", "X..30"},
+	{"4309", 0, "SHIP TO GEO", "N20"},
+	{"4310", 0, "RTN TO COMP", "X..35"},
+	{"4311", 0, "RTN TO NAME", "X..35"},
+	{"4312", 0, "RTN TO ADD1", "X..70"},
+	{"4313", 0, "RTN TO ADD2", "X..70"},
+	{"4314", 0, "RTN TO SUB", "X..70"},
+	{"4315", 0, "RTN TO LOC", "X..70"},
+	{"4316", 0, "RTN TO REG", "X..70"},
+	{"4317", 0, "RTN TO COUNTRY", "X2"},
+	{"4318", 0, "RTN TO POST", "X..20"},
+	{"4319", 0, "RTN TO PHONE", "X..30"},
+	{"4320", 0, "SRV DESCRIPTION", "X..35"},
+	{"4321", 0, "DANGEROUS GOODS", "N1,yesno"},
+	{"4322", 0, "AUTH LEAVE", "N1,yesno"},
+	{"4323", 0, "SIG REQUIRED", "N1,yesno"},
+	{"4324", 0, "NBEF DEL DT", "N10,yymmddhhmm"},
+	{"4325", 0, "NAFT DEL DT", "N10,yymmddhhmm"},
+	{"4326", 0, "REL DATE", "N6,yymmdd"},
+	{"7001", 0, "NSN", "N13"},
+	{"7002", 0, "MEAT CUT", "X..30"},
+	{"7003", 0, "EXPIRY TIME", "N10,yymmddhhmm"},
+	{"7004", 0, "ACTIVE POTENCY", "N..4"},
+	{"7005", 0, "CATCH AREA", "X..12"},
+	{"7006", 0, "FIRST FREEZE DATE", "N6,yymmdd"},
+	{"7007", 0, "HARVEST DATE", "N6,yymmdd+N0..6,yymmdd"},
+	{"7008", 0, "AQUATIC SPECIES", "X..3"},
+	{"7009", 0, "FISHING GEAR TYPE", "X..10"},
+	{"7010", 0, "PROD METHOD", "X..2"},
+	{"7011", 0, "TEST BY DATE", "N6,yymmdd+N0..4"},
+	{"7020", 0, "REFURB LOT", "X..20"},
+	{"7021", 0, "FUNC STAT", "X..20"},
+	{"7022", 0, "REV STAT", "X..20"},
+	{"7023", 0, "GIAI - ASSEMBLY", "X..30"},
+	{"703", 10, "PROCESSOR #", "N3+X..27"},
+	{"7040", 0, "UIC+EXT", "X4"},
+	{"710", 0, "NHRN PZN", "X..20"},
+	{"711", 0, "NHRN CIP", "X..20"},
+	{"712", 0, "NHRN CN", "X..20"},
+	{"713", 0, "NHRN DRN", "X..20"},
+	{"714", 0, "NHRN AIM", "X..20"},
+	{"715", 0, "NHRN NDC", "X..20"},
+	{"723", 10, "CERT #", "X2+X..28"},
+	{"7240", 0, "PROTOCOL", "X..20"},
+	{"7241", 0, "AIDC MEDIA TYPE", "N2"},
+	{"7242", 0, "VCN", "X..25"},
+	{"8001", 0, "DIMENSIONS", "N14"},
+	{"8002", 0, "CMT No.", "X..20"},
+	{"8003", 0, "GRAI", "N1,zero+N13,csum+X0..16"},
+	{"8004", 0, "GIAI", "X..30"},
+	{"8005", 0, "PRICE PER UNIT", "N6"},
+	{"8006", 0, "ITIP", "N14,csum+N2+N2"},
+	{"8007", 0, "IBAN", "X..34"},
+	{"8008", 0, "PROD TIME", "N8,yymmddhh+N0..4"},
+	{"8009", 0, "OPTSEN", "X..50"},
+	{"8010", 0, "CPID", "Y..30"},
+	{"8011", 0, "CPID SERIAL", "N..12"},
+	{"8012", 0, "VERSION", "X..20"},
+	{"8013", 0, "GMN", "X..25"},
+	{"8017", 0, "GSRN - PROVIDER", "N18,csum"},
+	{"8018", 0, "GSRN - RECIPIENT", "N18,csum"},
+	{"8019", 0, "SRIN", "N..10"},
+	{"8020", 0, "REF No.", "X..25"},
+	{"8026", 0, "ITIP CONTENT", "N14,csum+N2+N2"},
+	{"8110", 0, "", "X..70"},
+	{"8111", 0, "POINTS", "N4"},
+	{"8112", 0, "", "X..70"},
+	{"8200", 0, "PRODUCT URL", "X..70"},
+	{"90", 0, "INTERNAL", "X..30"},
+	{"91", 0, "INTERNAL", "X..90"},
+	{"92", 0, "INTERNAL", "X..90"},
+	{"93", 0, "INTERNAL", "X..90"},
+	{"94", 0, "INTERNAL", "X..90"},
+	{"95", 0, "INTERNAL", "X..90"},
+	{"96", 0, "INTERNAL", "X..90"},
+	{"97", 0, "INTERNAL", "X..90"},
+	{"98", 0, "INTERNAL", "X..90"},
+	{"99", 0, "INTERNAL", "X..90"},
+}
+
+// predefinedPrefixes are the AI prefixes with a predefined data length.
+var predefinedPrefixes = []string{
+	"00", "01", "02", "03", "04", "11", "12", "13", "14", "15", "16", "17", "18", "19", "20",
+	"31", "32", "33", "34", "35", "36", "41",
+}
+
+var aiTable = func() map[string]*AIInfo {
+	table := make(map[string]*AIInfo)
+	add := func(ai, title, format string, decimals int) {
+		info := &AIInfo{AI: ai, Title: title, Format: format, Decimals: decimals, comps: parseFormat(format)}
+		for _, p := range predefinedPrefixes {
+			if strings.HasPrefix(ai, p) {
+				info.Predefined = true
+			}
+		}
+		table[ai] = info
+	}
+	for _, d := range aiDefs {
+		if d.variants == 0 {
+			add(d.ai, d.title, d.format, -1)
+			continue
+		}
+		for n := 0; n < d.variants; n++ {
+			decimals := -1
+			if d.ai[0] == '3' {
+				decimals = n
+			}
+			add(d.ai+strconv.Itoa(n), d.title, d.format, decimals)
+		}
+	}
+	return table
+}()
+
+// parseFormat parses a format of the AI table. It panics on malformed
+// formats, which are programming errors.
+func parseFormat(format string) []component {
+	var comps []component
+	for _, f := range strings.Split(format, "+") {
+		spec, check, _ := strings.Cut(f, ",")
+		c := component{charset: spec[0], check: check}
+		lo, hi, variable := strings.Cut(spec[1:], "..")
+		var err error
+		if !variable {
+			hi = lo
+		}
+		if lo == "" {
+			lo = "1"
+		}
+		if c.min, err = strconv.Atoi(lo); err == nil {
+			c.max, err = strconv.Atoi(hi)
+		}
+		if err != nil {
+			panic("gs1: bad AI format " + format)
+		}
+		comps = append(comps, c)
+	}
+	return comps
+}
+
+// Lookup returns the table entry of an application identifier.
+func Lookup(ai string) (AIInfo, bool) {
+	info, ok := aiTable[ai]
+	if !ok {
+		return AIInfo{}, false
+	}
+	return *info, true
+}
+
+// fixedLength returns the data length of an AI whose components are all of
+// fixed length, or -1.
+func (a *AIInfo) fixedLength() int {
+	n := 0
+	for _, c := range a.comps {
+		if c.min != c.max {
+			return -1
+		}
+		n += c.max
+	}
+	return n
+}
+
+// validate checks data against the format of the AI.
+func (a *AIInfo) validate(data string) error {
+	if data == "" {
+		return errorf(a.AI, -1, ErrInvalidLength, "no data")
+	}
+	pos := 0
+	for i, c := range a.comps {
+		n := c.max
+		if i == len(a.comps)-1 {
+			n = len(data) - pos
+		}
+		if n > len(data)-pos {
+			return errorf(a.AI, len(data), ErrInvalidLength, "format %s needs more than %d characters", a.Format, len(data))
+		}
+		if n < c.min || n > c.max {
+			return errorf(a.AI, pos, ErrInvalidLength, "%d characters do not match format %s", len(data), a.Format)
+		}
+		if err := c.validate(a.AI, data[pos:pos+n], pos); err != nil {
+			return err
+		}
+		pos += n
+	}
+	return nil
+}
+
+func (c *component) validate(ai, s string, pos int) error {
+	for i := 0; i < len(s); i++ {
+		if !inCharset(c.charset, s[i]) {
+			return errorf(ai, pos+i, ErrInvalidChar, "%q is not allowed", s[i])
+		}
+	}
+	if s == "" {
+		return nil
+	}
+	switch c.check {
+	case "csum":
+		if want := CheckDigit(s[:len(s)-1]); s[len(s)-1] != want {
+			return errorf(ai, pos+len(s)-1, ErrCheckDigit, "want %c, got %c", want, s[len(s)-1])
+		}
+	case "yymmdd", "yymmd0", "yymmddhh", "yymmddhhmm":
+		if !validDate(c.check, s) {
+			return errorf(ai, pos, ErrInvalidDate, "%s is not a valid %s", s, strings.ToUpper(c.check))
+		}
+	case "yesno":
+		if s != "0" && s != "1" {
+			return errorf(ai, pos, ErrInvalidChar, "must be 0 or 1")
+		}
+	case "zero":
+		if strings.Trim(s, "0") != "" {
+			return errorf(ai, pos, ErrInvalidChar, "must be 0")
+		}
+	}
+	return nil
+}
+
+// validDate checks a date field; the digits have already been checked.
+// YYMMD0 dates may have day 00, meaning the last day of the month.
+func validDate(layout, s string) bool {
+	if len(s) != len(layout) {
+		return false
+	}
+	if layout == "yymmd0" && s[4:6] == "00" {
+		s = s[:4] + "01"
+	}
+	if _, err := time.Parse("060102", s[:6]); err != nil {
+		return false
+	}
+	if len(s) >= 8 && s[6:8] > "23" {
+		return false
+	}
+	return len(s) < 10 || s[8:10] <= "59"
+}
+
+// cset82 is GS1 AI encodable character set 82 besides letters and digits.
+const cset82 = "!\"%&'()*+,-./:;<=>?_"
+
+func inCharset(charset, b byte) bool {
+	digit := b >= '0' && b <= '9'
+	upper := b >= 'A' && b <= 'Z'
+	switch charset {
+	case 'N':
+		return digit
+	case 'Y':
+		return digit || upper || b == '#' || b == '-' || b == '/'
+	default:
+		return digit || upper || b >= 'a' && b <= 'z' || strings.IndexByte(cset82, b) >= 0
+	}
+}
+
+// CheckDigit returns the GS1 modulo-10 check digit for a string of digits,
+// as used by GTINs, SSCCs and GLNs.
+func CheckDigit(body string) byte {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		d := int(body[len(body)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
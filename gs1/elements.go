@@ -0,0 +1,112 @@
+package gs1
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ─── Element constructors ──────────────────────────────────────────────────
+//
+// The constructors only format their arguments; values that do not fit the
+// AI, such as a negative weight, produce an element that Build rejects.
+
+// GTIN returns AI (01). GTIN-8, GTIN-12 and GTIN-13 are padded with
+// leading zeros to 14 digits; the check digit must be included.
+func GTIN(gtin string) Element { return Element{AI: "01", Data: padGTIN(gtin)} }
+
+// Content returns AI (02), the GTIN of the trade items contained in a
+// logistic unit.
+func Content(gtin string) Element { return Element{AI: "02", Data: padGTIN(gtin)} }
+
+// SSCC returns AI (00), the Serial Shipping Container Code of 18 digits.
+func SSCC(sscc string) Element { return Element{AI: "00", Data: sscc} }
+
+// Batch returns AI (10), the batch or lot number.
+func Batch(lot string) Element { return Element{AI: "10", Data: lot} }
+
+// Serial returns AI (21), the serial number.
+func Serial(serial string) Element { return Element{AI: "21", Data: serial} }
+
+// ProductionDate returns AI (11).
+func ProductionDate(t time.Time) Element { return dateElement("11", t) }
+
+// PackagingDate returns AI (13).
+func PackagingDate(t time.Time) Element { return dateElement("13", t) }
+
+// BestBefore returns AI (15).
+func BestBefore(t time.Time) Element { return dateElement("15", t) }
+
+// SellBy returns AI (16).
+func SellBy(t time.Time) Element { return dateElement("16", t) }
+
+// Expiry returns AI (17), the expiration date.
+func Expiry(t time.Time) Element { return dateElement("17", t) }
+
+// Variant returns AI (20), the two-digit product variant.
+func Variant(v int) Element { return Element{AI: "20", Data: fmt.Sprintf("%02d", v)} }
+
+// Count returns AI (37), the count of trade items in a logistic unit.
+func Count(n int) Element { return Element{AI: "37", Data: strconv.Itoa(n)} }
+
+// NetWeightKg returns AI (310n) with the weight rounded to decimals
+// (0–5) places: NetWeightKg(1.25, 3) is (3103)001250.
+func NetWeightKg(kg float64, decimals int) Element { return measure("310", kg, decimals) }
+
+// NetWeightLb returns AI (320n), the net weight in pounds.
+func NetWeightLb(lb float64, decimals int) Element { return measure("320", lb, decimals) }
+
+// GrossWeightKg returns AI (330n), the logistic gross weight in kilograms.
+func GrossWeightKg(kg float64, decimals int) Element { return measure("330", kg, decimals) }
+
+// LengthM returns AI (311n), the length in metres.
+func LengthM(m float64, decimals int) Element { return measure("311", m, decimals) }
+
+// NetVolumeL returns AI (315n), the net volume in litres.
+func NetVolumeL(l float64, decimals int) Element { return measure("315", l, decimals) }
+
+// Amount returns AI (390n), the amount payable in local currency, rounded
+// to decimals (0–9) places.
+func Amount(amount float64, decimals int) Element { return scaled("390", amount, decimals, 0) }
+
+// OrderNumber returns AI (400), the customer's purchase order number.
+func OrderNumber(order string) Element { return Element{AI: "400", Data: order} }
+
+// ShipTo returns AI (410), the GLN of the ship-to location.
+func ShipTo(gln string) Element { return Element{AI: "410", Data: gln} }
+
+// Origin returns AI (422), the ISO 3166 numeric country of origin, e.g.
+// 392 for Japan.
+func Origin(country int) Element { return Element{AI: "422", Data: fmt.Sprintf("%03d", country)} }
+
+// Internal returns AI (90) to (99), information agreed between trading
+// partners or for internal use.
+func Internal(ai int, data string) Element { return Element{AI: strconv.Itoa(ai), Data: data} }
+
+func padGTIN(gtin string) string {
+	switch len(gtin) {
+	case 8, 12, 13:
+		return strings.Repeat("0", 14-len(gtin)) + gtin
+	}
+	return gtin
+}
+
+func dateElement(ai string, t time.Time) Element {
+	return Element{AI: ai, Data: t.Format("060102")}
+}
+
+// measure formats a six-digit measure AI.
+func measure(ai string, v float64, decimals int) Element { return scaled(ai, v, decimals, 6) }
+
+// scaled formats v as an integer with an implied decimal point, zero-padded
+// to width digits; the AI's last digit gives the decimals. Out-of-range
+// values are written as is, so that validation reports them.
+func scaled(ai string, v float64, decimals, width int) Element {
+	ai += strconv.Itoa(decimals)
+	if v < 0 || decimals < 0 {
+		return Element{AI: ai, Data: strconv.FormatFloat(v, 'f', -1, 64)}
+	}
+	return Element{AI: ai, Data: fmt.Sprintf("%0*d", width, int64(math.Round(v*math.Pow10(decimals))))}
+}
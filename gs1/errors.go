@@ -0,0 +1,58 @@
+package gs1
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors wrapped by *Error; test them with errors.Is.
+var (
+	// ErrSyntax means an element string could not be split into elements.
+	ErrSyntax = errors.New("malformed element string")
+	// ErrUnknownAI means an AI is not in the GS1 AI table.
+	ErrUnknownAI = errors.New("unknown application identifier")
+	// ErrInvalidLength means the data of an element is too short or too
+	// long for its AI.
+	ErrInvalidLength = errors.New("invalid data length")
+	// ErrInvalidChar means the data contains a character its AI does not
+	// allow.
+	ErrInvalidChar = errors.New("invalid character")
+	// ErrCheckDigit means a GS1 check digit is wrong.
+	ErrCheckDigit = errors.New("invalid check digit")
+	// ErrInvalidDate means a date or time field is not a valid date.
+	ErrInvalidDate = errors.New("invalid date")
+	// ErrDuplicateAI means an AI occurs more than once.
+	ErrDuplicateAI = errors.New("duplicate application identifier")
+)
+
+// Error describes an invalid element or element string.
+type Error struct {
+	// AI is the application identifier of the invalid element, or "" if
+	// the string could not be split into elements.
+	AI string
+	// Position is the byte offset of the problem in the element's data,
+	// or, when AI is "", in the string passed to Parse. It is -1 if the
+	// problem has no position.
+	Position int
+	// Err is one of the sentinel errors of this package.
+	Err error
+	// Detail is a human-readable description of the problem.
+	Detail string
+}
+
+func (e *Error) Error() string {
+	msg := "gs1: " + e.Err.Error()
+	if e.AI != "" {
+		msg = fmt.Sprintf("gs1: AI (%s): %s", e.AI, e.Err)
+	}
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+func errorf(ai string, pos int, err error, format string, args ...any) *Error {
+	return &Error{AI: ai, Position: pos, Err: err, Detail: fmt.Sprintf(format, args...)}
+}
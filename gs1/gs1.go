@@ -0,0 +1,244 @@
+// Package gs1 builds and parses GS1 element strings, the data carried by
+// GS1-128 and GS1 DataBar Expanded symbols.
+//
+// An element string is a sequence of elements, each an Application
+// Identifier (AI) and its data. Build validates the elements against the
+// GS1 AI table, and the result renders both forms the barcode generators
+// need:
+//
+//	s, err := gs1.Build(
+//		gs1.GTIN("04912345123459"),
+//		gs1.Expiry(time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)),
+//		gs1.Batch("ABC123"),
+//		gs1.NetWeightKg(1.25, 3),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	img, err := gs1128.Draw(s.Data(), 600, 120) // "01049123451234591726123110ABC123{FNC1}3103001250"
+//	label := s.HRI()                            // "(01)04912345123459(17)261231(10)ABC123(3103)001250"
+package gs1
+
+import "strings"
+
+// Element is one AI and its data. The constructors in this package format
+// the data of common AIs; other AIs can be written as literals:
+//
+//	gs1.Element{AI: "422", Data: "392"}
+type Element struct {
+	AI   string
+	Data string
+}
+
+// Validate checks the element against the AI table: a known AI, the
+// length and character set of its data, and check digits and dates.
+func (e Element) Validate() error {
+	info, ok := aiTable[e.AI]
+	if !ok {
+		return errorf(e.AI, -1, ErrUnknownAI, "not in the GS1 AI table")
+	}
+	return info.validate(e.Data)
+}
+
+// ElementString is a sequence of elements.
+type ElementString []Element
+
+// Build returns the element string of elems after validating it.
+func Build(elems ...Element) (ElementString, error) {
+	s := ElementString(elems)
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate checks every element and that no AI occurs twice. It returns
+// the *Error of the first problem found.
+func (s ElementString) Validate() error {
+	if len(s) == 0 {
+		return errorf("", -1, ErrSyntax, "no elements")
+	}
+	seen := make(map[string]bool, len(s))
+	for _, e := range s {
+		if err := e.Validate(); err != nil {
+			return err
+		}
+		if seen[e.AI] {
+			return errorf(e.AI, -1, ErrDuplicateAI, "")
+		}
+		seen[e.AI] = true
+	}
+	return nil
+}
+
+// Get returns the data of the first element with the given AI.
+func (s ElementString) Get(ai string) (string, bool) {
+	for _, e := range s {
+		if e.AI == ai {
+			return e.Data, true
+		}
+	}
+	return "", false
+}
+
+// Data returns the encodable form passed to the Draw methods of GS1-128
+// and GS1 DataBar Expanded: AIs and data concatenated, with "{FNC1}" after
+// each element that has no predefined length, except the last. The FNC1
+// that starts the symbol is added by the encoder.
+func (s ElementString) Data() string {
+	var b strings.Builder
+	for i, e := range s {
+		b.WriteString(e.AI)
+		b.WriteString(e.Data)
+		if i < len(s)-1 && !predefined(e.AI) {
+			b.WriteString("{FNC1}")
+		}
+	}
+	return b.String()
+}
+
+// HRI returns the human-readable interpretation, with each AI in
+// parentheses: "(01)04912345123459(10)ABC123".
+func (s ElementString) HRI() string {
+	var b strings.Builder
+	for _, e := range s {
+		b.WriteString("(" + e.AI + ")" + e.Data)
+	}
+	return b.String()
+}
+
+// String returns the HRI form.
+func (s ElementString) String() string { return s.HRI() }
+
+// predefined reports whether an AI has a predefined length. Unknown AIs
+// are treated as variable-length, so they are always terminated.
+func predefined(ai string) bool {
+	info, ok := aiTable[ai]
+	return ok && info.Predefined
+}
+
+// symbologyIDs are the AIM symbology identifiers a scanner may prefix to
+// GS1 data.
+var symbologyIDs = []string{"]C1", "]e0", "]d2", "]Q3", "]J1"}
+
+// Parse parses an element string in HRI form, "(01)04912345123459(10)ABC",
+// or in encodable form with FNC1 written as "{FNC1}" or GS (ASCII 29), as
+// returned by Data or by a scanner. A leading FNC1 or AIM symbology
+// identifier is skipped. The result is validated like Build's.
+func Parse(code string) (ElementString, error) {
+	var s ElementString
+	var err error
+	if strings.HasPrefix(code, "(") {
+		s, err = parseHRI(code)
+	} else {
+		s, err = parseData(code)
+	}
+	if err == nil {
+		err = s.Validate()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// parseHRI splits "(AI)data(AI)data…". The data of a predefined-length AI
+// ends after its length; other data ends at the next "(" that opens a
+// known AI, so data may itself contain parentheses.
+func parseHRI(code string) (ElementString, error) {
+	var s ElementString
+	for i := 0; i < len(code); {
+		ai, n := hriAI(code[i:])
+		if n == 0 {
+			return nil, errorf("", i, ErrSyntax, "expected \"(AI)\"")
+		}
+		if _, ok := aiTable[ai]; !ok {
+			return nil, errorf(ai, -1, ErrUnknownAI, "at position %d", i)
+		}
+		i += n
+		end := len(code)
+		if info := aiTable[ai]; info.Predefined && i+info.fixedLength() < end {
+			end = i + info.fixedLength()
+		} else {
+			for j := i + 1; j < len(code); j++ {
+				if next, m := hriAI(code[j:]); m > 0 && aiTable[next] != nil {
+					end = j
+					break
+				}
+			}
+		}
+		s = append(s, Element{AI: ai, Data: code[i:end]})
+		i = end
+	}
+	return s, nil
+}
+
+// hriAI reads "(AI)" at the start of s and returns the AI and the length
+// read, or 0 if s does not start with one.
+func hriAI(s string) (string, int) {
+	if !strings.HasPrefix(s, "(") {
+		return "", 0
+	}
+	end := strings.IndexByte(s, ')')
+	if end < 3 || end > 5 || strings.Trim(s[1:end], "0123456789") != "" {
+		return "", 0
+	}
+	return s[1:end], end + 1
+}
+
+// parseData splits the encodable form. AIs are recognised by the AI
+// table, whose AIs are prefix-free.
+func parseData(code string) (ElementString, error) {
+	i := 0
+	for _, id := range symbologyIDs {
+		if strings.HasPrefix(code, id) {
+			i = len(id)
+			break
+		}
+	}
+	var s ElementString
+	for {
+		for n := separatorLen(code[i:]); n > 0; n = separatorLen(code[i:]) {
+			i += n
+		}
+		if i == len(code) {
+			break
+		}
+		var info *AIInfo
+		for n := 2; n <= 4 && info == nil && i+n <= len(code); n++ {
+			info = aiTable[code[i:i+n]]
+		}
+		if info == nil {
+			return nil, errorf("", i, ErrUnknownAI, "no known AI at position %d", i)
+		}
+		i += len(info.AI)
+		end := len(code)
+		if n := info.fixedLength(); info.Predefined && i+n < end {
+			end = i + n
+		}
+		for j := i; j < end; j++ {
+			if separatorLen(code[j:]) > 0 {
+				end = j
+				break
+			}
+		}
+		s = append(s, Element{AI: info.AI, Data: code[i:end]})
+		i = end
+	}
+	if len(s) == 0 {
+		return nil, errorf("", -1, ErrSyntax, "no elements")
+	}
+	return s, nil
+}
+
+// separatorLen returns the length of the FNC1 separator at the start of s,
+// or 0.
+func separatorLen(s string) int {
+	switch {
+	case strings.HasPrefix(s, "{FNC1}"):
+		return len("{FNC1}")
+	case strings.HasPrefix(s, "\x1d"):
+		return 1
+	}
+	return 0
+}
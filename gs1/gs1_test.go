@@ -0,0 +1,190 @@
+package gs1_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pao-xx/barcode-pao/gs1"
+)
+
+func TestRoundTrip(t *testing.T) {
+	expiry := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		elems []gs1.Element
+		data  string
+		hri   string
+	}{
+		{
+			elems: []gs1.Element{gs1.GTIN("04912345123459"), gs1.Expiry(expiry), gs1.Batch("ABC123"), gs1.NetWeightKg(1.25, 3)},
+			data:  "01049123451234591726123110ABC123{FNC1}3103001250",
+			hri:   "(01)04912345123459(17)261231(10)ABC123(3103)001250",
+		},
+		{
+			// A GTIN-13 is padded to 14 digits; the last element has no
+			// separator, whatever its length.
+			elems: []gs1.Element{gs1.GTIN("4901234567894"), gs1.Serial("X(1)-2/3")},
+			data:  "010490123456789421X(1)-2/3",
+			hri:   "(01)04901234567894(21)X(1)-2/3",
+		},
+		{
+			elems: []gs1.Element{gs1.SSCC("106141411234567897"), gs1.Count(24), gs1.Content("04912345123459"), gs1.Amount(1234.5, 2), gs1.Origin(392)},
+			data:  "001061414112345678973724{FNC1}02049123451234593902123450{FNC1}422392",
+			hri:   "(00)106141411234567897(37)24(02)04912345123459(3902)123450(422)392",
+		},
+		{
+			elems: []gs1.Element{gs1.Internal(91, "ABC"), {AI: "3940", Data: "0050"}, {AI: "3955", Data: "012345"}},
+			data:  "91ABC{FNC1}39400050{FNC1}3955012345",
+			hri:   "(91)ABC(3940)0050(3955)012345",
+		},
+	}
+	for _, tt := range tests {
+		s, err := gs1.Build(tt.elems...)
+		if err != nil {
+			t.Errorf("Build(%v): %v", tt.elems, err)
+			continue
+		}
+		if got := s.Data(); got != tt.data {
+			t.Errorf("Data() = %q, want %q", got, tt.data)
+		}
+		if got := s.HRI(); got != tt.hri {
+			t.Errorf("HRI() = %q, want %q", got, tt.hri)
+		}
+		for _, form := range []string{s.Data(), s.HRI(), strings.ReplaceAll(s.Data(), "{FNC1}", "\x1d"), "]C1" + s.Data()} {
+			p, err := gs1.Parse(form)
+			if err != nil {
+				t.Errorf("Parse(%q): %v", form, err)
+				continue
+			}
+			if p.HRI() != s.HRI() {
+				t.Errorf("Parse(%q) = %s, want %s", form, p.HRI(), s.HRI())
+			}
+		}
+	}
+}
+
+func TestLengthLimits(t *testing.T) {
+	tests := []struct {
+		ai, data string
+		err      error
+	}{
+		// Fixed length.
+		{"20", "01", nil},
+		{"20", "1", gs1.ErrInvalidLength},
+		{"20", "012", gs1.ErrInvalidLength},
+		{"3103", "001250", nil},
+		{"3103", "01250", gs1.ErrInvalidLength},
+		{"3103", "0012500", gs1.ErrInvalidLength},
+		{"11", "261231", nil},
+		{"11", "2612310", gs1.ErrInvalidLength},
+		// Variable length.
+		{"10", "A", nil},
+		{"10", strings.Repeat("A", 20), nil},
+		{"10", strings.Repeat("A", 21), gs1.ErrInvalidLength},
+		{"10", "", gs1.ErrInvalidLength},
+		{"37", "12345678", nil},
+		{"37", "123456789", gs1.ErrInvalidLength},
+		{"3902", strings.Repeat("9", 15), nil},
+		{"3902", strings.Repeat("9", 16), gs1.ErrInvalidLength},
+		// A fixed component followed by a variable one.
+		{"3912", "392" + strings.Repeat("1", 15), nil},
+		{"3912", "39", gs1.ErrInvalidLength},
+		{"3912", "392" + strings.Repeat("1", 16), gs1.ErrInvalidLength},
+		// Character sets and dates.
+		{"37", "12A", gs1.ErrInvalidChar},
+		{"10", "AB C", gs1.ErrInvalidChar},
+		{"17", "261300", gs1.ErrInvalidDate},
+		{"17", "261200", nil},
+	}
+	for _, tt := range tests {
+		err := gs1.Element{AI: tt.ai, Data: tt.data}.Validate()
+		if !errors.Is(err, tt.err) || (tt.err == nil) != (err == nil) {
+			t.Errorf("(%s)%s: %v, want %v", tt.ai, tt.data, err, tt.err)
+		}
+	}
+}
+
+func TestCheckDigits(t *testing.T) {
+	tests := []struct {
+		ai, data string
+		ok       bool
+	}{
+		{"00", "106141411234567897", true},
+		{"00", "106141411234567890", false},
+		{"01", "04912345123459", true},
+		{"01", "04912345123458", false},
+		{"01", "04901234567894", true},
+		{"02", "10012345678902", true},
+		{"02", "10012345678900", false},
+	}
+	for _, tt := range tests {
+		err := gs1.Element{AI: tt.ai, Data: tt.data}.Validate()
+		if tt.ok && err != nil {
+			t.Errorf("(%s)%s: %v", tt.ai, tt.data, err)
+		}
+		if !tt.ok {
+			var e *gs1.Error
+			if !errors.As(err, &e) || !errors.Is(err, gs1.ErrCheckDigit) || e.Position != len(tt.data)-1 {
+				t.Errorf("(%s)%s: %v, want ErrCheckDigit at %d", tt.ai, tt.data, err, len(tt.data)-1)
+			}
+		}
+		if got := gs1.CheckDigit(tt.data[:len(tt.data)-1]); tt.ok && got != tt.data[len(tt.data)-1] {
+			t.Errorf("CheckDigit(%s) = %c", tt.data[:len(tt.data)-1], got)
+		}
+	}
+}
+
+// AI 394n exists for n = 0–3 and 395n for n = 0–5.
+func TestAIVariantBoundaries(t *testing.T) {
+	tests := []struct {
+		ai       string
+		known    bool
+		decimals int
+	}{
+		{"3940", true, 0},
+		{"3943", true, 3},
+		{"3944", false, 0},
+		{"3950", true, 0},
+		{"3955", true, 5},
+		{"3956", false, 0},
+		{"3105", true, 5},
+		{"3106", false, 0},
+		{"3909", true, 9},
+	}
+	for _, tt := range tests {
+		info, ok := gs1.Lookup(tt.ai)
+		if ok != tt.known {
+			t.Errorf("Lookup(%s): known %v, want %v", tt.ai, ok, tt.known)
+			continue
+		}
+		if ok && info.Decimals != tt.decimals {
+			t.Errorf("Lookup(%s).Decimals = %d, want %d", tt.ai, info.Decimals, tt.decimals)
+		}
+		if !ok {
+			if _, err := gs1.Parse("(" + tt.ai + ")0001"); !errors.Is(err, gs1.ErrUnknownAI) {
+				t.Errorf("Parse (%s): %v, want ErrUnknownAI", tt.ai, err)
+			}
+		}
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	tests := []struct {
+		elems []gs1.Element
+		err   error
+	}{
+		{nil, gs1.ErrSyntax},
+		{[]gs1.Element{{AI: "99999", Data: "1"}}, gs1.ErrUnknownAI},
+		{[]gs1.Element{gs1.Batch("A"), gs1.Batch("B")}, gs1.ErrDuplicateAI},
+		{[]gs1.Element{gs1.NetWeightKg(-1, 3)}, gs1.ErrInvalidLength},
+	}
+	for _, tt := range tests {
+		if _, err := gs1.Build(tt.elems...); !errors.Is(err, tt.err) {
+			t.Errorf("Build(%v) = %v, want %v", tt.elems, err, tt.err)
+		}
+	}
+	if _, err := gs1.Parse("(01)04912345123459(10"); err == nil {
+		t.Error("Parse accepted a truncated AI")
+	}
+}
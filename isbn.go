@@ -0,0 +1,428 @@
+package barcode_pao
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ─── Publication identifiers: ISBN, ISSN, ISMN ─────────────────────────────
+//
+// Books, serials and printed music are marked with a JAN-13 under a GS1
+// prefix of their own (978/979 for ISBN, 977 for ISSN, 9790 for ISMN), with
+// the identifier printed in hyphenated form above the bars. The ISBN, ISSN
+// and ISMN generators draw that layout from the identifier as written; the
+// functions below validate, convert and hyphenate identifiers on their own.
+
+// identifier converts a publication identifier to the JAN-13 it is drawn
+// as.
+type identifier struct {
+	// name is the registry name, in upper case, and the symbology of
+	// DrawError.
+	name string
+	// toJAN returns the JAN-13 with its check digit and the header line.
+	// Under CheckDigitLenient a wrong check digit is recomputed.
+	toJAN func(code string, o *settings) (jan, header string, err error)
+}
+
+var (
+	isbnIdentifier = &identifier{name: "ISBN", toJAN: func(code string, o *settings) (string, string, error) {
+		jan, err := isbnJAN(code, o.checkDigitPolicy == CheckDigitLenient)
+		if err != nil {
+			return "", "", err
+		}
+		h, ok := hyphenateISBN(jan)
+		if !ok {
+			return "", "", fmt.Errorf("%w: %s", ErrUnknownISBNRange, jan)
+		}
+		return jan, "ISBN " + h, nil
+	}}
+	issnIdentifier = &identifier{name: "ISSN", toJAN: func(code string, o *settings) (string, string, error) {
+		jan, issn, err := issnJAN(code, o.issnVariant, o.checkDigitPolicy == CheckDigitLenient)
+		if err != nil {
+			return "", "", err
+		}
+		return jan, "ISSN " + issn[:4] + "-" + issn[4:], nil
+	}}
+	ismnIdentifier = &identifier{name: "ISMN", toJAN: func(code string, o *settings) (string, string, error) {
+		jan, err := ismnJAN(code, o.checkDigitPolicy == CheckDigitLenient)
+		if err != nil {
+			return "", "", err
+		}
+		return jan, "ISMN " + hyphenateISMN(jan), nil
+	}}
+)
+
+// ISBN generates the JAN-13 of a book (Bookland EAN) from its ISBN-10 or
+// ISBN-13, with "ISBN 978-…" printed above the bars. Draw accepts the ISBN
+// with or without hyphens and "ISBN" prefix; a price add-on is set with
+// SetAddOn. It always draws with the pure-Go backend. An ISBN whose range
+// is not in the range table cannot be hyphenated, and Draw reports it with
+// a *DrawError wrapping ErrUnknownISBNRange.
+type ISBN struct{ Jan13 }
+
+// NewISBN creates an ISBN barcode generator.
+// It panics on failure; use NewISBNE to handle errors.
+func NewISBN(outputFormat string) *ISBN {
+	return must(NewISBNE(outputFormat))
+}
+
+// NewISBNE creates an ISBN barcode generator. It does not need the native
+// library.
+func NewISBNE(outputFormat string) (*ISBN, error) {
+	return &ISBN{Jan13{Barcode1DBase{newIdentifierBase(isbnIdentifier, outputFormat)}}}, nil
+}
+
+// ISSN generates the JAN-13 of a serial from its ISSN, with "ISSN
+// 1234-5679" printed above the bars. The issue number usually goes into
+// an EAN-2 add-on set with SetAddOn. It always draws with the pure-Go
+// backend.
+type ISSN struct{ Jan13 }
+
+// NewISSN creates an ISSN barcode generator.
+// It panics on failure; use NewISSNE to handle errors.
+func NewISSN(outputFormat string) *ISSN {
+	return must(NewISSNE(outputFormat))
+}
+
+// NewISSNE creates an ISSN barcode generator. It does not need the native
+// library.
+func NewISSNE(outputFormat string) (*ISSN, error) {
+	return &ISSN{Jan13{Barcode1DBase{newIdentifierBase(issnIdentifier, outputFormat)}}}, nil
+}
+
+// SetVariant sets the two digits that follow the ISSN in the JAN-13, used
+// by publishers to tell apart editions or prices of the same serial.
+// The default is "00".
+func (b *ISSN) SetVariant(variant string) error {
+	if len(variant) != 2 || strings.Trim(variant, digitChars) != "" {
+		return fmt.Errorf("%w: ISSN variant must be 2 digits, got %q", ErrInvalidOption, variant)
+	}
+	return b.set(func(o *settings) { o.issnVariant = variant }, nil)
+}
+
+// GetVariant returns the value set by SetVariant.
+func (b *ISSN) GetVariant() string {
+	return get(b.BarcodeBase, func(o *settings) string { return o.issnVariant })
+}
+
+// ISMN generates the JAN-13 of printed music from its ISMN, with "ISMN
+// 979-0-…" printed above the bars. It always draws with the pure-Go
+// backend.
+type ISMN struct{ Jan13 }
+
+// NewISMN creates an ISMN barcode generator.
+// It panics on failure; use NewISMNE to handle errors.
+func NewISMN(outputFormat string) *ISMN {
+	return must(NewISMNE(outputFormat))
+}
+
+// NewISMNE creates an ISMN barcode generator. It does not need the native
+// library.
+func NewISMNE(outputFormat string) (*ISMN, error) {
+	return &ISMN{Jan13{Barcode1DBase{newIdentifierBase(ismnIdentifier, outputFormat)}}}, nil
+}
+
+func newIdentifierBase(ident *identifier, outputFormat string) *BarcodeBase {
+	b := newPureGoBase(typeJan13, outputFormat)
+	b.ident = ident
+	return b
+}
+
+// ISBN13 validates an ISBN-10 or ISBN-13 and returns the 13 digits of the
+// ISBN-13. Hyphens, spaces and an "ISBN", "ISBN-10:" or "ISBN-13:" prefix
+// are ignored. Invalid input is reported as a *DrawError.
+func ISBN13(isbn string) (string, error) {
+	jan, err := isbnJAN(isbn, false)
+	if err != nil {
+		return "", namedError("ISBN", isbn, err)
+	}
+	return jan, nil
+}
+
+// ISBN10 validates an ISBN and returns its ISBN-10 form, which exists only
+// for ISBNs with the prefix 978.
+func ISBN10(isbn string) (string, error) {
+	jan, err := ISBN13(isbn)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(jan, "978") {
+		return "", namedError("ISBN", isbn, errDraw(ReasonInvalidCharacter, "an ISBN with prefix %s has no ISBN-10 form", jan[:3]))
+	}
+	return jan[3:12] + string(mod11CheckDigit(jan[3:12])), nil
+}
+
+// HyphenateISBN returns an ISBN as a hyphenated ISBN-13, e.g.
+// "978-4-06-519981-7". The registration group and registrant elements
+// come from the range table in isbnranges.go; an ISBN outside it is
+// reported with an error wrapping ErrUnknownISBNRange.
+func HyphenateISBN(isbn string) (string, error) {
+	jan, err := ISBN13(isbn)
+	if err != nil {
+		return "", err
+	}
+	h, ok := hyphenateISBN(jan)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownISBNRange, jan)
+	}
+	return h, nil
+}
+
+// ISSNToJAN validates an ISSN and returns the JAN-13 it is printed as:
+// 977, the first seven ISSN digits, the two-digit variant and a check
+// digit.
+func ISSNToJAN(issn, variant string) (string, error) {
+	if len(variant) != 2 || strings.Trim(variant, digitChars) != "" {
+		return "", fmt.Errorf("%w: ISSN variant must be 2 digits, got %q", ErrInvalidOption, variant)
+	}
+	jan, _, err := issnJAN(issn, variant, false)
+	if err != nil {
+		return "", namedError("ISSN", issn, err)
+	}
+	return jan, nil
+}
+
+// HyphenateISSN validates an ISSN, or the JAN-13 of one, and returns the
+// ISSN in its printed form "1234-5679".
+func HyphenateISSN(issn string) (string, error) {
+	_, s, err := issnJAN(issn, "00", false)
+	if err != nil {
+		return "", namedError("ISSN", issn, err)
+	}
+	return s[:4] + "-" + s[4:], nil
+}
+
+// ISMN13 validates an ISMN, in the former "M-2306-7118-7" form or as 13
+// digits, and returns the 13 digits of the ISMN.
+func ISMN13(ismn string) (string, error) {
+	jan, err := ismnJAN(ismn, false)
+	if err != nil {
+		return "", namedError("ISMN", ismn, err)
+	}
+	return jan, nil
+}
+
+// HyphenateISMN returns an ISMN as a hyphenated ISMN-13, e.g.
+// "979-0-2306-7118-7".
+func HyphenateISMN(ismn string) (string, error) {
+	jan, err := ISMN13(ismn)
+	if err != nil {
+		return "", err
+	}
+	return hyphenateISMN(jan), nil
+}
+
+// identDigits extracts the characters of an identifier written with an
+// optional scheme prefix ("ISBN", "ISBN-13:") and hyphens or spaces. The
+// letters listed in letters are kept, in upper case, besides the digits;
+// pos maps each kept character to its index in s.
+func identDigits(s, prefix, letters string) (string, []int, error) {
+	rs := []rune(s)
+	i := 0
+	for i < len(rs) && rs[i] == ' ' {
+		i++
+	}
+	if len(rs)-i >= len(prefix) && strings.EqualFold(string(rs[i:i+len(prefix)]), prefix) {
+		i += len(prefix)
+		if rest := string(rs[i:]); strings.HasPrefix(rest, "-10") || strings.HasPrefix(rest, "-13") {
+			i += 3
+		}
+		if i < len(rs) && rs[i] == ':' {
+			i++
+		}
+	}
+	var d []byte
+	var pos []int
+	for ; i < len(rs); i++ {
+		r := rs[i]
+		if r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		switch {
+		case isDigit(r) || r < 0x80 && strings.ContainsRune(letters, r):
+			d = append(d, byte(r))
+			pos = append(pos, i)
+		case r == '-' || r == ' ':
+		default:
+			return "", nil, errInvalidChar(i, rs[i], "")
+		}
+	}
+	if len(d) == 0 {
+		return "", nil, errDraw(ReasonEmptyInput, "no digits")
+	}
+	return string(d), pos, nil
+}
+
+// onlyDigits rejects a letter kept by identDigits anywhere but at index
+// keep.
+func onlyDigits(d string, pos []int, keep int, detail string) error {
+	for i := 0; i < len(d); i++ {
+		if !isDigit(rune(d[i])) && i != keep {
+			return errInvalidChar(pos[i], rune(d[i]), detail)
+		}
+	}
+	return nil
+}
+
+// checkIdentDigit compares the check character at index i of d with want.
+func checkIdentDigit(d string, pos []int, i int, want byte, lenient bool) error {
+	if d[i] != want && !lenient {
+		return errCheckDigit(pos[i], d[i], want)
+	}
+	return nil
+}
+
+// mod11CheckDigit returns the modulo-11 check character of ISBN-10 and
+// ISSN: weights descend to 2 at the last digit, and 10 is written X.
+func mod11CheckDigit(body string) byte {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		sum += (len(body) + 1 - i) * int(body[i]-'0')
+	}
+	if c := (11 - sum%11) % 11; c < 10 {
+		return byte('0' + c)
+	}
+	return 'X'
+}
+
+// withGS1CheckDigit appends the GS1 check digit to a 12-digit body.
+func withGS1CheckDigit(body string) string { return body + string(gs1CheckDigit(body)) }
+
+func isbnJAN(code string, lenient bool) (string, error) {
+	d, pos, err := identDigits(code, "ISBN", "X")
+	if err != nil {
+		return "", err
+	}
+	switch len(d) {
+	case 10:
+		if err := onlyDigits(d, pos, 9, "X is only allowed as the ISBN-10 check digit"); err != nil {
+			return "", err
+		}
+		if err := checkIdentDigit(d, pos, 9, mod11CheckDigit(d[:9]), lenient); err != nil {
+			return "", err
+		}
+		return withGS1CheckDigit("978" + d[:9]), nil
+	case 13:
+		if err := onlyDigits(d, pos, -1, "only digits are allowed"); err != nil {
+			return "", err
+		}
+		if d[:3] != "978" && d[:3] != "979" || d[:4] == "9790" {
+			return "", &DrawError{Reason: ReasonInvalidCharacter, Position: pos[0],
+				Detail: "ISBN-13 must start with 978 or 979 (9790 is ISMN)"}
+		}
+		if err := checkIdentDigit(d, pos, 12, gs1CheckDigit(d[:12]), lenient); err != nil {
+			return "", err
+		}
+		return withGS1CheckDigit(d[:12]), nil
+	}
+	return "", errDraw(ReasonInvalidLength, "expected 10 or 13 digits, got %d", len(d))
+}
+
+// issnJAN returns the JAN-13 and the eight-character ISSN of an ISSN or
+// its JAN-13.
+func issnJAN(code, variant string, lenient bool) (jan, issn string, err error) {
+	d, pos, err := identDigits(code, "ISSN", "X")
+	if err != nil {
+		return "", "", err
+	}
+	switch len(d) {
+	case 8:
+		if err := onlyDigits(d, pos, 7, "X is only allowed as the ISSN check digit"); err != nil {
+			return "", "", err
+		}
+		want := mod11CheckDigit(d[:7])
+		if err := checkIdentDigit(d, pos, 7, want, lenient); err != nil {
+			return "", "", err
+		}
+		return withGS1CheckDigit("977" + d[:7] + variant), d[:7] + string(want), nil
+	case 13:
+		if err := onlyDigits(d, pos, -1, "only digits are allowed"); err != nil {
+			return "", "", err
+		}
+		if d[:3] != "977" {
+			return "", "", &DrawError{Reason: ReasonInvalidCharacter, Position: pos[0],
+				Detail: "the JAN-13 of an ISSN starts with 977"}
+		}
+		if err := checkIdentDigit(d, pos, 12, gs1CheckDigit(d[:12]), lenient); err != nil {
+			return "", "", err
+		}
+		return withGS1CheckDigit(d[:12]), d[3:10] + string(mod11CheckDigit(d[3:10])), nil
+	}
+	return "", "", errDraw(ReasonInvalidLength, "expected 8 characters or 13 digits, got %d", len(d))
+}
+
+func ismnJAN(code string, lenient bool) (string, error) {
+	d, pos, err := identDigits(code, "ISMN", "M")
+	if err != nil {
+		return "", err
+	}
+	var body string
+	switch len(d) {
+	case 10:
+		if d[0] != 'M' {
+			return "", errInvalidChar(pos[0], rune(d[0]), "a 10-character ISMN starts with M")
+		}
+		if err := onlyDigits(d, pos, 0, "M is only allowed as the first character"); err != nil {
+			return "", err
+		}
+		body = "9790" + d[1:9]
+	case 13:
+		if err := onlyDigits(d, pos, -1, "only digits are allowed"); err != nil {
+			return "", err
+		}
+		if d[:4] != "9790" {
+			return "", &DrawError{Reason: ReasonInvalidCharacter, Position: pos[0],
+				Detail: "ISMN-13 must start with 9790"}
+		}
+		body = d[:12]
+	default:
+		return "", errDraw(ReasonInvalidLength, "expected M and 9 digits, or 13 digits, got %d", len(d))
+	}
+	// The ISMN-10 check digit, with M counted as 3, equals that of the
+	// ISMN-13.
+	if err := checkIdentDigit(d, pos, len(d)-1, gs1CheckDigit(body), lenient); err != nil {
+		return "", err
+	}
+	return withGS1CheckDigit(body), nil
+}
+
+//go:generate go run isbnranges_gen.go
+
+// hyphenateISBN splits a valid ISBN-13 into prefix, registration group,
+// registrant, publication and check digit.
+func hyphenateISBN(jan string) (string, bool) {
+	prefix, rest := jan[:3], jan[3:12]
+	group := rangeLength(isbnGroups[prefix], rest)
+	if group == 0 {
+		return "", false
+	}
+	reg := rangeLength(isbnRegistrants[prefix+"-"+rest[:group]], rest[group:])
+	if reg == 0 {
+		return "", false
+	}
+	return prefix + "-" + rest[:group] + "-" + rest[group:group+reg] + "-" + rest[group+reg:] + "-" + jan[12:], true
+}
+
+// ismnRegistrants are the publisher ranges of ISMN, fixed by the standard.
+var ismnRegistrants = []string{"000-099", "1000-3999", "40000-69999", "700000-899999", "9000000-9999999"}
+
+func hyphenateISMN(jan string) string {
+	n := rangeLength(ismnRegistrants, jan[4:12])
+	return "979-0-" + jan[4:4+n] + "-" + jan[4+n:12] + "-" + jan[12:]
+}
+
+// rangeLength returns the length of the element at the start of digits:
+// the length of the bounds of the range "lo-hi" that holds its leading
+// digits, or 0 if none does.
+func rangeLength(ranges []string, digits string) int {
+	for _, r := range ranges {
+		lo, hi, _ := strings.Cut(r, "-")
+		if len(lo) > len(digits) {
+			continue
+		}
+		if p := digits[:len(lo)]; p >= lo && p <= hi {
+			return len(lo)
+		}
+	}
+	return 0
+}
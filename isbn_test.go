@@ -0,0 +1,129 @@
+package barcode_pao
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestHyphenateISBN(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"9784065199817", "978-4-06-519981-7"},
+		{"ISBN978-4-06-519981-7", "978-4-06-519981-7"},
+		{"4-06-519981-6", "978-4-06-519981-7"},
+		{"ISBN-10: 4065199816", "978-4-06-519981-7"},
+		{"0-8044-2957-X", "978-0-8044-2957-3"},
+		{"9781402894626", "978-1-4028-9462-6"},
+		// Seven-digit registrants of the English language groups.
+		{"9780639800004", "978-0-6398000-0-4"},
+		{"9780645000009", "978-0-6450000-0-9"},
+		{"9780228012344", "978-0-2280-1234-4"},
+		{"9780655012344", "978-0-6550-1234-4"},
+		{"9791020000002", "979-10-200-0000-2"},
+	}
+	for _, tt := range tests {
+		got, err := HyphenateISBN(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("HyphenateISBN(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestHyphenateISBNErrors(t *testing.T) {
+	// 978-2 is not in the table, and 979-16 to 979-79 are not assigned.
+	for _, in := range []string{"9782070368228", "2-07-036822-X", "9791600000002"} {
+		if _, err := HyphenateISBN(in); !errors.Is(err, ErrUnknownISBNRange) {
+			t.Errorf("HyphenateISBN(%q): %v, want ErrUnknownISBNRange", in, err)
+		}
+	}
+	tests := []struct {
+		in     string
+		reason DrawReason
+	}{
+		{"9784065199818", ReasonBadCheckDigit},
+		{"4-06-519981-X", ReasonBadCheckDigit},
+		{"97840651998", ReasonInvalidLength},
+		{"9790230671187", ReasonInvalidCharacter}, // an ISMN
+		{"40651998X6", ReasonInvalidCharacter},
+	}
+	for _, tt := range tests {
+		_, err := HyphenateISBN(tt.in)
+		var de *DrawError
+		if !errors.As(err, &de) || de.Reason != tt.reason || errors.Is(err, ErrUnknownISBNRange) {
+			t.Errorf("HyphenateISBN(%q): %v, want %s", tt.in, err, tt.reason)
+		}
+	}
+}
+
+func TestISBNForms(t *testing.T) {
+	if got, err := ISBN13("4-06-519981-6"); err != nil || got != "9784065199817" {
+		t.Errorf("ISBN13 = %q, %v", got, err)
+	}
+	if got, err := ISBN10("978-0-8044-2957-3"); err != nil || got != "080442957X" {
+		t.Errorf("ISBN10 = %q, %v", got, err)
+	}
+	if _, err := ISBN10("9791020000002"); err == nil {
+		t.Error("ISBN10 of a 979 ISBN succeeded")
+	}
+}
+
+func TestISBNDraw(t *testing.T) {
+	b, err := NewISBNE(FormatSVG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svg, err := b.Draw("4-06-519981-6", 300, 150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(svg, "ISBN 978-4-06-519981-7") {
+		t.Error("header text missing")
+	}
+	_, err = b.Draw("9782070368228", 300, 150)
+	var de *DrawError
+	if !errors.Is(err, ErrUnknownISBNRange) || !errors.As(err, &de) || de.Symbology != "ISBN" {
+		t.Errorf("Draw outside the range table: %v", err)
+	}
+	if _, err := b.Encode("9782070368228"); !errors.Is(err, ErrUnknownISBNRange) {
+		t.Errorf("Encode outside the range table: %v", err)
+	}
+}
+
+// isbnranges.go is the output of isbnranges_gen.go for
+// testdata/RangeMessage.xml, apart from the header comment.
+func TestISBNRangesGenerated(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the generator")
+	}
+	gocmd := filepath.Join(runtime.GOROOT(), "bin", "go")
+	if _, err := os.Stat(gocmd); err != nil {
+		t.Skipf("go command not found: %v", err)
+	}
+	out := filepath.Join(t.TempDir(), "isbnranges.go")
+	cmd := exec.Command(gocmd, "run", "isbnranges_gen.go", "-in", "testdata/RangeMessage.xml", "-out", out)
+	if msg, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("isbnranges_gen.go: %v\n%s", err, msg)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("isbnranges.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := func(src []byte) string {
+		s := string(src)
+		return s[strings.Index(s, "// isbnGroups"):]
+	}
+	if !strings.HasPrefix(string(got), "// Code generated by isbnranges_gen.go") {
+		t.Errorf("generated file lacks the generated code header:\n%.200s", got)
+	}
+	if body(got) != body(want) {
+		t.Errorf("generated tables differ from isbnranges.go:\n%s", body(got))
+	}
+}
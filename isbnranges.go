@@ -0,0 +1,48 @@
+package barcode_pao
+
+// ISBN ranges, following the RangeMessage published by the International
+// ISBN Agency. A range "lo-hi" matches an element whose leading digits lie
+// between lo and hi; the element is as long as the bounds. ISBNs in ranges
+// that are not yet assigned cannot be hyphenated.
+//
+// This table holds a subset of the registration groups; ISBNs of the others
+// are reported with ErrUnknownISBNRange. To cover every group, download
+// RangeMessage.xml and run go generate, which replaces this file with the
+// output of isbnranges_gen.go.
+
+// isbnGroups are the registration group ranges of each ISBN prefix.
+var isbnGroups = map[string][]string{
+	"978": {"0-5", "600-649", "65-65", "7-7", "80-94", "950-989",
+		"9900-9989", "99900-99999"},
+	"979": {"10-15", "8-8"},
+}
+
+// isbnRegistrants are the registrant ranges of each registration group,
+// keyed by prefix and group.
+var isbnRegistrants = map[string][]string{
+	// English language
+	"978-0": {"00-19", "200-227", "2280-2289", "229-368", "3690-3699", "370-638",
+		"6390-6397", "6398000-6399999", "640-644", "6450000-6459999", "646-647", "6480000-6489999",
+		"649-654", "6550-6559", "656-699", "7000-8499", "85000-89999", "900000-949999",
+		"9500000-9999999"},
+	// English language
+	"978-1": {"000-009", "01-02", "030-034", "0350-0399", "04-06", "0700-0999",
+		"100-397", "3980-5499", "55000-64999", "6500-6799", "68000-68599", "6860-7139",
+		"714-716", "7170-7319", "7320000-7399999", "74000-77499", "7750000-7753999", "77540-77639",
+		"7764000-7764999", "77650-77699", "7770000-7782999", "77830-78999", "7900-7999", "80000-80049",
+		"80050-80499", "80500-83799", "8380000-8384999", "83850-86719", "8672-8675", "86760-86979",
+		"869800-915999", "9160000-9165059", "916506-916869", "9168700-9169079", "916908-919599", "9196000-9196549",
+		"919655-972999", "9730-9877", "987800-991149", "9911500-9911999", "991200-998989", "9989900-9999999"},
+	// German language
+	"978-3": {"00-02", "030-033", "0340-0369", "03700-03999", "04-19", "200-699",
+		"7000-8499", "85000-89999", "900000-949999", "9500000-9539999", "95400-96999", "9700000-9849999",
+		"98500-99999"},
+	// Japan
+	"978-4": {"00-19", "200-699", "7000-8499", "85000-89999", "900000-949999", "9500000-9999999"},
+	// China, People's Republic
+	"978-7": {"00-09", "100-499", "5000-7999", "80000-89999", "900000-999999"},
+	// France
+	"979-10": {"00-19", "200-699", "7000-8999", "90000-97599", "976000-999999"},
+	// Korea, Republic
+	"979-11": {"00-24", "250-549", "5500-8499", "85000-94999", "950000-999999"},
+}
@@ -0,0 +1,127 @@
+//go:build ignore
+
+// isbnranges_gen writes isbnranges.go from the RangeMessage.xml published
+// by the International ISBN Agency at
+// https://www.isbn-international.org/range_file_generation. Download the
+// XML file into this directory and run
+//
+//	go generate
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type rule struct {
+	Range  string `xml:"Range"`
+	Length int    `xml:"Length"`
+}
+
+type group struct {
+	Prefix string `xml:"Prefix"`
+	Agency string `xml:"Agency"`
+	Rules  []rule `xml:"Rules>Rule"`
+}
+
+type rangeMessage struct {
+	Source   string  `xml:"MessageSource"`
+	Serial   string  `xml:"MessageSerialNumber"`
+	Date     string  `xml:"MessageDate"`
+	Prefixes []group `xml:"EAN.UCCPrefixes>EAN.UCC"`
+	Groups   []group `xml:"RegistrationGroups>Group"`
+}
+
+func main() {
+	in := flag.String("in", "RangeMessage.xml", "range message `file`")
+	out := flag.String("out", "isbnranges.go", "output `file`")
+	flag.Parse()
+
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var msg rangeMessage
+	if err := xml.Unmarshal(data, &msg); err != nil {
+		log.Fatalf("%s: %v", *in, err)
+	}
+	if len(msg.Prefixes) == 0 || len(msg.Groups) == 0 {
+		log.Fatalf("%s: no ranges; is it an ISBN RangeMessage?", *in)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by isbnranges_gen.go from RangeMessage.xml; DO NOT EDIT.\n")
+	fmt.Fprintf(&b, "// Source: %s, message %s of %s.\n\n", msg.Source, msg.Serial, msg.Date)
+	b.WriteString(`package barcode_pao
+
+// ISBN ranges, following the RangeMessage published by the International
+// ISBN Agency. A range "lo-hi" matches an element whose leading digits lie
+// between lo and hi; the element is as long as the bounds. ISBNs in ranges
+// that are not yet assigned cannot be hyphenated.
+
+// isbnGroups are the registration group ranges of each ISBN prefix.
+var isbnGroups = map[string][]string{
+`)
+	for _, p := range msg.Prefixes {
+		writeRanges(&b, p, "")
+	}
+	b.WriteString(`}
+
+// isbnRegistrants are the registrant ranges of each registration group,
+// keyed by prefix and group.
+var isbnRegistrants = map[string][]string{
+`)
+	for _, g := range msg.Groups {
+		writeRanges(&b, g, g.Agency)
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatalf("formatting output: %v", err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// writeRanges writes the map entry of a prefix or group, preceded by a
+// comment unless it is empty. The seven-digit ranges of the message are cut
+// to the element length; ranges of length 0 are not assigned and are left
+// out.
+func writeRanges(b *bytes.Buffer, g group, comment string) {
+	var ranges []string
+	for _, r := range g.Rules {
+		lo, hi, ok := strings.Cut(r.Range, "-")
+		if !ok || len(lo) != 7 || len(hi) != 7 || r.Length > 7 {
+			log.Fatalf("%s: malformed rule %q length %d", g.Prefix, r.Range, r.Length)
+		}
+		if r.Length == 0 {
+			continue
+		}
+		ranges = append(ranges, strconv.Quote(lo[:r.Length]+"-"+hi[:r.Length]))
+	}
+	if len(ranges) == 0 {
+		return
+	}
+	if comment != "" {
+		fmt.Fprintf(b, "\t// %s\n", comment)
+	}
+	fmt.Fprintf(b, "\t%q: {", g.Prefix)
+	for i, r := range ranges {
+		if i > 0 && i%6 == 0 {
+			b.WriteString("\n\t\t")
+		} else if i > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(r + ",")
+	}
+	b.WriteString("},\n")
+}
@@ -0,0 +1,49 @@
+//go:build !windows && !cgo
+
+package barcode_pao
+
+import (
+	"errors"
+	"runtime"
+)
+
+// errNoDlopen is returned when the binary was built without cgo, which the
+// dlopen-based loader needs on non-Windows platforms.
+var errNoDlopen = errors.New("native library loading on " + runtime.GOOS + " requires cgo (build with CGO_ENABLED=1)")
+
+// nativeProc is a placeholder proc; loadLibrary always fails in this build.
+type nativeProc struct {
+	Name string
+}
+
+// Find always fails in this build.
+func (p *nativeProc) Find() error {
+	return errNoDlopen
+}
+
+// Call panics: no native library can be loaded in this build.
+func (p *nativeProc) Call(a ...uintptr) (r1, r2 uintptr, lastErr error) {
+	panic(errNoDlopen)
+}
+
+// nativeLibNames lists the file names the native library may be shipped as.
+var nativeLibNames = func() []string {
+	if runtime.GOOS == "darwin" {
+		return []string{"libbarcode_pao.dylib", "barcode_pao.dylib"}
+	}
+	return []string{"libbarcode_pao.so", "barcode_pao.so"}
+}()
+
+type nativeLibrary struct{}
+
+func openNativeLibrary(nativeDir string) (*nativeLibrary, string, error) {
+	return nil, nativeDir, errNoDlopen
+}
+
+func (l *nativeLibrary) NewProc(name string) *nativeProc {
+	return &nativeProc{Name: name}
+}
+
+func callDouble(p *nativeProc, h uintptr, v float64) {
+	panic(errNoDlopen)
+}
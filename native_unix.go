@@ -0,0 +1,169 @@
+//go:build !windows && cgo
+
+package barcode_pao
+
+/*
+#cgo linux LDFLAGS: -ldl
+#include <dlfcn.h>
+#include <stdint.h>
+#include <stdlib.h>
+
+typedef uintptr_t (*bc_fn0)(void);
+typedef uintptr_t (*bc_fn1)(uintptr_t);
+typedef uintptr_t (*bc_fn2)(uintptr_t, uintptr_t);
+typedef uintptr_t (*bc_fn3)(uintptr_t, uintptr_t, uintptr_t);
+typedef uintptr_t (*bc_fn4)(uintptr_t, uintptr_t, uintptr_t, uintptr_t);
+typedef uintptr_t (*bc_fn5)(uintptr_t, uintptr_t, uintptr_t, uintptr_t, uintptr_t);
+typedef uintptr_t (*bc_fn6)(uintptr_t, uintptr_t, uintptr_t, uintptr_t, uintptr_t, uintptr_t);
+typedef void (*bc_fn_double)(uintptr_t, double);
+
+// bc_call invokes fn with n integer/pointer arguments. Every exported
+// function of barcode_ffi.h that takes no double fits this shape.
+static uintptr_t bc_call(void* fn, int n, uintptr_t a0, uintptr_t a1, uintptr_t a2,
+                         uintptr_t a3, uintptr_t a4, uintptr_t a5) {
+	switch (n) {
+	case 0: return ((bc_fn0)fn)();
+	case 1: return ((bc_fn1)fn)(a0);
+	case 2: return ((bc_fn2)fn)(a0, a1);
+	case 3: return ((bc_fn3)fn)(a0, a1, a2);
+	case 4: return ((bc_fn4)fn)(a0, a1, a2, a3);
+	case 5: return ((bc_fn5)fn)(a0, a1, a2, a3, a4);
+	default: return ((bc_fn6)fn)(a0, a1, a2, a3, a4, a5);
+	}
+}
+
+// bc_call_double invokes a (handle, double) setter. Doubles travel in
+// floating-point registers on System V and AAPCS64, so they need their own
+// trampoline.
+static void bc_call_double(void* fn, uintptr_t h, double v) {
+	((bc_fn_double)fn)(h, v);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"unsafe"
+)
+
+// maxProcArgs is the largest arity among the native exports.
+const maxProcArgs = 6
+
+// nativeProc is a single exported function of the native library.
+type nativeProc struct {
+	Name string
+	addr unsafe.Pointer
+	err  error
+}
+
+// Find reports whether the symbol was resolved.
+func (p *nativeProc) Find() error {
+	return p.err
+}
+
+// Call invokes the function with integer/pointer arguments. Like
+// syscall.LazyProc.Call, it panics if the symbol could not be resolved.
+func (p *nativeProc) Call(a ...uintptr) (r1, r2 uintptr, lastErr error) {
+	if p.err != nil {
+		panic(p.err)
+	}
+	if len(a) > maxProcArgs {
+		panic(fmt.Sprintf("barcode_pao: %s called with %d arguments, max %d", p.Name, len(a), maxProcArgs))
+	}
+	var args [maxProcArgs]C.uintptr_t
+	for i, v := range a {
+		args[i] = C.uintptr_t(v)
+	}
+	r := C.bc_call(p.addr, C.int(len(a)), args[0], args[1], args[2], args[3], args[4], args[5])
+	return uintptr(r), 0, nil
+}
+
+// nativeLibNames lists the file names the native library may be shipped as.
+var nativeLibNames = func() []string {
+	if runtime.GOOS == "darwin" {
+		return []string{"libbarcode_pao.dylib", "barcode_pao.dylib"}
+	}
+	return []string{"libbarcode_pao.so", "barcode_pao.so"}
+}()
+
+// nativeDeps lists dependent libraries preloaded from the native directory.
+var nativeDeps = func() []string {
+	if runtime.GOOS == "darwin" {
+		return []string{"libSDL2.dylib", "libSDL2_image.dylib", "libSDL2_ttf.dylib"}
+	}
+	return []string{"libSDL2.so", "libSDL2_image.so", "libSDL2_ttf.so"}
+}()
+
+// nativeLibrary is a dlopen handle to barcode_pao.so/dylib.
+type nativeLibrary struct {
+	handle unsafe.Pointer
+}
+
+// openNativeLibrary loads the shared library from nativeDir. It returns the
+// path it tried so that callers can report it on failure.
+func openNativeLibrary(nativeDir string) (*nativeLibrary, string, error) {
+	// Preload dependent libraries globally so the engine can resolve them
+	// without LD_LIBRARY_PATH / DYLD_LIBRARY_PATH.
+	for _, dep := range nativeDeps {
+		depPath := filepath.Join(nativeDir, dep)
+		if _, err := os.Stat(depPath); err == nil {
+			dlopen(depPath, C.RTLD_NOW|C.RTLD_GLOBAL)
+		}
+	}
+
+	libPath := filepath.Join(nativeDir, nativeLibNames[0])
+	for _, name := range nativeLibNames {
+		p := filepath.Join(nativeDir, name)
+		if _, err := os.Stat(p); err == nil {
+			libPath = p
+			break
+		}
+	}
+	h, err := dlopen(libPath, C.RTLD_NOW|C.RTLD_LOCAL)
+	if err != nil {
+		return nil, libPath, err
+	}
+	return &nativeLibrary{handle: h}, libPath, nil
+}
+
+// NewProc resolves the named export. A missing symbol is reported by Find.
+func (l *nativeLibrary) NewProc(name string) *nativeProc {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	C.dlerror()
+	addr := C.dlsym(l.handle, cname)
+	if addr == nil {
+		return &nativeProc{Name: name, err: fmt.Errorf("symbol %s: %w", name, dlerror())}
+	}
+	return &nativeProc{Name: name, addr: addr}
+}
+
+// callDouble calls a native setter taking (handle, double).
+func callDouble(p *nativeProc, h uintptr, v float64) {
+	if p.err != nil {
+		panic(p.err)
+	}
+	C.bc_call_double(p.addr, C.uintptr_t(h), C.double(v))
+}
+
+func dlopen(path string, flags C.int) (unsafe.Pointer, error) {
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	h := C.dlopen(cpath, flags)
+	if h == nil {
+		return nil, dlerror()
+	}
+	return h, nil
+}
+
+func dlerror() error {
+	msg := C.dlerror()
+	if msg == nil {
+		return errors.New("unknown dlopen error")
+	}
+	return errors.New(C.GoString(msg))
+}
@@ -0,0 +1,55 @@
+//go:build windows
+
+package barcode_pao
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// nativeProc is a single exported function of the native library.
+type nativeProc = syscall.LazyProc
+
+// nativeLibNames lists the file names the native library may be shipped as.
+var nativeLibNames = []string{"barcode_pao.dll"}
+
+// nativeDeps lists dependent libraries preloaded from the native directory.
+var nativeDeps = []string{"SDL2.dll", "SDL2_image.dll", "SDL2_ttf.dll"}
+
+// nativeLibrary is a loaded barcode_pao.dll.
+type nativeLibrary struct {
+	dll *syscall.LazyDLL
+}
+
+// openNativeLibrary loads barcode_pao.dll from nativeDir. It returns the path
+// it tried so that callers can report it on failure.
+func openNativeLibrary(nativeDir string) (*nativeLibrary, string, error) {
+	// Preload dependent DLLs
+	for _, dep := range nativeDeps {
+		depPath := filepath.Join(nativeDir, dep)
+		if _, err := os.Stat(depPath); err == nil {
+			syscall.LoadDLL(depPath)
+		}
+	}
+
+	dllPath := filepath.Join(nativeDir, nativeLibNames[0])
+	dll := syscall.NewLazyDLL(dllPath)
+	if err := dll.Load(); err != nil {
+		return nil, dllPath, err
+	}
+	return &nativeLibrary{dll: dll}, dllPath, nil
+}
+
+// NewProc returns the named export. The symbol is resolved on first use.
+func (l *nativeLibrary) NewProc(name string) *nativeProc {
+	return l.dll.NewProc(name)
+}
+
+// callDouble calls a native setter taking (handle, double). The Windows x64
+// calling convention mirrors the first arguments into XMM registers, so the
+// raw bits can travel through the integer argument path.
+func callDouble(p *nativeProc, h uintptr, v float64) {
+	p.Call(h, uintptr(math.Float64bits(v)))
+}
@@ -0,0 +1,132 @@
+package barcode_pao
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ─── NW-7 (Codabar) ────────────────────────────────────────────────────────
+
+// nw7Patterns are the wide/narrow patterns of nw7Chars followed by the
+// start/stop characters A–D: 7 elements, most significant bit first, set
+// bits wide.
+var nw7Patterns = [...]int{
+	0x003, 0x006, 0x009, 0x060, 0x012, 0x042, 0x021, 0x024, 0x030, 0x048, // 0-9
+	0x00c, 0x018, 0x045, 0x051, 0x054, 0x015, // - $ : / . +
+	0x01A, 0x029, 0x00B, 0x00E, // A B C D
+}
+
+const nw7AllChars = nw7Chars + "ABCD"
+
+func encodeNW7(code string, opts *settings) (*Symbol, error) {
+	return nw7Symbol(code, 1, code39Wide, opts)
+}
+
+// encodeCodabar encodes NW-7 with the wide-to-narrow ratio set in opts:
+// 2:1, 2.5:1 or 3:1.
+func encodeCodabar(code string, opts *settings) (*Symbol, error) {
+	if opts.codabarRatio == 2.5 {
+		return nw7Symbol(code, 2, 5, opts)
+	}
+	return nw7Symbol(code, 1, int(opts.codabarRatio), opts)
+}
+
+// nw7Symbol encodes code with narrow and wide elements of the given
+// widths.
+func nw7Symbol(code string, narrow, wide int, opts *settings) (*Symbol, error) {
+	full, err := completeNW7(code, opts)
+	if err != nil {
+		return nil, err
+	}
+	full = strings.ToUpper(full)
+	var bars []int
+	for i, r := range full {
+		if i > 0 {
+			bars = append(bars, 1)
+		}
+		bars = appendWideNarrow(bars, nw7Patterns[strings.IndexRune(nw7AllChars, r)], 7, wide)
+	}
+	if narrow != 1 {
+		for i, w := range bars {
+			if w == 1 {
+				bars[i] = narrow
+			}
+		}
+	}
+	text := full
+	if !opts.showStartStop {
+		text = full[1 : len(full)-1]
+	}
+	return &Symbol{Bars: bars, QuietZone: 10, Text: text}, nil
+}
+
+// parseNW7StartStop normalizes a start or stop character option.
+func parseNW7StartStop(what, c string) (string, error) {
+	c = strings.ToUpper(c)
+	if len(c) != 1 || !strings.Contains("ABCD", c) {
+		return "", fmt.Errorf("%w: NW-7 %s character must be A, B, C or D, got %q", ErrInvalidOption, what, c)
+	}
+	return c, nil
+}
+
+// completeNW7 returns code between the start and stop characters set in
+// opts, unless it has its own, with the check character of opts.nw7Check
+// before the stop character. The characters of code are kept as written.
+func completeNW7(code string, opts *settings) (string, error) {
+	if err := validateNW7(code, opts); err != nil {
+		return "", err
+	}
+	full, off := code, 1
+	if !strings.ContainsAny(strings.ToUpper(full[:1]), "ABCD") {
+		full, off = opts.nw7Start+full+opts.nw7Stop, 0
+	}
+	scheme := opts.nw7Check
+	if scheme == NW7CheckNone {
+		return full, nil
+	}
+	data := full[1 : len(full)-1]
+	if scheme != NW7CheckMod16 {
+		for i, r := range data {
+			if !isDigit(r) {
+				return "", errInvalidChar(off+i, r, "the "+string(scheme)+" check scheme takes digits only")
+			}
+		}
+	}
+	return full[:len(full)-1] + string(nw7CheckChar(scheme, strings.ToUpper(full))) + full[len(full)-1:], nil
+}
+
+// nw7CheckChar returns the check character of full, the data between its
+// start and stop characters. The digit schemes weight the digits from the
+// right.
+func nw7CheckChar(scheme NW7CheckScheme, full string) byte {
+	data := full[1 : len(full)-1]
+	switch scheme {
+	case NW7CheckMod16:
+		sum := 0
+		for i := 0; i < len(full); i++ {
+			sum += strings.IndexByte(nw7AllChars, full[i])
+		}
+		return nw7AllChars[(16-sum%16)%16]
+	case NW7CheckMod11:
+		if c := 11 - mod11Remainder(data); c < 10 {
+			return byte('0' + c)
+		}
+		return '0'
+	case NW7CheckMod10W21:
+		return luhnCheckDigit(data)
+	case NW7CheckMod10W31:
+		return gs1CheckDigit(data)
+	}
+	m := 7
+	if scheme == NW7Check9DR || scheme == NW7Check9DSR {
+		m = 9
+	}
+	r := 0
+	for i := 0; i < len(data); i++ {
+		r = (r*10 + int(data[i]-'0')) % m
+	}
+	if scheme == NW7Check7DSR || scheme == NW7Check9DSR {
+		r = m - r
+	}
+	return byte('0' + r)
+}
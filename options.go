@@ -0,0 +1,610 @@
+package barcode_pao
+
+import (
+	"errors"
+	"strings"
+)
+
+// ─── Serializable settings ─────────────────────────────────────────────────
+//
+// Every barcode type has an Options struct holding all of its settings,
+// tagged for JSON and YAML so that label templates can be stored in config
+// files. Options() takes a consistent snapshot of a generator; Apply(opts)
+// replays it through the setters, so a value the setter would reject is
+// reported the same way. Apply sets every field, so a template loaded from
+// a file should be decoded over the result of Options() rather than into a
+// zero value:
+//
+//	opts := qr.Options()
+//	if err := json.Unmarshal(data, &opts); err != nil {
+//		return err
+//	}
+//	err := qr.Apply(opts)
+
+// Color is an RGBA color, as taken by SetForegroundColor.
+type Color struct {
+	R int `json:"r" yaml:"r"`
+	G int `json:"g" yaml:"g"`
+	B int `json:"b" yaml:"b"`
+	A int `json:"a" yaml:"a"`
+}
+
+// BaseOptions holds the settings shared by all barcode types.
+type BaseOptions struct {
+	OutputFormat string `json:"outputFormat" yaml:"outputFormat"`
+	Foreground   Color  `json:"foreground" yaml:"foreground"`
+	Background   Color  `json:"background" yaml:"background"`
+}
+
+// Options1D holds the settings shared by the 1D barcode types. It is the
+// Options struct of Code93, Code32, GS1128, ITF, Matrix2of5, NEC2of5,
+// GS1DataBarLimited and Pharmacode.
+type Options1D struct {
+	BaseOptions     `yaml:",inline"`
+	ShowText        bool    `json:"showText" yaml:"showText"`
+	TextGap         float64 `json:"textGap" yaml:"textGap"`
+	TextFontScale   float64 `json:"textFontScale" yaml:"textFontScale"`
+	TextEvenSpacing bool    `json:"textEvenSpacing" yaml:"textEvenSpacing"`
+	FitWidth        bool    `json:"fitWidth" yaml:"fitWidth"`
+	PxAdjustBlack   int     `json:"pxAdjustBlack" yaml:"pxAdjustBlack"`
+	PxAdjustWhite   int     `json:"pxAdjustWhite" yaml:"pxAdjustWhite"`
+}
+
+// Options2D holds the settings shared by the 2D barcode types.
+type Options2D struct {
+	BaseOptions    `yaml:",inline"`
+	StringEncoding string `json:"stringEncoding" yaml:"stringEncoding"`
+	FitWidth       bool   `json:"fitWidth" yaml:"fitWidth"`
+}
+
+// Code39Options holds the settings of Code39.
+type Code39Options struct {
+	Options1D      `yaml:",inline"`
+	ShowStartStop  bool `json:"showStartStop" yaml:"showStartStop"`
+	FullASCII      bool `json:"fullAscii" yaml:"fullAscii"`
+	CheckCharacter bool `json:"checkCharacter" yaml:"checkCharacter"`
+}
+
+// NW7Options holds the settings of NW7.
+type NW7Options struct {
+	Options1D      `yaml:",inline"`
+	ShowStartStop  bool           `json:"showStartStop" yaml:"showStartStop"`
+	StartCharacter string         `json:"startCharacter" yaml:"startCharacter"`
+	StopCharacter  string         `json:"stopCharacter" yaml:"stopCharacter"`
+	CheckScheme    NW7CheckScheme `json:"checkScheme" yaml:"checkScheme"`
+}
+
+// CodabarOptions holds the settings of RationalizedCodabar.
+type CodabarOptions struct {
+	NW7Options `yaml:",inline"`
+	WideRatio  float64 `json:"wideRatio" yaml:"wideRatio"`
+}
+
+// Code11Options holds the settings of Code11.
+type Code11Options struct {
+	Options1D   `yaml:",inline"`
+	CheckDigits int `json:"checkDigits" yaml:"checkDigits"`
+}
+
+// MSIOptions holds the settings of MSI.
+type MSIOptions struct {
+	Options1D   `yaml:",inline"`
+	CheckScheme MSICheckScheme `json:"checkScheme" yaml:"checkScheme"`
+}
+
+// TelepenOptions holds the settings of Telepen.
+type TelepenOptions struct {
+	Options1D `yaml:",inline"`
+	Numeric   bool `json:"numeric" yaml:"numeric"`
+}
+
+// Code128Options holds the settings of Code128.
+type Code128Options struct {
+	Options1D `yaml:",inline"`
+	CodeMode  Code128Mode `json:"codeMode" yaml:"codeMode"`
+}
+
+// JANOptions holds the settings of Jan8, Jan13, UPCA and UPCE.
+type JANOptions struct {
+	Options1D        `yaml:",inline"`
+	ExtendedGuard    bool             `json:"extendedGuard" yaml:"extendedGuard"`
+	CheckDigitPolicy CheckDigitPolicy `json:"checkDigitPolicy" yaml:"checkDigitPolicy"`
+	AddOn            string           `json:"addOn" yaml:"addOn"`
+}
+
+// HIBCOptions holds the settings of HIBC.
+type HIBCOptions struct {
+	Options1D     `yaml:",inline"`
+	ShowStartStop bool         `json:"showStartStop" yaml:"showStartStop"`
+	Standard      HIBCStandard `json:"standard" yaml:"standard"`
+}
+
+// ITF14Options holds the settings of ITF14.
+type ITF14Options struct {
+	Options1D        `yaml:",inline"`
+	BearerStyle      BearerStyle      `json:"bearerStyle" yaml:"bearerStyle"`
+	BearerWidth      int              `json:"bearerWidth" yaml:"bearerWidth"`
+	CheckDigitPolicy CheckDigitPolicy `json:"checkDigitPolicy" yaml:"checkDigitPolicy"`
+}
+
+// ISSNOptions holds the settings of ISSN.
+type ISSNOptions struct {
+	JANOptions `yaml:",inline"`
+	Variant    string `json:"variant" yaml:"variant"`
+}
+
+// DataBar14Options holds the settings of GS1DataBar14.
+type DataBar14Options struct {
+	Options1D  `yaml:",inline"`
+	SymbolType DataBarSymbolType `json:"symbolType" yaml:"symbolType"`
+}
+
+// DataBarExpandedOptions holds the settings of GS1DataBarExpanded.
+type DataBarExpandedOptions struct {
+	Options1D   `yaml:",inline"`
+	SymbolType  DataBarSymbolType `json:"symbolType" yaml:"symbolType"`
+	NoOfColumns int               `json:"noOfColumns" yaml:"noOfColumns"`
+}
+
+// YubinOptions holds the settings of YubinCustomer.
+type YubinOptions struct {
+	BaseOptions   `yaml:",inline"`
+	PxAdjustBlack int `json:"pxAdjustBlack" yaml:"pxAdjustBlack"`
+	PxAdjustWhite int `json:"pxAdjustWhite" yaml:"pxAdjustWhite"`
+}
+
+// QROptions holds the settings of QR.
+type QROptions struct {
+	Options2D            `yaml:",inline"`
+	ErrorCorrectionLevel ECCLevel     `json:"errorCorrectionLevel" yaml:"errorCorrectionLevel"`
+	Version              int          `json:"version" yaml:"version"`
+	EncodeMode           QREncodeMode `json:"encodeMode" yaml:"encodeMode"`
+	GS1Mode              bool         `json:"gs1Mode" yaml:"gs1Mode"`
+}
+
+// DataMatrixOptions holds the settings of DataMatrix.
+type DataMatrixOptions struct {
+	Options2D    `yaml:",inline"`
+	CodeSize     DataMatrixSize   `json:"codeSize" yaml:"codeSize"`
+	EncodeScheme DataMatrixScheme `json:"encodeScheme" yaml:"encodeScheme"`
+	GS1Mode      bool             `json:"gs1Mode" yaml:"gs1Mode"`
+}
+
+// PDF417Options holds the settings of PDF417.
+type PDF417Options struct {
+	Options2D   `yaml:",inline"`
+	ErrorLevel  int     `json:"errorLevel" yaml:"errorLevel"`
+	Columns     int     `json:"columns" yaml:"columns"`
+	Rows        int     `json:"rows" yaml:"rows"`
+	AspectRatio float64 `json:"aspectRatio" yaml:"aspectRatio"`
+	YHeight     int     `json:"yHeight" yaml:"yHeight"`
+}
+
+// snapshot returns the output format and a copy of the settings.
+func (b *BarcodeBase) snapshot() (string, settings) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.outputFormat, b.opts
+}
+
+func baseOptions(format string, o *settings) BaseOptions {
+	return BaseOptions{
+		OutputFormat: format,
+		Foreground:   Color{int(o.fg.R), int(o.fg.G), int(o.fg.B), int(o.fg.A)},
+		Background:   Color{int(o.bg.R), int(o.bg.G), int(o.bg.B), int(o.bg.A)},
+	}
+}
+
+func options1D(format string, o *settings) Options1D {
+	return Options1D{
+		BaseOptions:     baseOptions(format, o),
+		ShowText:        o.showText,
+		TextGap:         o.textGap,
+		TextFontScale:   o.textFontScale,
+		TextEvenSpacing: o.textEvenSpacing,
+		FitWidth:        o.fitWidth,
+		PxAdjustBlack:   o.pxAdjustBlack,
+		PxAdjustWhite:   o.pxAdjustWhite,
+	}
+}
+
+func options2D(format string, o *settings) Options2D {
+	return Options2D{
+		BaseOptions:    baseOptions(format, o),
+		StringEncoding: o.stringEncoding,
+		FitWidth:       o.fitWidth,
+	}
+}
+
+// Options returns the settings shared by all barcode types.
+func (b *BarcodeBase) Options() BaseOptions {
+	f, o := b.snapshot()
+	return baseOptions(f, &o)
+}
+
+// Apply sets the output format and colors.
+func (b *BarcodeBase) Apply(opts BaseOptions) error {
+	fg, bg := opts.Foreground, opts.Background
+	return errors.Join(
+		b.SetOutputFormat(opts.OutputFormat),
+		b.SetForegroundColor(fg.R, fg.G, fg.B, fg.A),
+		b.SetBackgroundColor(bg.R, bg.G, bg.B, bg.A),
+	)
+}
+
+// Options returns the 1D settings.
+func (b *Barcode1DBase) Options() Options1D {
+	f, o := b.snapshot()
+	return options1D(f, &o)
+}
+
+// Apply sets the 1D settings.
+func (b *Barcode1DBase) Apply(opts Options1D) error {
+	return errors.Join(
+		b.BarcodeBase.Apply(opts.BaseOptions),
+		b.SetShowText(opts.ShowText),
+		b.SetTextGap(opts.TextGap),
+		b.SetTextFontScale(opts.TextFontScale),
+		b.SetTextEvenSpacing(opts.TextEvenSpacing),
+		b.SetFitWidth(opts.FitWidth),
+		b.SetPxAdjustBlack(opts.PxAdjustBlack),
+		b.SetPxAdjustWhite(opts.PxAdjustWhite),
+	)
+}
+
+// Options returns the 2D settings.
+func (b *Barcode2DBase) Options() Options2D {
+	f, o := b.snapshot()
+	return options2D(f, &o)
+}
+
+// Apply sets the 2D settings.
+func (b *Barcode2DBase) Apply(opts Options2D) error {
+	return errors.Join(
+		b.BarcodeBase.Apply(opts.BaseOptions),
+		b.SetStringEncoding(opts.StringEncoding),
+		b.SetFitWidth(opts.FitWidth),
+	)
+}
+
+// Options returns the Code39 settings.
+func (b *Code39) Options() Code39Options {
+	f, o := b.snapshot()
+	return Code39Options{
+		Options1D:      options1D(f, &o),
+		ShowStartStop:  o.showStartStop,
+		FullASCII:      o.fullASCII,
+		CheckCharacter: o.code39Check,
+	}
+}
+
+// Apply sets the Code39 settings.
+func (b *Code39) Apply(opts Code39Options) error {
+	return errors.Join(
+		b.Barcode1DBase.Apply(opts.Options1D),
+		b.SetShowStartStop(opts.ShowStartStop),
+		b.SetFullASCII(opts.FullASCII),
+		b.SetCheckCharacter(opts.CheckCharacter),
+	)
+}
+
+// Options returns the HIBC settings.
+func (b *HIBC) Options() HIBCOptions {
+	f, o := b.snapshot()
+	return HIBCOptions{Options1D: options1D(f, &o), ShowStartStop: o.showStartStop, Standard: o.hibcStandard}
+}
+
+// Apply sets the HIBC settings.
+func (b *HIBC) Apply(opts HIBCOptions) error {
+	return errors.Join(
+		b.Barcode1DBase.Apply(opts.Options1D),
+		b.SetShowStartStop(opts.ShowStartStop),
+		b.SetStandard(opts.Standard),
+	)
+}
+
+// Options returns the NW7 settings.
+func (b *NW7) Options() NW7Options {
+	f, o := b.snapshot()
+	return nw7OptionsOf(f, &o)
+}
+
+func nw7OptionsOf(f string, o *settings) NW7Options {
+	return NW7Options{
+		Options1D:      options1D(f, o),
+		ShowStartStop:  o.showStartStop,
+		StartCharacter: o.nw7Start,
+		StopCharacter:  o.nw7Stop,
+		CheckScheme:    o.nw7Check,
+	}
+}
+
+// Apply sets the NW7 settings. The start, stop and check character
+// settings are only set where they differ from the current ones: setting
+// any of them makes the input be completed before it reaches the engine,
+// so applying unchanged Options must not.
+func (b *NW7) Apply(opts NW7Options) error {
+	cur := b.Options()
+	errs := []error{
+		b.Barcode1DBase.Apply(opts.Options1D),
+		b.SetShowStartStop(opts.ShowStartStop),
+	}
+	if !strings.EqualFold(opts.StartCharacter, cur.StartCharacter) {
+		errs = append(errs, b.SetStartCharacter(opts.StartCharacter))
+	}
+	if !strings.EqualFold(opts.StopCharacter, cur.StopCharacter) {
+		errs = append(errs, b.SetStopCharacter(opts.StopCharacter))
+	}
+	if !strings.EqualFold(string(opts.CheckScheme), string(cur.CheckScheme)) {
+		errs = append(errs, b.SetCheckScheme(opts.CheckScheme))
+	}
+	return errors.Join(errs...)
+}
+
+// Options returns the Codabar settings.
+func (b *RationalizedCodabar) Options() CodabarOptions {
+	f, o := b.snapshot()
+	return CodabarOptions{NW7Options: nw7OptionsOf(f, &o), WideRatio: o.codabarRatio}
+}
+
+// Apply sets the Codabar settings.
+func (b *RationalizedCodabar) Apply(opts CodabarOptions) error {
+	return errors.Join(b.NW7.Apply(opts.NW7Options), b.SetWideRatio(opts.WideRatio))
+}
+
+// Options returns the Code 11 settings.
+func (b *Code11) Options() Code11Options {
+	f, o := b.snapshot()
+	return Code11Options{Options1D: options1D(f, &o), CheckDigits: o.code11Checks}
+}
+
+// Apply sets the Code 11 settings.
+func (b *Code11) Apply(opts Code11Options) error {
+	return errors.Join(b.Barcode1DBase.Apply(opts.Options1D), b.SetCheckDigits(opts.CheckDigits))
+}
+
+// Options returns the MSI Plessey settings.
+func (b *MSI) Options() MSIOptions {
+	f, o := b.snapshot()
+	return MSIOptions{Options1D: options1D(f, &o), CheckScheme: o.msiCheck}
+}
+
+// Apply sets the MSI Plessey settings.
+func (b *MSI) Apply(opts MSIOptions) error {
+	return errors.Join(b.Barcode1DBase.Apply(opts.Options1D), b.SetCheckScheme(opts.CheckScheme))
+}
+
+// Options returns the Telepen settings.
+func (b *Telepen) Options() TelepenOptions {
+	f, o := b.snapshot()
+	return TelepenOptions{Options1D: options1D(f, &o), Numeric: o.telepenNumeric}
+}
+
+// Apply sets the Telepen settings.
+func (b *Telepen) Apply(opts TelepenOptions) error {
+	return errors.Join(b.Barcode1DBase.Apply(opts.Options1D), b.SetNumeric(opts.Numeric))
+}
+
+// Options returns the Code128 settings.
+func (b *Code128) Options() Code128Options {
+	f, o := b.snapshot()
+	return Code128Options{Options1D: options1D(f, &o), CodeMode: Code128Mode(o.codeMode)}
+}
+
+// Apply sets the Code128 settings.
+func (b *Code128) Apply(opts Code128Options) error {
+	return errors.Join(b.Barcode1DBase.Apply(opts.Options1D), b.SetCodeMode(opts.CodeMode))
+}
+
+// janOptions returns the settings of the JAN/UPC types.
+func janOptions(b *BarcodeBase) JANOptions {
+	f, o := b.snapshot()
+	return janOptionsOf(f, &o)
+}
+
+func janOptionsOf(f string, o *settings) JANOptions {
+	return JANOptions{
+		Options1D:        options1D(f, o),
+		ExtendedGuard:    o.extendedGuard,
+		CheckDigitPolicy: o.checkDigitPolicy,
+		AddOn:            o.addOn,
+	}
+}
+
+// Options returns the JAN-8 settings.
+func (b *Jan8) Options() JANOptions { return janOptions(b.BarcodeBase) }
+
+// Apply sets the JAN-8 settings.
+func (b *Jan8) Apply(opts JANOptions) error {
+	return errors.Join(
+		b.Barcode1DBase.Apply(opts.Options1D),
+		b.SetExtendedGuard(opts.ExtendedGuard),
+		b.SetCheckDigitPolicy(opts.CheckDigitPolicy),
+		b.SetAddOn(opts.AddOn),
+	)
+}
+
+// Options returns the JAN-13 settings.
+func (b *Jan13) Options() JANOptions { return janOptions(b.BarcodeBase) }
+
+// Apply sets the JAN-13 settings.
+func (b *Jan13) Apply(opts JANOptions) error {
+	return errors.Join(
+		b.Barcode1DBase.Apply(opts.Options1D),
+		b.SetExtendedGuard(opts.ExtendedGuard),
+		b.SetCheckDigitPolicy(opts.CheckDigitPolicy),
+		b.SetAddOn(opts.AddOn),
+	)
+}
+
+// Options returns the UPC-A settings.
+func (b *UPCA) Options() JANOptions { return janOptions(b.BarcodeBase) }
+
+// Apply sets the UPC-A settings.
+func (b *UPCA) Apply(opts JANOptions) error {
+	return errors.Join(
+		b.Barcode1DBase.Apply(opts.Options1D),
+		b.SetExtendedGuard(opts.ExtendedGuard),
+		b.SetCheckDigitPolicy(opts.CheckDigitPolicy),
+		b.SetAddOn(opts.AddOn),
+	)
+}
+
+// Options returns the UPC-E settings.
+func (b *UPCE) Options() JANOptions { return janOptions(b.BarcodeBase) }
+
+// Apply sets the UPC-E settings.
+func (b *UPCE) Apply(opts JANOptions) error {
+	return errors.Join(
+		b.Barcode1DBase.Apply(opts.Options1D),
+		b.SetExtendedGuard(opts.ExtendedGuard),
+		b.SetCheckDigitPolicy(opts.CheckDigitPolicy),
+		b.SetAddOn(opts.AddOn),
+	)
+}
+
+// Options returns the ITF-14 settings.
+func (b *ITF14) Options() ITF14Options {
+	f, o := b.snapshot()
+	return ITF14Options{
+		Options1D:        options1D(f, &o),
+		BearerStyle:      o.bearerStyle,
+		BearerWidth:      o.bearerWidth,
+		CheckDigitPolicy: o.checkDigitPolicy,
+	}
+}
+
+// Apply sets the ITF-14 settings.
+func (b *ITF14) Apply(opts ITF14Options) error {
+	return errors.Join(
+		b.Barcode1DBase.Apply(opts.Options1D),
+		b.SetBearerStyle(opts.BearerStyle),
+		b.SetBearerWidth(opts.BearerWidth),
+		b.SetCheckDigitPolicy(opts.CheckDigitPolicy),
+	)
+}
+
+// Options returns the ISSN settings.
+func (b *ISSN) Options() ISSNOptions {
+	f, o := b.snapshot()
+	return ISSNOptions{JANOptions: janOptionsOf(f, &o), Variant: o.issnVariant}
+}
+
+// Apply sets the ISSN settings.
+func (b *ISSN) Apply(opts ISSNOptions) error {
+	return errors.Join(b.Jan13.Apply(opts.JANOptions), b.SetVariant(opts.Variant))
+}
+
+// Options returns the GS1 DataBar 14 settings.
+func (b *GS1DataBar14) Options() DataBar14Options {
+	f, o := b.snapshot()
+	return DataBar14Options{Options1D: options1D(f, &o), SymbolType: DataBarSymbolType(o.symbolType14)}
+}
+
+// Apply sets the GS1 DataBar 14 settings.
+func (b *GS1DataBar14) Apply(opts DataBar14Options) error {
+	return errors.Join(b.Barcode1DBase.Apply(opts.Options1D), b.SetSymbolType(opts.SymbolType))
+}
+
+// Options returns the GS1 DataBar Expanded settings.
+func (b *GS1DataBarExpanded) Options() DataBarExpandedOptions {
+	f, o := b.snapshot()
+	return DataBarExpandedOptions{
+		Options1D:   options1D(f, &o),
+		SymbolType:  DataBarSymbolType(o.symbolTypeExp),
+		NoOfColumns: o.expColumns,
+	}
+}
+
+// Apply sets the GS1 DataBar Expanded settings.
+func (b *GS1DataBarExpanded) Apply(opts DataBarExpandedOptions) error {
+	return errors.Join(
+		b.Barcode1DBase.Apply(opts.Options1D),
+		b.SetSymbolType(opts.SymbolType),
+		b.SetNoOfColumns(opts.NoOfColumns),
+	)
+}
+
+// Options returns the YubinCustomer settings.
+func (b *YubinCustomer) Options() YubinOptions {
+	f, o := b.snapshot()
+	return YubinOptions{BaseOptions: baseOptions(f, &o), PxAdjustBlack: o.pxAdjustBlack, PxAdjustWhite: o.pxAdjustWhite}
+}
+
+// Apply sets the YubinCustomer settings.
+func (b *YubinCustomer) Apply(opts YubinOptions) error {
+	return errors.Join(
+		b.BarcodeBase.Apply(opts.BaseOptions),
+		b.SetPxAdjustBlack(opts.PxAdjustBlack),
+		b.SetPxAdjustWhite(opts.PxAdjustWhite),
+	)
+}
+
+// Options returns the QR settings.
+func (b *QR) Options() QROptions {
+	f, o := b.snapshot()
+	return QROptions{
+		Options2D:            options2D(f, &o),
+		ErrorCorrectionLevel: ECCLevel(o.eccLevel),
+		Version:              o.qrVersion,
+		EncodeMode:           QREncodeMode(o.encodeMode),
+		GS1Mode:              o.gs1,
+	}
+}
+
+// Apply sets the QR settings.
+func (b *QR) Apply(opts QROptions) error {
+	return errors.Join(
+		b.Barcode2DBase.Apply(opts.Options2D),
+		b.SetErrorCorrectionLevel(opts.ErrorCorrectionLevel),
+		b.SetVersion(opts.Version),
+		b.SetEncodeMode(opts.EncodeMode),
+		b.SetGS1Mode(opts.GS1Mode),
+	)
+}
+
+// Options returns the DataMatrix settings.
+func (b *DataMatrix) Options() DataMatrixOptions {
+	f, o := b.snapshot()
+	return DataMatrixOptions{
+		Options2D:    options2D(f, &o),
+		CodeSize:     DataMatrixSize(o.dmCodeSize),
+		EncodeScheme: DataMatrixScheme(o.dmEncodeScheme),
+		GS1Mode:      o.gs1,
+	}
+}
+
+// Apply sets the DataMatrix settings.
+func (b *DataMatrix) Apply(opts DataMatrixOptions) error {
+	return errors.Join(
+		b.Barcode2DBase.Apply(opts.Options2D),
+		b.SetCodeSize(opts.CodeSize),
+		b.SetEncodeScheme(opts.EncodeScheme),
+		b.SetGS1Mode(opts.GS1Mode),
+	)
+}
+
+// Options returns the PDF417 settings.
+func (b *PDF417) Options() PDF417Options {
+	f, o := b.snapshot()
+	return PDF417Options{
+		Options2D:   options2D(f, &o),
+		ErrorLevel:  o.pdfErrorLevel,
+		Columns:     o.pdfColumns,
+		Rows:        o.pdfRows,
+		AspectRatio: o.pdfAspectRatio,
+		YHeight:     o.pdfYHeight,
+	}
+}
+
+// Apply sets the PDF417 settings.
+func (b *PDF417) Apply(opts PDF417Options) error {
+	return errors.Join(
+		b.Barcode2DBase.Apply(opts.Options2D),
+		b.SetErrorLevel(opts.ErrorLevel),
+		b.SetColumns(opts.Columns),
+		b.SetRows(opts.Rows),
+		b.SetAspectRatio(opts.AspectRatio),
+		b.SetYHeight(opts.YHeight),
+	)
+}
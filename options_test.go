@@ -0,0 +1,84 @@
+package barcode_pao
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Apply(Options()) must leave every setting as it was.
+func TestNW7ApplyRoundTrip(t *testing.T) {
+	nw := &NW7{Barcode1DBase{newPureGoBase(typeNW7, FormatPNG)}}
+	before := nw.opts
+	if err := nw.Apply(nw.Options()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(nw.opts, before) {
+		t.Errorf("Apply(Options()) changed the settings:\n got %+v\nwant %+v", nw.opts, before)
+	}
+	if nw.opts.nw7Set {
+		t.Error("Apply(Options()) marked the start/stop options as set")
+	}
+
+	// A lower-case spelling of the current value is no change either.
+	opts := nw.Options()
+	opts.StartCharacter, opts.CheckScheme = "a", "none"
+	if err := nw.Apply(opts); err != nil {
+		t.Fatal(err)
+	}
+	if nw.opts.nw7Set {
+		t.Error("Apply with the current values in lower case marked the options as set")
+	}
+
+	opts.StopCharacter = "D"
+	if err := nw.Apply(opts); err != nil {
+		t.Fatal(err)
+	}
+	if !nw.opts.nw7Set || nw.opts.nw7Stop != "D" {
+		t.Errorf("Apply of a new stop character: nw7Set %v, stop %q", nw.opts.nw7Set, nw.opts.nw7Stop)
+	}
+	set := nw.opts
+	if err := nw.Apply(nw.Options()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(nw.opts, set) {
+		t.Errorf("Apply(Options()) after a change altered the settings:\n got %+v\nwant %+v", nw.opts, set)
+	}
+}
+
+func TestCodabarApplyRoundTrip(t *testing.T) {
+	c, err := NewRationalizedCodabarE(FormatSVG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetWideRatio(2.5); err != nil {
+		t.Fatal(err)
+	}
+	before := c.opts
+	if err := c.Apply(c.Options()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c.opts, before) {
+		t.Errorf("Apply(Options()) changed the settings:\n got %+v\nwant %+v", c.opts, before)
+	}
+}
+
+func TestQRSetVersionRange(t *testing.T) {
+	q, err := NewQRCodeE(FormatPNG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []int{0, 1, 40} {
+		if err := q.SetVersion(v); err != nil {
+			t.Errorf("SetVersion(%d): %v", v, err)
+		}
+	}
+	for _, v := range []int{-1, 41} {
+		if err := q.SetVersion(v); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("SetVersion(%d) = %v, want ErrInvalidOption", v, err)
+		}
+	}
+	if v := q.GetVersion(); v != 40 {
+		t.Errorf("a rejected version changed the setting to %d", v)
+	}
+}
@@ -0,0 +1,54 @@
+package barcode_pao
+
+import "strconv"
+
+// ─── Pharmacode ────────────────────────────────────────────────────────────
+//
+// Laetus Pharmacode (one-track) encodes a number from 3 to 131070 in
+// narrow and wide bars only. Narrow bars are 1 module, wide bars 3 and the
+// spaces 2.
+
+const (
+	pharmaMin = 3
+	pharmaMax = 131070
+)
+
+func validatePharmacode(code string, opts *settings) error {
+	if err := validateCharset(digitChars, "only digits are allowed")(code, opts); err != nil {
+		return err
+	}
+	switch n, err := strconv.Atoi(code); {
+	case err != nil || n > pharmaMax:
+		return errDraw(ReasonDataTooLong, "%s exceeds the maximum of %d", code, pharmaMax)
+	case n < pharmaMin:
+		return errDraw(ReasonInvalidLength, "%s is below the minimum of %d", code, pharmaMin)
+	}
+	return nil
+}
+
+// encodePharmacode writes the bars from the right: an even number takes a
+// wide bar and becomes (n-2)/2, an odd one a narrow bar and (n-1)/2.
+func encodePharmacode(code string, opts *settings) (*Symbol, error) {
+	if err := validators[typePharma](code, opts); err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(code)
+	var rev []int
+	for n > 0 {
+		if n%2 == 0 {
+			rev = append(rev, 3)
+			n = (n - 2) / 2
+		} else {
+			rev = append(rev, 1)
+			n = (n - 1) / 2
+		}
+	}
+	bars := make([]int, 0, 2*len(rev)-1)
+	for i := len(rev) - 1; i >= 0; i-- {
+		bars = append(bars, rev[i])
+		if i > 0 {
+			bars = append(bars, 2)
+		}
+	}
+	return &Symbol{Bars: bars, QuietZone: 6, Text: code}, nil
+}
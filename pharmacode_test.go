@@ -0,0 +1,82 @@
+package barcode_pao
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// pharmaValue reads a Pharmacode back: counting from the right from 0, a
+// narrow bar at position i adds 2^i and a wide bar 2^(i+1).
+func pharmaValue(bars []int) (int, error) {
+	n, weight := 0, 1
+	for i := len(bars) - 1; i >= 0; i -= 2 {
+		switch bars[i] {
+		case 1:
+			n += weight
+		case 3:
+			n += 2 * weight
+		default:
+			return 0, fmt.Errorf("bar %d is %d modules wide", i, bars[i])
+		}
+		if i > 0 && bars[i-1] != 2 {
+			return 0, fmt.Errorf("space %d is %d modules wide", i-1, bars[i-1])
+		}
+		weight *= 2
+	}
+	return n, nil
+}
+
+func TestPharmacode(t *testing.T) {
+	tests := []struct {
+		code string
+		bars []int
+	}{
+		{"3", []int{1, 2, 1}},
+		{"4", []int{1, 2, 3}},
+		{"6", []int{3, 2, 3}},
+		{"7", []int{1, 2, 1, 2, 1}},
+		{"91", []int{1, 2, 3, 2, 3, 2, 3, 2, 1, 2, 1}},
+	}
+	for _, tt := range tests {
+		sym, err := encodePharmacode(tt.code, testSettings())
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if !reflect.DeepEqual(sym.Bars, tt.bars) {
+			t.Errorf("%s: Bars = %v, want %v", tt.code, sym.Bars, tt.bars)
+		}
+	}
+
+	// Every value in range reads back; the maximum is 16 wide bars.
+	for n := pharmaMin; n <= pharmaMax; n += 997 {
+		for _, v := range []int{n, pharmaMax} {
+			sym, err := encodePharmacode(strconv.Itoa(v), testSettings())
+			if err != nil {
+				t.Fatalf("%d: %v", v, err)
+			}
+			if got, err := pharmaValue(sym.Bars); err != nil || got != v {
+				t.Fatalf("%d reads back as %d, %v", v, got, err)
+			}
+		}
+	}
+	sym, _ := encodePharmacode(strconv.Itoa(pharmaMax), testSettings())
+	if len(sym.Bars) != 31 || sym.Bars[0] != 3 || sym.Bars[30] != 3 {
+		t.Errorf("%d: Bars = %v", pharmaMax, sym.Bars)
+	}
+
+	for code, reason := range map[string]DrawReason{
+		"2":      ReasonInvalidLength,
+		"131071": ReasonDataTooLong,
+		"12a":    ReasonInvalidCharacter,
+	} {
+		_, err := encodePharmacode(code, testSettings())
+		var de *DrawError
+		if !errors.As(err, &de) || de.Reason != reason {
+			t.Errorf("%s: %v, want %s", code, err, reason)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package barcode_pao
+
+import "sync"
+
+// Pool hands out barcode generators to concurrent workers. A generator is
+// used by one goroutine at a time, so draws on different generators run in
+// parallel instead of queueing on one generator's lock.
+//
+// All generators come from the function passed to NewPool, which fixes the
+// symbology and the settings:
+//
+//	pool := barcode.NewPool(func() (*barcode.QR, error) {
+//		qr, err := barcode.NewQRCodeE(barcode.FormatPNG)
+//		if err != nil {
+//			return nil, err
+//		}
+//		qr.SetErrorCorrectionLevel(barcode.ECCLevelH)
+//		return qr, nil
+//	})
+//
+// Generators should be returned with the settings they were created with.
+// Like sync.Pool, a Pool may drop idle generators at any time; their native
+// handles are then released when they are garbage collected.
+type Pool[T any] struct {
+	newFn func() (T, error)
+	idle  sync.Pool
+}
+
+// NewPool returns a pool that creates generators with newFn.
+func NewPool[T any](newFn func() (T, error)) *Pool[T] {
+	return &Pool[T]{newFn: newFn}
+}
+
+// Get returns an idle generator, or a new one if none is available.
+func (p *Pool[T]) Get() (T, error) {
+	if v := p.idle.Get(); v != nil {
+		return v.(T), nil
+	}
+	return p.newFn()
+}
+
+// Put returns a generator obtained from Get to the pool. Closed generators
+// must not be put back.
+func (p *Pool[T]) Put(b T) {
+	p.idle.Put(b)
+}
+
+// Do runs f with a generator from the pool and returns it afterwards.
+func (p *Pool[T]) Do(f func(b T) error) error {
+	b, err := p.Get()
+	if err != nil {
+		return err
+	}
+	defer p.Put(b)
+	return f(b)
+}
@@ -0,0 +1,153 @@
+package barcode_pao
+
+import "image/color"
+
+// ─── Pure-Go backend ───────────────────────────────────────────────────────
+//
+// The pure-Go backend is used when the native library cannot be loaded. It
+// covers the symbologies listed in pureGoTypes; for every other type the
+// constructors still require the native library.
+
+// settings mirrors the values pushed to the native handle so that the
+// pure-Go backend can render without one.
+type settings struct {
+	fg, bg           color.RGBA
+	pxAdjustBlack    int
+	pxAdjustWhite    int
+	fitWidth         bool
+	showText         bool
+	textFontScale    float64
+	textGap          float64
+	textEvenSpacing  bool
+	stringEncoding   string
+	codeMode         string
+	eccLevel         string
+	qrVersion        int
+	encodeMode       string
+	showStartStop    bool
+	extendedGuard    bool
+	dmCodeSize       string
+	dmEncodeScheme   string
+	pdfErrorLevel    int
+	pdfColumns       int
+	pdfRows          int
+	pdfAspectRatio   float64
+	pdfYHeight       int
+	symbolType14     string
+	symbolTypeExp    string
+	expColumns       int
+	gs1              bool
+	checkDigitPolicy CheckDigitPolicy
+	addOn            string
+	issnVariant      string
+	bearerStyle      BearerStyle
+	bearerWidth      int
+	fullASCII        bool
+	code39Check      bool
+	hibcStandard     HIBCStandard
+	nw7Start         string
+	nw7Stop          string
+	nw7Check         NW7CheckScheme
+	nw7Set           bool // a start, stop or check option was set
+	codabarRatio     float64
+	code11Checks     int
+	msiCheck         MSICheckScheme
+	telepenNumeric   bool
+}
+
+// defaultSettings returns the engine defaults.
+func defaultSettings() settings {
+	return settings{
+		fg:               color.RGBA{0, 0, 0, 255},
+		bg:               color.RGBA{255, 255, 255, 255},
+		showText:         true,
+		textFontScale:    1.0,
+		textGap:          1.0,
+		stringEncoding:   "utf-8",
+		codeMode:         "AUTO",
+		eccLevel:         "M",
+		encodeMode:       "BYTE",
+		showStartStop:    true,
+		extendedGuard:    true,
+		dmCodeSize:       "AUTO",
+		dmEncodeScheme:   "AUTO",
+		pdfErrorLevel:    2,
+		pdfAspectRatio:   0.5,
+		pdfYHeight:       3,
+		symbolType14:     "OMNIDIRECTIONAL",
+		symbolTypeExp:    "UNSTACKED",
+		expColumns:       4,
+		checkDigitPolicy: CheckDigitStrict,
+		issnVariant:      "00",
+		bearerStyle:      BearerFrame,
+		bearerWidth:      5,
+		hibcStandard:     HIBCLIC,
+		nw7Start:         "A",
+		nw7Stop:          "A",
+		nw7Check:         NW7CheckNone,
+		codabarRatio:     3,
+		code11Checks:     2,
+		msiCheck:         MSICheckMod10,
+	}
+}
+
+func rgba(r, g, b, a int) color.RGBA {
+	return color.RGBA{clampByte(r), clampByte(g), clampByte(b), clampByte(a)}
+}
+
+func clampByte(v int) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
+
+// pureGoTypes lists the type IDs that can be created without the native
+// library.
+var pureGoTypes = map[int]bool{
+	typeCode128: true,
+	typeQR:      true,
+	typeITF14:   true,
+	typeCode32:  true,
+	typeHIBC:    true,
+	typeCodabar: true,
+	typeCode11:  true,
+	typeMSI:     true,
+	typePharma:  true,
+	typeTelepen: true,
+}
+
+func (b *Barcode1DBase) layoutPureGo1D(code string, width, height int) (*drawing, error) {
+	sym, err := b.encode(code)
+	if err != nil {
+		return nil, err
+	}
+	var d *drawing
+	switch g, ok := eanGeometries[b.typeID]; {
+	case ok:
+		d, err = layoutEAN(sym, g, width, height, &b.opts)
+	case b.typeID == typeITF14:
+		d, err = layoutITF14(sym, width, height, &b.opts)
+	default:
+		d, err = layoutLinear(sym, width, height, &b.opts)
+	}
+	if err != nil {
+		return nil, b.drawError(code, err)
+	}
+	return d, nil
+}
+
+func (b *Barcode2DBase) layoutPureGo2D(code string, size int) (*drawing, error) {
+	sym, err := b.encode(code)
+	if err != nil {
+		return nil, err
+	}
+	d, err := layoutMatrix(sym, size, size, &b.opts)
+	if err != nil {
+		return nil, b.drawError(code, err)
+	}
+	return d, nil
+}
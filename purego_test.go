@@ -0,0 +1,7 @@
+package barcode_pao
+
+// testSettings returns the settings of a new generator.
+func testSettings() *settings {
+	o := defaultSettings()
+	return &o
+}
@@ -0,0 +1,760 @@
+package barcode_pao
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+// ─── QR Code (pure Go) ─────────────────────────────────────────────────────
+//
+// ISO/IEC 18004 model 2, versions 1–40, error correction levels L/M/Q/H.
+
+// QR error correction levels, in the order used by the capacity tables.
+const (
+	qrECCL = iota
+	qrECCM
+	qrECCQ
+	qrECCH
+)
+
+// qrFormatBits are the two-bit level indicators of the format information.
+var qrFormatBits = [4]int{1, 0, 3, 2}
+
+// qrECCPerBlock is the number of error correction codewords per block,
+// indexed by level and version.
+var qrECCPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+// qrBlocks is the number of error correction blocks, indexed by level and
+// version.
+var qrBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// QR segment modes: indicator and character count widths for versions
+// 1–9, 10–26 and 27–40.
+type qrMode struct {
+	name      string
+	indicator int
+	countBits [3]int
+}
+
+var (
+	qrModeNumeric  = &qrMode{"NUMERIC", 0x1, [3]int{10, 12, 14}}
+	qrModeAlphanum = &qrMode{"ALPHANUMERIC", 0x2, [3]int{9, 11, 13}}
+	qrModeByte     = &qrMode{"BYTE", 0x4, [3]int{8, 16, 16}}
+	qrModeKanji    = &qrMode{"KANJI", 0x8, [3]int{8, 10, 12}}
+	// qrModeFNC1 is the FNC1 in first position indicator that marks GS1
+	// data. It has no character count or data.
+	qrModeFNC1 = &qrMode{"FNC1", 0x5, [3]int{0, 0, 0}}
+)
+
+func (m *qrMode) charCountBits(version int) int {
+	switch {
+	case version <= 9:
+		return m.countBits[0]
+	case version <= 26:
+		return m.countBits[1]
+	}
+	return m.countBits[2]
+}
+
+const qrAlphanumChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// bitBuffer is an append-only sequence of bits.
+type bitBuffer []bool
+
+func (bb *bitBuffer) appendBits(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*bb = append(*bb, (v>>i)&1 != 0)
+	}
+}
+
+// qrSegment is a run of data encoded in a single mode.
+type qrSegment struct {
+	mode     *qrMode
+	numChars int
+	data     bitBuffer
+}
+
+func qrNumericSegment(s string) (*qrSegment, error) {
+	for i, r := range []rune(s) {
+		if !isDigit(r) {
+			return nil, errInvalidChar(i, r, "not valid in NUMERIC mode")
+		}
+	}
+	seg := &qrSegment{mode: qrModeNumeric, numChars: len(s)}
+	for i := 0; i < len(s); i += 3 {
+		n := len(s) - i
+		if n > 3 {
+			n = 3
+		}
+		v := 0
+		for j := i; j < i+n; j++ {
+			v = v*10 + int(s[j]-'0')
+		}
+		seg.data.appendBits(v, n*3+1)
+	}
+	return seg, nil
+}
+
+func qrAlphanumSegment(s string) (*qrSegment, error) {
+	seg := &qrSegment{mode: qrModeAlphanum, numChars: len(s)}
+	vals := make([]int, 0, len(s))
+	for i, r := range []rune(s) {
+		v := strings.IndexRune(qrAlphanumChars, r)
+		if v < 0 {
+			return nil, errInvalidChar(i, r, "not valid in ALPHANUMERIC mode")
+		}
+		vals = append(vals, v)
+	}
+	for i := 0; i+1 < len(vals); i += 2 {
+		seg.data.appendBits(vals[i]*45+vals[i+1], 11)
+	}
+	if len(vals)%2 == 1 {
+		seg.data.appendBits(vals[len(vals)-1], 6)
+	}
+	return seg, nil
+}
+
+func qrByteSegment(b []byte) *qrSegment {
+	seg := &qrSegment{mode: qrModeByte, numChars: len(b)}
+	for _, c := range b {
+		seg.data.appendBits(int(c), 8)
+	}
+	return seg
+}
+
+// qrKanjiValue returns the 13-bit Kanji mode value of a double-byte
+// Shift_JIS character, or -1 if it is outside the Kanji mode ranges.
+func qrKanjiValue(sjis []byte) int {
+	if len(sjis) != 2 {
+		return -1
+	}
+	c := int(sjis[0])<<8 | int(sjis[1])
+	switch {
+	case c >= 0x8140 && c <= 0x9FFC:
+		c -= 0x8140
+	case c >= 0xE040 && c <= 0xEBBF:
+		c -= 0xC140
+	default:
+		return -1
+	}
+	return (c>>8)*0xC0 + c&0xFF
+}
+
+// toShiftJIS converts s to Shift_JIS.
+func toShiftJIS(s string) ([]byte, error) {
+	b, err := japanese.ShiftJIS.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		return nil, errDraw(ReasonInvalidCharacter, "%q cannot be represented in Shift_JIS", s)
+	}
+	return b, nil
+}
+
+// qrKanjiSegments encodes s with Kanji mode for every character Kanji mode
+// can hold and Byte mode (Shift_JIS) for the rest.
+func qrKanjiSegments(s string) ([]*qrSegment, error) {
+	var segs []*qrSegment
+	var kanji *qrSegment
+	var bytes []byte
+	flushBytes := func() {
+		if len(bytes) > 0 {
+			segs = append(segs, qrByteSegment(bytes))
+			bytes = nil
+		}
+	}
+	for i, r := range []rune(s) {
+		sj, err := toShiftJIS(string(r))
+		if err != nil {
+			return nil, errInvalidChar(i, r, "cannot be encoded in KANJI mode")
+		}
+		if v := qrKanjiValue(sj); v >= 0 {
+			flushBytes()
+			if kanji == nil {
+				kanji = &qrSegment{mode: qrModeKanji}
+				segs = append(segs, kanji)
+			}
+			kanji.numChars++
+			kanji.data.appendBits(v, 13)
+			continue
+		}
+		kanji = nil
+		bytes = append(bytes, sj...)
+	}
+	flushBytes()
+	return segs, nil
+}
+
+// qrByteData returns the bytes stored in Byte mode for the configured
+// string encoding.
+func qrByteData(s, encoding string) ([]byte, error) {
+	switch strings.ToLower(strings.ReplaceAll(encoding, "_", "-")) {
+	case "shift-jis", "sjis", "shiftjis", "cp932":
+		return toShiftJIS(s)
+	}
+	if !utf8.ValidString(s) {
+		return nil, errDraw(ReasonInvalidCharacter, "input is not valid UTF-8")
+	}
+	return []byte(s), nil
+}
+
+// qrGS1NumericRun is the shortest run of digits that GS1 mode puts into a
+// numeric segment of its own.
+const qrGS1NumericRun = 7
+
+// qrGS1Segments encodes a GS1 element string after an FNC1 in first
+// position indicator, ignoring the encode mode: long digit runs go into
+// numeric segments and the rest into alphanumeric or byte segments.
+func qrGS1Segments(code string) ([]*qrSegment, error) {
+	data, err := gs1Message(code)
+	if err != nil {
+		return nil, err
+	}
+	segs := []*qrSegment{{mode: qrModeFNC1}}
+	start := 0
+	for i := 0; i < len(data); {
+		n := 0
+		for i+n < len(data) && isDigit(data[i+n]) {
+			n++
+		}
+		if n < qrGS1NumericRun {
+			i += max(n, 1)
+			continue
+		}
+		if start < i {
+			segs = append(segs, qrGS1TextSegment(data[start:i]))
+		}
+		seg, err := qrNumericSegment(string(data[i : i+n]))
+		if err != nil {
+			return nil, err
+		}
+		segs = append(segs, seg)
+		i += n
+		start = i
+	}
+	if start < len(data) {
+		segs = append(segs, qrGS1TextSegment(data[start:]))
+	}
+	return segs, nil
+}
+
+// qrGS1TextSegment encodes GS1 data in alphanumeric mode, where FNC1 is
+// written as "%" and "%" as "%%", or in byte mode, where FNC1 is GS, if a
+// character is not alphanumeric.
+func qrGS1TextSegment(data []rune) *qrSegment {
+	var alnum strings.Builder
+	for _, r := range data {
+		switch {
+		case r == runeFNC1:
+			alnum.WriteByte('%')
+		case r == '%':
+			alnum.WriteString("%%")
+		case strings.ContainsRune(qrAlphanumChars, r):
+			alnum.WriteRune(r)
+		default:
+			b := make([]byte, len(data))
+			for i, r := range data {
+				b[i] = byte(r)
+				if r == runeFNC1 {
+					b[i] = gs1GS
+				}
+			}
+			return qrByteSegment(b)
+		}
+	}
+	seg, _ := qrAlphanumSegment(alnum.String())
+	return seg
+}
+
+// qrSegments splits code into segments for the configured encode mode.
+func qrSegments(code string, opts *settings) ([]*qrSegment, error) {
+	if opts.gs1 {
+		return qrGS1Segments(code)
+	}
+	switch strings.ToUpper(opts.encodeMode) {
+	case "NUMERIC":
+		seg, err := qrNumericSegment(code)
+		if err != nil {
+			return nil, err
+		}
+		return []*qrSegment{seg}, nil
+	case "ALPHANUMERIC":
+		seg, err := qrAlphanumSegment(code)
+		if err != nil {
+			return nil, err
+		}
+		return []*qrSegment{seg}, nil
+	case "KANJI":
+		return qrKanjiSegments(code)
+	case "", "BYTE":
+		b, err := qrByteData(code, opts.stringEncoding)
+		if err != nil {
+			return nil, err
+		}
+		return []*qrSegment{qrByteSegment(b)}, nil
+	}
+	return nil, errDraw(ReasonInvalidOption, "unknown encode mode %q", opts.encodeMode)
+}
+
+func qrECCIndex(level string) (int, error) {
+	switch strings.ToUpper(level) {
+	case "L":
+		return qrECCL, nil
+	case "", "M":
+		return qrECCM, nil
+	case "Q":
+		return qrECCQ, nil
+	case "H":
+		return qrECCH, nil
+	}
+	return 0, errDraw(ReasonInvalidOption, "unknown error correction level %q", level)
+}
+
+// qrRawDataModules is the number of modules available for data and ECC
+// codewords, including remainder bits.
+func qrRawDataModules(ver int) int {
+	result := (16*ver+128)*ver + 64
+	if ver >= 2 {
+		numAlign := ver/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if ver >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+// qrDataCodewords is the number of data codewords for a version and level.
+func qrDataCodewords(ver, ecl int) int {
+	return qrRawDataModules(ver)/8 - qrECCPerBlock[ecl][ver]*qrBlocks[ecl][ver]
+}
+
+// qrSegmentsBits returns the total bit length of the segments at a version,
+// or -1 if a character count overflows its field.
+func qrSegmentsBits(segs []*qrSegment, ver int) int {
+	total := 0
+	for _, seg := range segs {
+		cc := seg.mode.charCountBits(ver)
+		if seg.numChars >= 1<<cc {
+			return -1
+		}
+		total += 4 + cc + len(seg.data)
+	}
+	return total
+}
+
+// encodeQR builds the module grid for code. version 0 selects the smallest
+// version that fits.
+func encodeQR(code string, opts *settings) (*Symbol, error) {
+	ecl, err := qrECCIndex(opts.eccLevel)
+	if err != nil {
+		return nil, err
+	}
+	segs, err := qrSegments(code, opts)
+	if err != nil {
+		return nil, err
+	}
+	return qrEncodeSegments(segs, ecl, opts.qrVersion)
+}
+
+func qrEncodeSegments(segs []*qrSegment, ecl, version int) (*Symbol, error) {
+	if version < 0 || version > 40 {
+		return nil, errDraw(ReasonInvalidOption, "version %d out of range 0-40", version)
+	}
+	minVer, maxVer := 1, 40
+	if version != 0 {
+		minVer, maxVer = version, version
+	}
+	ver := 0
+	for v := minVer; v <= maxVer; v++ {
+		if n := qrSegmentsBits(segs, v); n >= 0 && n <= qrDataCodewords(v, ecl)*8 {
+			ver = v
+			break
+		}
+	}
+	if ver == 0 {
+		if version != 0 {
+			return nil, errDraw(ReasonDataTooLong, "data too long for version %d", version)
+		}
+		return nil, errDraw(ReasonDataTooLong, "data too long for any version")
+	}
+
+	var bb bitBuffer
+	for _, seg := range segs {
+		bb.appendBits(seg.mode.indicator, 4)
+		bb.appendBits(seg.numChars, seg.mode.charCountBits(ver))
+		bb = append(bb, seg.data...)
+	}
+	capacity := qrDataCodewords(ver, ecl) * 8
+	term := capacity - len(bb)
+	if term > 4 {
+		term = 4
+	}
+	bb.appendBits(0, term)
+	bb.appendBits(0, (8-len(bb)%8)%8)
+	for pad := 0xEC; len(bb) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.appendBits(pad, 8)
+	}
+	data := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			data[i>>3] |= 1 << (7 - uint(i&7))
+		}
+	}
+
+	q := newQRMatrix(ver)
+	q.drawFunctionPatterns()
+	q.drawCodewords(qrAddECC(data, ver, ecl))
+
+	// Pick the mask with the lowest penalty.
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(ecl, mask)
+		if p := q.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		q.applyMask(mask) // XOR undoes the mask
+	}
+	q.applyMask(best)
+	q.drawFormatBits(ecl, best)
+	return &Symbol{Modules: q.modules, QuietZone: 4}, nil
+}
+
+// ─── Reed–Solomon over GF(2^8), polynomial 0x11D ───
+
+func gfMul(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= ((int(y) >> i) & 1) * int(x)
+	}
+	return byte(z)
+}
+
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMul(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return result
+}
+
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMul(d, factor)
+		}
+	}
+	return result
+}
+
+// qrAddECC splits data into blocks, appends ECC to each and interleaves.
+func qrAddECC(data []byte, ver, ecl int) []byte {
+	numBlocks := qrBlocks[ecl][ver]
+	eccLen := qrECCPerBlock[ecl][ver]
+	raw := qrRawDataModules(ver) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+
+	divisor := rsDivisor(eccLen)
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := range blocks {
+		n := shortLen - eccLen
+		if i >= numShort {
+			n++
+		}
+		dat := append([]byte(nil), data[k:k+n]...)
+		k += n
+		ecc := rsRemainder(dat, divisor)
+		if i < numShort {
+			dat = append(dat, 0)
+		}
+		blocks[i] = append(dat, ecc...)
+	}
+	result := make([]byte, 0, raw)
+	for i := range blocks[0] {
+		for j, blk := range blocks {
+			// Short blocks have no codeword at the padding position.
+			if i != shortLen-eccLen || j >= numShort {
+				result = append(result, blk[i])
+			}
+		}
+	}
+	return result
+}
+
+// ─── Module placement ───
+
+type qrMatrix struct {
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+	version    int
+}
+
+func newQRMatrix(ver int) *qrMatrix {
+	size := ver*4 + 17
+	q := &qrMatrix{size: size, version: ver}
+	q.modules = make([][]bool, size)
+	q.isFunction = make([][]bool, size)
+	for i := range q.modules {
+		q.modules[i] = make([]bool, size)
+		q.isFunction[i] = make([]bool, size)
+	}
+	return q
+}
+
+func (q *qrMatrix) setFunction(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.isFunction[y][x] = true
+}
+
+func (q *qrMatrix) alignmentPositions() []int {
+	if q.version == 1 {
+		return nil
+	}
+	numAlign := q.version/7 + 2
+	step := (q.version*4 + numAlign*2 + 1) / (numAlign*2 - 2) * 2
+	if q.version == 32 {
+		step = 26
+	}
+	pos := make([]int, numAlign)
+	pos[0] = 6
+	for i, p := numAlign-1, q.size-7; i >= 1; i, p = i-1, p-step {
+		pos[i] = p
+	}
+	return pos
+}
+
+func (q *qrMatrix) drawFunctionPatterns() {
+	for i := 0; i < q.size; i++ {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+	q.drawFinder(3, 3)
+	q.drawFinder(q.size-4, 3)
+	q.drawFinder(3, q.size-4)
+
+	align := q.alignmentPositions()
+	last := len(align) - 1
+	for i, x := range align {
+		for j, y := range align {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					q.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format areas; real bits are drawn per mask.
+	q.drawFormatBits(0, 0)
+	q.drawVersion()
+}
+
+func (q *qrMatrix) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || x >= q.size || y < 0 || y >= q.size {
+				continue
+			}
+			d := max(abs(dx), abs(dy))
+			q.setFunction(x, y, d != 2 && d != 4)
+		}
+	}
+}
+
+func (q *qrMatrix) drawFormatBits(ecl, mask int) {
+	data := qrFormatBits[ecl]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>i)&1 != 0 }
+
+	for i := 0; i <= 5; i++ {
+		q.setFunction(8, i, bit(i))
+	}
+	q.setFunction(8, 7, bit(6))
+	q.setFunction(8, 8, bit(7))
+	q.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		q.setFunction(q.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, q.size-15+i, bit(i))
+	}
+	q.setFunction(8, q.size-8, true) // dark module
+}
+
+func (q *qrMatrix) drawVersion() {
+	if q.version < 7 {
+		return
+	}
+	rem := q.version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := q.version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := (bits>>i)&1 != 0
+		a, b := q.size-11+i%3, i/3
+		q.setFunction(a, b, dark)
+		q.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords places codewords in the zigzag order of ISO/IEC 18004 7.7.3.
+func (q *qrMatrix) drawCodewords(data []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if upward {
+					y = q.size - 1 - vert
+				}
+				if !q.isFunction[y][x] && i < len(data)*8 {
+					q.modules[y][x] = (data[i>>3]>>(7-uint(i&7)))&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (q *qrMatrix) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !q.isFunction[y][x] {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the current grid with the four rules of ISO/IEC 18004
+// 7.8.3.
+func (q *qrMatrix) penalty() int {
+	n := q.size
+	at := func(x, y int, transpose bool) bool {
+		if transpose {
+			return q.modules[x][y]
+		}
+		return q.modules[y][x]
+	}
+	finderA := []bool{true, false, true, true, true, false, true, false, false, false, false}
+	finderB := []bool{false, false, false, false, true, false, true, true, true, false, true}
+	result := 0
+	for _, tr := range []bool{false, true} {
+		for y := 0; y < n; y++ {
+			// N1: runs of five or more same-colour modules.
+			run := 1
+			for x := 1; x <= n; x++ {
+				if x < n && at(x, y, tr) == at(x-1, y, tr) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					result += 3 + run - 5
+				}
+				run = 1
+			}
+			// N3: finder-like 1:1:3:1:1 patterns with four light modules.
+			for x := 0; x+11 <= n; x++ {
+				matchA, matchB := true, true
+				for k := 0; k < 11; k++ {
+					v := at(x+k, y, tr)
+					matchA = matchA && v == finderA[k]
+					matchB = matchB && v == finderB[k]
+				}
+				if matchA {
+					result += 40
+				}
+				if matchB {
+					result += 40
+				}
+			}
+		}
+	}
+	// N2: 2×2 blocks of the same colour.
+	dark := 0
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			c := q.modules[y][x]
+			if c {
+				dark++
+			}
+			if x+1 < n && y+1 < n && c == q.modules[y][x+1] && c == q.modules[y+1][x] && c == q.modules[y+1][x+1] {
+				result += 3
+			}
+		}
+	}
+	// N4: deviation of the dark proportion from 50%.
+	total := n * n
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	return result + k*10
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
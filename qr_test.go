@@ -0,0 +1,535 @@
+package barcode_pao
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+// The QR tests read symbols back with qrReader, a small decoder written
+// against ISO/IEC 18004 independently of the encoder. Every symbol below was
+// also checked with an external decoder.
+
+// qrRead is what qrReader recovers from a symbol.
+type qrRead struct {
+	version, ecl, mask int
+	modes              []string
+	text               string
+}
+
+// qrReader decodes the module grid of a QR symbol without errors.
+type qrReader struct {
+	t       *testing.T
+	m       [][]bool
+	size    int
+	version int
+}
+
+func readQR(t *testing.T, modules [][]bool) qrRead {
+	t.Helper()
+	n := len(modules)
+	if n < 21 || n > 177 || (n-17)%4 != 0 {
+		t.Fatalf("symbol is %d modules wide", n)
+	}
+	r := &qrReader{t: t, m: modules, size: n, version: (n - 17) / 4}
+	r.checkPatterns()
+	ecl, mask := r.format()
+	r.checkVersion()
+	data := r.dataCodewords(r.unmask(mask), ecl)
+	modes, text := r.segments(data)
+	return qrRead{version: r.version, ecl: ecl, mask: mask, modes: modes, text: text}
+}
+
+// checkPatterns checks the finder and timing patterns and the dark module.
+func (r *qrReader) checkPatterns() {
+	n := r.size
+	for _, c := range [][2]int{{0, 0}, {n - 7, 0}, {0, n - 7}} {
+		for dy := -1; dy <= 7; dy++ {
+			for dx := -1; dx <= 7; dx++ {
+				x, y := c[0]+dx, c[1]+dy
+				if x < 0 || x >= n || y < 0 || y >= n {
+					continue
+				}
+				d := max(abs(dx-3), abs(dy-3))
+				if want := d != 2 && d != 4; r.m[y][x] != want {
+					r.t.Fatalf("finder at (%d,%d): module (%d,%d) wrong", c[0], c[1], x, y)
+				}
+			}
+		}
+	}
+	for i := 8; i < n-8; i++ {
+		if r.m[6][i] != (i%2 == 0) || r.m[i][6] != (i%2 == 0) {
+			r.t.Fatalf("timing pattern wrong at %d", i)
+		}
+	}
+	if !r.m[n-8][8] {
+		r.t.Fatal("dark module missing")
+	}
+}
+
+// format reads both copies of the format information.
+func (r *qrReader) format() (ecl, mask int) {
+	n := r.size
+	read := func(pos [15][2]int) int {
+		v := 0
+		for i, p := range pos {
+			if r.m[p[1]][p[0]] {
+				v |= 1 << i
+			}
+		}
+		return v
+	}
+	var first, second [15][2]int
+	for i := 0; i < 15; i++ {
+		switch {
+		case i < 6:
+			first[i] = [2]int{8, i}
+		case i < 8:
+			first[i] = [2]int{8, i + 1}
+		case i == 8:
+			first[i] = [2]int{7, 8}
+		default:
+			first[i] = [2]int{14 - i, 8}
+		}
+		if i < 8 {
+			second[i] = [2]int{n - 1 - i, 8}
+		} else {
+			second[i] = [2]int{8, n - 15 + i}
+		}
+	}
+	v := read(first)
+	if w := read(second); w != v {
+		r.t.Fatalf("format copies differ: %015b, %015b", v, w)
+	}
+	v ^= 0x5412
+	if polyMod(v, 0x537, 10) != 0 {
+		r.t.Fatalf("format information %015b fails its BCH check", v)
+	}
+	level := [4]int{qrECCM, qrECCL, qrECCH, qrECCQ}
+	return level[v>>13], v >> 10 & 7
+}
+
+// checkVersion checks both copies of the version information of versions 7
+// and up.
+func (r *qrReader) checkVersion() {
+	if r.version < 7 {
+		return
+	}
+	var a, b int
+	for i := 0; i < 18; i++ {
+		if r.m[i/3][r.size-11+i%3] {
+			a |= 1 << i
+		}
+		if r.m[r.size-11+i%3][i/3] {
+			b |= 1 << i
+		}
+	}
+	if a != b || a>>12 != r.version || polyMod(a, 0x1F25, 12) != 0 {
+		r.t.Fatalf("version information %018b, %018b for version %d", a, b, r.version)
+	}
+}
+
+// polyMod returns v modulo the generator g of degree deg over GF(2).
+func polyMod(v, g, deg int) int {
+	for i := 31; i >= deg; i-- {
+		if v>>i&1 != 0 {
+			v ^= g << (i - deg)
+		}
+	}
+	return v
+}
+
+// unmask returns the codeword bits of the symbol in placement order.
+func (r *qrReader) unmask(mask int) []bool {
+	masks := [8]func(i, j int) bool{
+		func(i, j int) bool { return (i+j)%2 == 0 },
+		func(i, j int) bool { return i%2 == 0 },
+		func(i, j int) bool { return j%3 == 0 },
+		func(i, j int) bool { return (i+j)%3 == 0 },
+		func(i, j int) bool { return (i/2+j/3)%2 == 0 },
+		func(i, j int) bool { return i*j%2+i*j%3 == 0 },
+		func(i, j int) bool { return (i*j%2+i*j%3)%2 == 0 },
+		func(i, j int) bool { return ((i+j)%2+i*j%3)%2 == 0 },
+	}
+	fn := newQRMatrix(r.version)
+	fn.drawFunctionPatterns()
+	var bits []bool
+	for right := r.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right--
+		}
+		for vert := 0; vert < r.size; vert++ {
+			i := vert
+			if (right+1)&2 == 0 {
+				i = r.size - 1 - vert
+			}
+			for j := right; j >= right-1; j-- {
+				if !fn.isFunction[i][j] {
+					bits = append(bits, r.m[i][j] != masks[mask](i, j))
+				}
+			}
+		}
+	}
+	return bits
+}
+
+// dataCodewords de-interleaves the codewords, checks the Reed–Solomon
+// syndromes of every block and returns the data codewords.
+func (r *qrReader) dataCodewords(bits []bool, ecl int) []byte {
+	cw := make([]byte, len(bits)/8)
+	for i := range cw {
+		for _, b := range bits[i*8 : i*8+8] {
+			cw[i] <<= 1
+			if b {
+				cw[i] |= 1
+			}
+		}
+	}
+	numBlocks, eccLen := qrBlocks[ecl][r.version], qrECCPerBlock[ecl][r.version]
+	numLong := len(cw) % numBlocks
+	shortData := len(cw)/numBlocks - eccLen
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortData; i++ {
+		for j := range blocks {
+			if i < shortData || j >= numBlocks-numLong {
+				blocks[j] = append(blocks[j], cw[k])
+				k++
+			}
+		}
+	}
+	for i := 0; i < eccLen; i++ {
+		for j := range blocks {
+			blocks[j] = append(blocks[j], cw[k])
+			k++
+		}
+	}
+	var data []byte
+	for j, blk := range blocks {
+		for s := 0; s < eccLen; s++ {
+			if v := gfEval(blk, gfPow(s)); v != 0 {
+				r.t.Fatalf("block %d: syndrome %d is %#x", j, s, v)
+			}
+		}
+		data = append(data, blk[:len(blk)-eccLen]...)
+	}
+	return data
+}
+
+// gfPow returns α^e in GF(2^8) with polynomial 0x11D.
+func gfPow(e int) byte {
+	v := 1
+	for ; e > 0; e-- {
+		v <<= 1
+		if v&0x100 != 0 {
+			v ^= 0x11D
+		}
+	}
+	return byte(v)
+}
+
+// gfEval evaluates the polynomial with coefficients c, highest degree
+// first, at x.
+func gfEval(c []byte, x byte) byte {
+	var v byte
+	for _, b := range c {
+		// Multiply by x with shift-and-add.
+		var p byte
+		for a, m := v, x; m != 0; m >>= 1 {
+			if m&1 != 0 {
+				p ^= a
+			}
+			hi := a & 0x80
+			a <<= 1
+			if hi != 0 {
+				a ^= 0x1D
+			}
+		}
+		v = p ^ b
+	}
+	return v
+}
+
+// segments parses the data bit stream.
+func (r *qrReader) segments(data []byte) (modes []string, text string) {
+	pos := 0
+	take := func(n int) int {
+		v := 0
+		for ; n > 0; n-- {
+			if pos >= len(data)*8 {
+				r.t.Fatal("bit stream ends inside a segment")
+			}
+			v = v<<1 | int(data[pos>>3]>>(7-pos&7)&1)
+			pos++
+		}
+		return v
+	}
+	class := 0
+	if r.version >= 27 {
+		class = 2
+	} else if r.version >= 10 {
+		class = 1
+	}
+	var sb strings.Builder
+	for pos+4 <= len(data)*8 {
+		mode := take(4)
+		switch mode {
+		case 0:
+			return modes, sb.String()
+		case 0x1:
+			modes = append(modes, "NUMERIC")
+			n := take([3]int{10, 12, 14}[class])
+			for ; n >= 3; n -= 3 {
+				fmt.Fprintf(&sb, "%03d", take(10))
+			}
+			if n == 2 {
+				fmt.Fprintf(&sb, "%02d", take(7))
+			} else if n == 1 {
+				fmt.Fprintf(&sb, "%d", take(4))
+			}
+		case 0x2:
+			modes = append(modes, "ALPHANUMERIC")
+			n := take([3]int{9, 11, 13}[class])
+			for ; n >= 2; n -= 2 {
+				v := take(11)
+				sb.WriteByte(qrAlphanumChars[v/45])
+				sb.WriteByte(qrAlphanumChars[v%45])
+			}
+			if n == 1 {
+				sb.WriteByte(qrAlphanumChars[take(6)])
+			}
+		case 0x4:
+			modes = append(modes, "BYTE")
+			n := take([3]int{8, 16, 16}[class])
+			for ; n > 0; n-- {
+				sb.WriteByte(byte(take(8)))
+			}
+		case 0x8:
+			modes = append(modes, "KANJI")
+			n := take([3]int{8, 10, 12}[class])
+			var sjis []byte
+			for ; n > 0; n-- {
+				v := take(13)
+				c := v/0xC0<<8 | v%0xC0
+				if c += 0x8140; c > 0x9FFC {
+					c += 0xC140 - 0x8140
+				}
+				sjis = append(sjis, byte(c>>8), byte(c))
+			}
+			s, err := japanese.ShiftJIS.NewDecoder().Bytes(sjis)
+			if err != nil {
+				r.t.Fatal(err)
+			}
+			sb.Write(s)
+		case 0x5:
+			modes = append(modes, "FNC1")
+		default:
+			r.t.Fatalf("unexpected mode indicator %#x", mode)
+		}
+	}
+	return modes, sb.String()
+}
+
+func TestQRDecode(t *testing.T) {
+	tests := []struct {
+		code, mode, ecl string
+		version         int // 0: auto
+		want            qrRead
+	}{
+		{"01234567", "NUMERIC", "M", 0, qrRead{version: 1, ecl: qrECCM, modes: []string{"NUMERIC"}}},
+		{"HELLO WORLD $%*+-./:", "ALPHANUMERIC", "Q", 0, qrRead{version: 2, ecl: qrECCQ, modes: []string{"ALPHANUMERIC"}}},
+		{"https://example.com/日本語", "BYTE", "L", 0, qrRead{version: 2, ecl: qrECCL, modes: []string{"BYTE"}}},
+		{"点茗漢字テスト", "KANJI", "H", 0, qrRead{version: 2, ecl: qrECCH, modes: []string{"KANJI"}}},
+		{"Kanji 点茗 and bytes", "KANJI", "L", 0, qrRead{version: 2, ecl: qrECCL, modes: []string{"BYTE", "KANJI", "BYTE"}}},
+		{"1234567890", "NUMERIC", "H", 7, qrRead{version: 7, ecl: qrECCH, modes: []string{"NUMERIC"}}},
+		{"version ten payload", "BYTE", "M", 10, qrRead{version: 10, ecl: qrECCM, modes: []string{"BYTE"}}},
+		{"ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789", "ALPHANUMERIC", "L", 14, qrRead{version: 14, ecl: qrECCL, modes: []string{"ALPHANUMERIC"}}},
+		{"x", "BYTE", "L", 40, qrRead{version: 40, ecl: qrECCL, modes: []string{"BYTE"}}},
+		{strings.Repeat("0123456789", 100), "NUMERIC", "Q", 0, qrRead{version: 19, ecl: qrECCQ, modes: []string{"NUMERIC"}}},
+	}
+	for _, tt := range tests {
+		o := testSettings()
+		o.encodeMode, o.eccLevel, o.qrVersion = tt.mode, tt.ecl, tt.version
+		sym, err := encodeQR(tt.code, o)
+		if err != nil {
+			t.Errorf("%q: %v", tt.code, err)
+			continue
+		}
+		got := readQR(t, sym.Modules)
+		if got.text != tt.code {
+			t.Errorf("%q: decoded %q", tt.code, got.text)
+		}
+		if got.version != tt.want.version || got.ecl != tt.want.ecl || fmt.Sprint(got.modes) != fmt.Sprint(tt.want.modes) {
+			t.Errorf("%q: version %d, level %d, modes %v; want %d, %d, %v",
+				tt.code, got.version, got.ecl, got.modes, tt.want.version, tt.want.ecl, tt.want.modes)
+		}
+		if sym.QuietZone != 4 || sym.Width() != 17+4*got.version || sym.Height() != sym.Width() {
+			t.Errorf("%q: %dx%d modules, quiet zone %d", tt.code, sym.Width(), sym.Height(), sym.QuietZone)
+		}
+	}
+}
+
+func TestQRGS1(t *testing.T) {
+	tests := []struct {
+		code, text string
+		modes      []string
+	}{
+		// Long digit runs go into numeric segments.
+		{"(01)04912345123459(10)ABC", "010491234512345910ABC", []string{"FNC1", "NUMERIC", "ALPHANUMERIC"}},
+		// FNC1 is "%" in alphanumeric mode.
+		{"(10)ABC(21)123", "10ABC%21123", []string{"FNC1", "ALPHANUMERIC"}},
+		// Lower case needs byte mode, where FNC1 is GS.
+		{"(10)abc(21)12", "10abc\x1d2112", []string{"FNC1", "BYTE"}},
+	}
+	for _, tt := range tests {
+		o := testSettings()
+		o.gs1 = true
+		sym, err := encodeQR(tt.code, o)
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		got := readQR(t, sym.Modules)
+		if got.text != tt.text || fmt.Sprint(got.modes) != fmt.Sprint(tt.modes) {
+			t.Errorf("%s: %q in %v, want %q in %v", tt.code, got.text, got.modes, tt.text, tt.modes)
+		}
+	}
+}
+
+// The smallest version is chosen from the capacities of ISO/IEC 18004
+// Table 7.
+func TestQRVersionSelection(t *testing.T) {
+	tests := []struct {
+		mode, ecl string
+		n         int
+		version   int
+	}{
+		{"NUMERIC", "L", 41, 1},
+		{"NUMERIC", "L", 42, 2},
+		{"ALPHANUMERIC", "L", 25, 1},
+		{"ALPHANUMERIC", "L", 26, 2},
+		{"BYTE", "L", 17, 1},
+		{"BYTE", "L", 18, 2},
+		{"BYTE", "H", 7, 1},
+		{"BYTE", "H", 8, 2},
+		{"BYTE", "M", 180, 9},
+		{"BYTE", "M", 181, 10},
+		{"BYTE", "M", 213, 10},
+		{"BYTE", "M", 214, 11},
+		{"NUMERIC", "L", 7089, 40},
+	}
+	for _, tt := range tests {
+		o := testSettings()
+		o.encodeMode, o.eccLevel = tt.mode, tt.ecl
+		code := strings.Repeat("1", tt.n)
+		sym, err := encodeQR(code, o)
+		if err != nil {
+			t.Errorf("%d %s at %s: %v", tt.n, tt.mode, tt.ecl, err)
+			continue
+		}
+		if v := (sym.Width() - 17) / 4; v != tt.version {
+			t.Errorf("%d %s at %s: version %d, want %d", tt.n, tt.mode, tt.ecl, v, tt.version)
+		}
+	}
+	o := testSettings()
+	o.encodeMode, o.eccLevel = "NUMERIC", "L"
+	if _, err := encodeQR(strings.Repeat("1", 7090), o); err == nil {
+		t.Error("7090 digits accepted at level L")
+	}
+	o.qrVersion = 1
+	if _, err := encodeQR(strings.Repeat("1", 42), o); err == nil {
+		t.Error("42 digits accepted in version 1-L")
+	}
+}
+
+// The worked example of ISO/IEC 18004 Annex I: "01234567" in version 1-M.
+func TestQRAnnexI(t *testing.T) {
+	seg, err := qrNumericSegment("01234567")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bits strings.Builder
+	for _, b := range seg.data {
+		if b {
+			bits.WriteByte('1')
+		} else {
+			bits.WriteByte('0')
+		}
+	}
+	if want := "000000110001010110011000011"; bits.String() != want {
+		t.Errorf("numeric data %s, want %s", bits.String(), want)
+	}
+	data := []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	want := append(append([]byte(nil), data...), 0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55)
+	if got := qrAddECC(data, 1, qrECCM); !bytes.Equal(got, want) {
+		t.Errorf("codewords % X\nwant      % X", got, want)
+	}
+
+	o := testSettings()
+	o.encodeMode = "NUMERIC"
+	sym, err := encodeQR("01234567", o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := readQR(t, sym.Modules)
+	if got.text != "01234567" {
+		t.Errorf("decoded %q", got.text)
+	}
+}
+
+// The encoder keeps the mask with the lowest penalty score.
+func TestQRMaskSelection(t *testing.T) {
+	masks := make(map[int]bool)
+	for _, code := range []string{"A", "01234567", "https://example.com/", "QR mask selection", strings.Repeat("\x00", 40), strings.Repeat("\xff", 40)} {
+		sym, err := qrEncodeSegments([]*qrSegment{qrByteSegment([]byte(code))}, qrECCM, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		read := readQR(t, sym.Modules)
+		masks[read.mask] = true
+
+		q := newQRMatrix(read.version)
+		q.drawFunctionPatterns()
+		for y := range q.modules {
+			copy(q.modules[y], sym.Modules[y])
+		}
+		q.applyMask(read.mask)
+		var scores [8]int
+		for m := range scores {
+			q.applyMask(m)
+			q.drawFormatBits(read.ecl, m)
+			scores[m] = q.penalty()
+			q.applyMask(m)
+		}
+		for m, p := range scores {
+			if p < scores[read.mask] {
+				t.Errorf("%q: mask %d scores %d, below %d of the chosen mask %d", code, m, p, scores[read.mask], read.mask)
+			}
+		}
+	}
+	if len(masks) < 2 {
+		t.Errorf("every symbol uses mask %v", masks)
+	}
+}
+
+func TestQRPenalty(t *testing.T) {
+	// An all-light version 1 grid: rule 1 scores each of the 21 rows and
+	// columns 3+16, rule 2 scores the 20×20 blocks 3 each, rule 3 finds no
+	// finder-like pattern and rule 4 scores 90 for 0% dark modules.
+	q := newQRMatrix(1)
+	want := 2*21*(3+16) + 20*20*3 + 90
+	if got := q.penalty(); got != want {
+		t.Errorf("penalty of a light grid = %d, want %d", got, want)
+	}
+
+	// A finder-like pattern in the first row adds 40 and splits its runs.
+	for x, dark := range []bool{true, false, true, true, true, false, true} {
+		q.modules[0][x] = dark
+	}
+	if got := q.penalty(); got <= 2*21*(3+16) {
+		t.Errorf("penalty with a finder-like row = %d", got)
+	}
+}
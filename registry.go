@@ -0,0 +1,348 @@
+package barcode_pao
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ─── Registry ──────────────────────────────────────────────────────────────
+//
+// The registry maps barcode kind names to constructors, so that templates
+// can refer to a symbology by name:
+//
+//	b, err := barcode.New("jan13", barcode.FormatPNG)
+//	if err != nil {
+//		return err
+//	}
+//	defer b.Close()
+//	png, err := b.DrawRectBytes("490123456789", 300, 120)
+
+// Barcode is implemented by every generator. DrawRect draws into a
+// width x height box whatever the symbology: 2D symbols use the smaller of
+// the two as their size, and YubinCustomer computes the width itself when
+// width is 0. The type-specific settings are reached by a type assertion;
+// Kind reports which of them a generator has.
+type Barcode interface {
+	io.Closer
+	Kind() KindInfo
+	SetOutputFormat(format string) error
+	SetForegroundColor(r, g, b, a int) error
+	SetBackgroundColor(r, g, b, a int) error
+	DrawRect(code string, width, height int) (string, error)
+	DrawRectBytes(code string, width, height int) ([]byte, error)
+	DrawRectTo(w io.Writer, code string, width, height int) error
+	DrawRectImage(code string, width, height int) (image.Image, error)
+	Encode(code string) (*Symbol, error)
+}
+
+// Capability is a set of optional features of a barcode kind. Each one
+// stands for the methods named in its comment.
+type Capability uint32
+
+// Capabilities of the barcode kinds.
+const (
+	// CapText: SetShowText, SetTextGap, SetTextFontScale, SetTextEvenSpacing.
+	CapText Capability = 1 << iota
+	// CapShowStartStop: SetShowStartStop.
+	CapShowStartStop
+	// CapExtendedGuard: SetExtendedGuard.
+	CapExtendedGuard
+	// CapCodeMode: SetCodeMode.
+	CapCodeMode
+	// CapSymbolType: SetSymbolType.
+	CapSymbolType
+	// CapStacked: DrawStacked and SetNoOfColumns.
+	CapStacked
+	// CapConvenience: DrawConvenience.
+	CapConvenience
+	// CapGS1: the input is a GS1 element string.
+	CapGS1
+	// CapStringEncoding: SetStringEncoding.
+	CapStringEncoding
+	// CapErrorCorrection: SetErrorCorrectionLevel (QR) or SetErrorLevel
+	// (PDF417).
+	CapErrorCorrection
+	// CapVersion: SetVersion.
+	CapVersion
+	// CapEncodeMode: SetEncodeMode.
+	CapEncodeMode
+	// CapCodeSize: SetCodeSize.
+	CapCodeSize
+	// CapEncodeScheme: SetEncodeScheme.
+	CapEncodeScheme
+	// CapRowsColumns: SetRows, SetColumns, SetAspectRatio, SetYHeight.
+	CapRowsColumns
+	// CapGS1Mode: SetGS1Mode.
+	CapGS1Mode
+	// CapCheckDigitPolicy: SetCheckDigitPolicy.
+	CapCheckDigitPolicy
+	// CapAddOn: SetAddOn.
+	CapAddOn
+	// CapBearer: SetBearerStyle, SetBearerWidth.
+	CapBearer
+	// CapFullASCII: SetFullASCII.
+	CapFullASCII
+	// CapCheckCharacter: SetCheckCharacter.
+	CapCheckCharacter
+	// CapHIBCStandard: SetStandard.
+	CapHIBCStandard
+	// CapStartStopCharacter: SetStartCharacter, SetStopCharacter.
+	CapStartStopCharacter
+	// CapCheckScheme: SetCheckScheme.
+	CapCheckScheme
+	// CapWideRatio: SetWideRatio.
+	CapWideRatio
+	// CapCheckDigits: SetCheckDigits.
+	CapCheckDigits
+	// CapNumeric: SetNumeric.
+	CapNumeric
+)
+
+var capabilityNames = [...]string{
+	"text", "show-start-stop", "extended-guard", "code-mode", "symbol-type", "stacked",
+	"convenience", "gs1", "string-encoding", "error-correction", "version", "encode-mode",
+	"code-size", "encode-scheme", "rows-columns", "gs1-mode", "check-digit-policy",
+	"add-on", "bearer", "full-ascii", "check-character", "hibc-standard",
+	"start-stop-character", "check-scheme", "wide-ratio", "check-digits", "numeric",
+}
+
+// Has reports whether c includes all of want.
+func (c Capability) Has(want Capability) bool { return c&want == want }
+
+func (c Capability) String() string {
+	var names []string
+	for i, n := range capabilityNames {
+		if c&(1<<i) != 0 {
+			names = append(names, n)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// KindInfo describes a registered barcode kind.
+type KindInfo struct {
+	// Name is the canonical registry name, e.g. "code128".
+	Name string
+	// Aliases are further names New accepts for the kind.
+	Aliases []string
+	// TypeID is the BC_* type ID of barcode_ffi.h; the symbologies drawn
+	// only in Go have IDs from 100 up.
+	TypeID int
+	// Matrix is true for 2D symbologies.
+	Matrix bool
+	// PureGo is true if the kind can be created without the native
+	// library.
+	PureGo bool
+	// Capabilities lists the optional features of the kind.
+	Capabilities Capability
+
+	newFn func(format string) (Barcode, error)
+}
+
+// factory adapts a typed constructor to the registry.
+func factory[T Barcode](newFn func(string) (T, error)) func(string) (Barcode, error) {
+	return func(format string) (Barcode, error) {
+		b, err := newFn(format)
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+}
+
+const (
+	cap1D  = CapText
+	capJAN = CapText | CapExtendedGuard | CapCheckDigitPolicy | CapAddOn
+	cap2D  = CapStringEncoding
+)
+
+// kinds is the registry, in BC_* order followed by the kinds drawn as one
+// of the BC_* types and the Go-only ones.
+var kinds = []KindInfo{
+	{Name: "code39", TypeID: typeCode39, Capabilities: cap1D | CapShowStartStop | CapFullASCII | CapCheckCharacter, newFn: factory(NewCode39E)},
+	{Name: "code93", TypeID: typeCode93, Capabilities: cap1D, newFn: factory(NewCode93E)},
+	{Name: "code128", TypeID: typeCode128, Capabilities: cap1D | CapCodeMode, newFn: factory(NewCode128E)},
+	{Name: "gs1128", Aliases: []string{"ean128"}, TypeID: typeGS1128, Capabilities: cap1D | CapGS1 | CapConvenience, newFn: factory(NewGS1128E)},
+	{Name: "nw7", Aliases: []string{"codabar"}, TypeID: typeNW7, Capabilities: cap1D | CapShowStartStop | CapStartStopCharacter | CapCheckScheme, newFn: factory(NewNW7E)},
+	{Name: "matrix2of5", TypeID: typeMatrix2of5, Capabilities: cap1D, newFn: factory(NewMatrix2of5E)},
+	{Name: "nec2of5", TypeID: typeNEC2of5, Capabilities: cap1D, newFn: factory(NewNEC2of5E)},
+	{Name: "jan8", Aliases: []string{"ean8"}, TypeID: typeJan8, Capabilities: capJAN, newFn: factory(NewJAN8E)},
+	{Name: "jan13", Aliases: []string{"ean13"}, TypeID: typeJan13, Capabilities: capJAN, newFn: factory(NewJAN13E)},
+	{Name: "upca", TypeID: typeUPCA, Capabilities: capJAN, newFn: factory(NewUPCAE)},
+	{Name: "upce", TypeID: typeUPCE, Capabilities: capJAN, newFn: factory(NewUPCEE)},
+	{Name: "itf", TypeID: typeITF, Capabilities: cap1D, newFn: factory(NewITFE)},
+	{Name: "databar14", Aliases: []string{"gs1databar14", "gs1databar"}, TypeID: typeGS1DataBar14, Capabilities: cap1D | CapSymbolType, newFn: factory(NewGS1DataBar14E)},
+	{Name: "databarlimited", Aliases: []string{"gs1databarlimited"}, TypeID: typeGS1DataBarLimited, Capabilities: cap1D, newFn: factory(NewGS1DataBarLimitedE)},
+	{Name: "databarexpanded", Aliases: []string{"gs1databarexpanded"}, TypeID: typeGS1DataBarExpanded, Capabilities: cap1D | CapGS1 | CapSymbolType | CapStacked, newFn: factory(NewGS1DataBarExpandedE)},
+	{Name: "yubin", Aliases: []string{"yubincustomer"}, TypeID: typeYubinCustomer, newFn: factory(NewYubinCustomerE)},
+	{Name: "qr", Aliases: []string{"qrcode"}, TypeID: typeQR, Matrix: true, Capabilities: cap2D | CapErrorCorrection | CapVersion | CapEncodeMode | CapGS1Mode, newFn: factory(NewQRCodeE)},
+	{Name: "datamatrix", TypeID: typeDataMatrix, Matrix: true, Capabilities: cap2D | CapCodeSize | CapEncodeScheme | CapGS1Mode, newFn: factory(NewDataMatrixE)},
+	{Name: "pdf417", TypeID: typePDF417, Matrix: true, Capabilities: cap2D | CapErrorCorrection | CapRowsColumns, newFn: factory(NewPDF417E)},
+
+	// Publication identifiers, drawn as JAN-13 by the pure-Go backend.
+	{Name: "isbn", Aliases: []string{"bookland"}, TypeID: typeJan13, PureGo: true, Capabilities: capJAN, newFn: factory(NewISBNE)},
+	{Name: "issn", TypeID: typeJan13, PureGo: true, Capabilities: capJAN, newFn: factory(NewISSNE)},
+	{Name: "ismn", TypeID: typeJan13, PureGo: true, Capabilities: capJAN, newFn: factory(NewISMNE)},
+
+	// Symbologies drawn only by the pure-Go backend.
+	{Name: "itf14", TypeID: typeITF14, Capabilities: cap1D | CapCheckDigitPolicy | CapBearer, newFn: factory(NewITF14E)},
+	{Name: "code32", Aliases: []string{"italianpharmacode"}, TypeID: typeCode32, Capabilities: cap1D, newFn: factory(NewCode32E)},
+	{Name: "hibc", Aliases: []string{"hibc39"}, TypeID: typeHIBC, Capabilities: cap1D | CapShowStartStop | CapHIBCStandard, newFn: factory(NewHIBCE)},
+	{Name: "rationalizedcodabar", TypeID: typeCodabar, Capabilities: cap1D | CapShowStartStop | CapStartStopCharacter | CapCheckScheme | CapWideRatio, newFn: factory(NewRationalizedCodabarE)},
+	{Name: "code11", Aliases: []string{"usd8"}, TypeID: typeCode11, Capabilities: cap1D | CapCheckDigits, newFn: factory(NewCode11E)},
+	{Name: "msi", Aliases: []string{"msiplessey"}, TypeID: typeMSI, Capabilities: cap1D | CapCheckScheme, newFn: factory(NewMSIE)},
+	{Name: "pharmacode", Aliases: []string{"laetus"}, TypeID: typePharma, Capabilities: cap1D, newFn: factory(NewPharmacodeE)},
+	{Name: "telepen", TypeID: typeTelepen, Capabilities: cap1D | CapNumeric, newFn: factory(NewTelepenE)},
+}
+
+// kindIndex maps normalized names and aliases to indexes into kinds.
+var kindIndex = func() map[string]int {
+	idx := make(map[string]int)
+	for i := range kinds {
+		kinds[i].PureGo = kinds[i].PureGo || pureGoTypes[kinds[i].TypeID]
+		for _, n := range append([]string{kinds[i].Name}, kinds[i].Aliases...) {
+			idx[normalizeKind(n)] = i
+		}
+	}
+	return idx
+}()
+
+// normalizeKind folds case and drops separators, so that "GS1-128",
+// "gs1_128" and "gs1128" name the same kind.
+func normalizeKind(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', ' ', '.':
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// New creates a generator of the named kind. Names are matched ignoring
+// case and separators; see Kinds for the names and aliases.
+func New(kind, format string) (Barcode, error) {
+	k, ok := LookupKind(kind)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+	return k.newFn(format)
+}
+
+// LookupKind returns the kind registered under name or one of its aliases.
+func LookupKind(name string) (KindInfo, bool) {
+	i, ok := kindIndex[normalizeKind(name)]
+	if !ok {
+		return KindInfo{}, false
+	}
+	return kinds[i], true
+}
+
+// Kinds returns all registered kinds ordered by type ID.
+func Kinds() []KindInfo {
+	out := append([]KindInfo(nil), kinds...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].TypeID < out[j].TypeID })
+	return out
+}
+
+// Kind returns the registry entry of the generator's type.
+func (b *BarcodeBase) Kind() KindInfo {
+	for _, k := range kinds {
+		if k.TypeID == b.typeID && (b.ident == nil || strings.EqualFold(k.Name, b.ident.name)) {
+			return k
+		}
+	}
+	return KindInfo{TypeID: b.typeID}
+}
+
+// ─── DrawRect ──────────────────────────────────────────────────────────────
+//
+// DrawRect and its variants give every generator the same drawing
+// signature. They take the code and box size of the type's own Draw methods
+// and render exactly as those do.
+
+// DrawRect is Draw; it makes 1D generators implement Barcode.
+func (b *Barcode1DBase) DrawRect(code string, width, height int) (string, error) {
+	return b.Draw(code, width, height)
+}
+
+// DrawRectBytes is DrawBytes.
+func (b *Barcode1DBase) DrawRectBytes(code string, width, height int) ([]byte, error) {
+	return b.DrawBytes(code, width, height)
+}
+
+// DrawRectTo is DrawTo.
+func (b *Barcode1DBase) DrawRectTo(w io.Writer, code string, width, height int) error {
+	return b.DrawTo(w, code, width, height)
+}
+
+// DrawRectImage is DrawImage.
+func (b *Barcode1DBase) DrawRectImage(code string, width, height int) (image.Image, error) {
+	return b.DrawImage(code, width, height)
+}
+
+// DrawRect draws a square symbol of the smaller of width and height.
+func (b *Barcode2DBase) DrawRect(code string, width, height int) (string, error) {
+	return b.Draw(code, min(width, height))
+}
+
+// DrawRectBytes is DrawBytes with the smaller of width and height.
+func (b *Barcode2DBase) DrawRectBytes(code string, width, height int) ([]byte, error) {
+	return b.DrawBytes(code, min(width, height))
+}
+
+// DrawRectTo is DrawTo with the smaller of width and height.
+func (b *Barcode2DBase) DrawRectTo(w io.Writer, code string, width, height int) error {
+	return b.DrawTo(w, code, min(width, height))
+}
+
+// DrawRectImage is DrawImage with the smaller of width and height.
+func (b *Barcode2DBase) DrawRectImage(code string, width, height int) (image.Image, error) {
+	return b.DrawImage(code, min(width, height))
+}
+
+// DrawRect is Draw; PDF417 symbols fill the whole box.
+func (b *PDF417) DrawRect(code string, width, height int) (string, error) {
+	return b.Draw(code, width, height)
+}
+
+// DrawRectBytes is DrawBytes.
+func (b *PDF417) DrawRectBytes(code string, width, height int) ([]byte, error) {
+	return b.DrawBytes(code, width, height)
+}
+
+// DrawRectTo is DrawTo.
+func (b *PDF417) DrawRectTo(w io.Writer, code string, width, height int) error {
+	return b.DrawTo(w, code, width, height)
+}
+
+// DrawRectImage is DrawImage.
+func (b *PDF417) DrawRectImage(code string, width, height int) (image.Image, error) {
+	return b.DrawImage(code, width, height)
+}
+
+// DrawRect is DrawWithWidth; a width of 0 selects the automatic width.
+func (b *YubinCustomer) DrawRect(code string, width, height int) (string, error) {
+	return b.DrawWithWidth(code, width, height)
+}
+
+// DrawRectBytes is DrawBytesWithWidth.
+func (b *YubinCustomer) DrawRectBytes(code string, width, height int) ([]byte, error) {
+	return b.DrawBytesWithWidth(code, width, height)
+}
+
+// DrawRectTo is DrawToWithWidth.
+func (b *YubinCustomer) DrawRectTo(w io.Writer, code string, width, height int) error {
+	return b.DrawToWithWidth(w, code, width, height)
+}
+
+// DrawRectImage is DrawImageWithWidth.
+func (b *YubinCustomer) DrawRectImage(code string, width, height int) (image.Image, error) {
+	return b.DrawImageWithWidth(code, width, height)
+}
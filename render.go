@@ -0,0 +1,332 @@
+package barcode_pao
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ─── Pure-Go rendering ─────────────────────────────────────────────────────
+//
+// Encoders produce a logical symbol; layout turns it into a drawing made of
+// filled rectangles and text labels, which is then rasterised (PNG/JPEG) or
+// serialised (SVG). Both outputs come from the same drawing, so they agree
+// on geometry.
+
+// drawing is a resolution-independent picture of a barcode.
+type drawing struct {
+	width, height int
+	fg, bg        color.RGBA
+	rects         []rect
+	labels        []label
+}
+
+type rect struct {
+	x, y, w, h float64
+}
+
+// label is a line of human-readable text.
+type label struct {
+	text string
+	// x, y, w describe the text box: its left edge, top edge and width.
+	// Text is centred in the box, or spread across it when even is set.
+	x, y, w float64
+	// size is the glyph height in pixels.
+	size float64
+	even bool
+}
+
+// glyph metrics of the built-in bitmap font, in font pixels.
+const (
+	glyphW   = 5
+	glyphH   = 7
+	glyphAdv = glyphW + 1
+)
+
+// centers returns the horizontal centre of every character of the label.
+func (l *label) centers() []float64 {
+	runes := []rune(l.text)
+	n := float64(len(runes))
+	out := make([]float64, len(runes))
+	if l.even {
+		cell := l.w / n
+		for i := range out {
+			out[i] = l.x + (float64(i)+0.5)*cell
+		}
+		return out
+	}
+	p := l.size / glyphH
+	start := l.x + (l.w-(n*glyphAdv-1)*p)/2
+	for i := range out {
+		out[i] = start + (float64(i)*glyphAdv+glyphW/2.0)*p
+	}
+	return out
+}
+
+// normalizeFormat maps the accepted spellings of an output format to one of
+// the Format* constants.
+func normalizeFormat(format string) string {
+	switch strings.ToLower(format) {
+	case "png":
+		return FormatPNG
+	case "jpg", "jpeg":
+		return FormatJPEG
+	case "svg":
+		return FormatSVG
+	}
+	return ""
+}
+
+// encode renders the drawing in the given output format and returns it the
+// way Draw does: Base64 for raster formats, markup for SVG.
+func (d *drawing) encode(format string) (string, error) {
+	data, err := d.encodeBytes(format)
+	if err != nil {
+		return "", err
+	}
+	if normalizeFormat(format) == FormatSVG {
+		return string(data), nil
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// encodeBytes renders the drawing as raw PNG/JPEG data or SVG markup.
+func (d *drawing) encodeBytes(format string) ([]byte, error) {
+	switch normalizeFormat(format) {
+	case FormatSVG:
+		return []byte(d.svg()), nil
+	case FormatPNG:
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, d.raster()); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatJPEG:
+		// JPEG has no alpha channel: flatten onto white.
+		img := image.NewRGBA(image.Rect(0, 0, d.width, d.height))
+		draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+		draw.Draw(img, img.Bounds(), d.raster(), image.Point{}, draw.Over)
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unsupported output format %q", format)
+}
+
+// raster renders the drawing into a two-colour paletted image: index 0 is
+// the background, index 1 the foreground.
+func (d *drawing) raster() *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, d.width, d.height), color.Palette{d.bg, d.fg})
+	fill := func(x, y, w, h float64) {
+		r := image.Rect(
+			int(math.Round(x)), int(math.Round(y)),
+			int(math.Round(x+w)), int(math.Round(y+h)),
+		).Intersect(img.Bounds())
+		for py := r.Min.Y; py < r.Max.Y; py++ {
+			row := img.Pix[img.PixOffset(r.Min.X, py):img.PixOffset(r.Max.X, py)]
+			for i := range row {
+				row[i] = 1
+			}
+		}
+	}
+	for _, r := range d.rects {
+		fill(r.x, r.y, r.w, r.h)
+	}
+	for i := range d.labels {
+		l := &d.labels[i]
+		p := l.size / glyphH
+		runes := []rune(l.text)
+		for j, cx := range l.centers() {
+			g := glyphFor(runes[j])
+			left := cx - glyphW*p/2
+			for col := 0; col < glyphW; col++ {
+				for row := 0; row < glyphH; row++ {
+					if g[col]&(1<<row) != 0 {
+						fill(left+float64(col)*p, l.y+float64(row)*p, p, p)
+					}
+				}
+			}
+		}
+	}
+	return img
+}
+
+// svg serialises the drawing as an SVG document.
+func (d *drawing) svg() string {
+	var sb strings.Builder
+	w, h := strconv.Itoa(d.width), strconv.Itoa(d.height)
+	sb.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" version="1.1" width="` + w + `" height="` + h + `" viewBox="0 0 ` + w + ` ` + h + `">` + "\n")
+	sb.WriteString(`<rect x="0" y="0" width="` + w + `" height="` + h + `"` + svgFill(d.bg) + "/>\n")
+	fill := svgFill(d.fg)
+	if len(d.rects) > 0 {
+		sb.WriteString("<g" + fill + ">\n")
+		for _, r := range d.rects {
+			sb.WriteString(`<rect x="` + svgNum(r.x) + `" y="` + svgNum(r.y) + `" width="` + svgNum(r.w) + `" height="` + svgNum(r.h) + `"/>` + "\n")
+		}
+		sb.WriteString("</g>\n")
+	}
+	for i := range d.labels {
+		l := &d.labels[i]
+		// The bitmap font's cap height is 7/10 of its em size.
+		fontSize := svgNum(l.size / 0.7)
+		baseline := svgNum(l.y + l.size)
+		attrs := ` font-family="monospace" font-size="` + fontSize + `" text-anchor="middle"` + fill
+		if l.even {
+			runes := []rune(l.text)
+			for j, cx := range l.centers() {
+				sb.WriteString(`<text x="` + svgNum(cx) + `" y="` + baseline + `"` + attrs + `>` + svgEscape(string(runes[j])) + "</text>\n")
+			}
+			continue
+		}
+		sb.WriteString(`<text x="` + svgNum(l.x+l.w/2) + `" y="` + baseline + `"` + attrs + `>` + svgEscape(l.text) + "</text>\n")
+	}
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+func svgFill(c color.RGBA) string {
+	s := fmt.Sprintf(` fill="#%02x%02x%02x"`, c.R, c.G, c.B)
+	if c.A != 255 {
+		s += ` fill-opacity="` + svgNum(float64(c.A)/255) + `"`
+	}
+	return s
+}
+
+func svgNum(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
+
+func svgEscape(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch r {
+		case '&':
+			sb.WriteString("&amp;")
+		case '<':
+			sb.WriteString("&lt;")
+		case '>':
+			sb.WriteString("&gt;")
+		case '"':
+			sb.WriteString("&quot;")
+		default:
+			if r < 0x20 {
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// layoutLinear places a 1D symbol in a width × height box.
+func layoutLinear(sym *Symbol, width, height int, o *settings) (*drawing, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errDraw(ReasonSizeTooSmall, "invalid size %dx%d", width, height)
+	}
+	modules := sym.Width()
+	total := modules + 2*sym.QuietZone
+
+	// Module width: whole pixels keep edges crisp unless the caller asked
+	// for the symbol to span the full width.
+	mw := float64(width) / float64(total)
+	if !o.fitWidth && mw >= 1 {
+		mw = math.Floor(mw)
+	}
+	x0 := (float64(width) - mw*float64(total)) / 2
+	left := x0 + float64(sym.QuietZone)*mw
+	symW := float64(modules) * mw
+
+	d := &drawing{width: width, height: height, fg: o.fg, bg: o.bg}
+	barH := float64(height)
+	if o.showText && sym.Text != "" {
+		size, gap := textSize(sym.Text, width, height, o)
+		barH = float64(height) - size - gap
+		if barH < 1 {
+			return nil, errDraw(ReasonSizeTooSmall, "height %d is too small to fit bars and text", height)
+		}
+		d.labels = append(d.labels, label{text: sym.Text, x: left, y: barH + gap, w: symW, size: size, even: o.textEvenSpacing})
+	}
+
+	adj := float64(o.pxAdjustBlack - o.pxAdjustWhite)
+	x := left
+	for i, e := range sym.Bars {
+		w := float64(e) * mw
+		if i%2 == 0 && w+adj > 0 {
+			d.rects = append(d.rects, rect{x: x - adj/2, y: 0, w: w + adj, h: barH})
+		}
+		x += w
+	}
+	return d, nil
+}
+
+// textSize returns the glyph height of the human-readable text under a
+// linear symbol, and the gap above it.
+func textSize(text string, width, height int, o *settings) (size, gap float64) {
+	size = math.Max(glyphH, math.Round(float64(height)*0.15*o.textFontScale))
+	n := float64(len([]rune(text)))
+	if maxSize := float64(width) * glyphH / (n*glyphAdv - 1); size > maxSize {
+		size = maxSize
+	}
+	return size, o.textGap * size / 3
+}
+
+// layoutMatrix places a 2D symbol in a width × height box.
+func layoutMatrix(sym *Symbol, width, height int, o *settings) (*drawing, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errDraw(ReasonSizeTooSmall, "invalid size %dx%d", width, height)
+	}
+	rows := sym.Height()
+	cols := sym.Width()
+	mw := float64(width) / float64(cols+2*sym.QuietZone)
+	mh := float64(height) / float64(rows+2*sym.QuietZone)
+	m := math.Min(mw, mh)
+	if m < 1 {
+		return nil, errDraw(ReasonSizeTooSmall, "size %dx%d is smaller than the %dx%d modules of the symbol and its quiet zone",
+			width, height, cols+2*sym.QuietZone, rows+2*sym.QuietZone)
+	}
+	if !o.fitWidth {
+		m = math.Floor(m)
+	}
+	rowHeight := func(r int) float64 {
+		if sym.RowHeights != nil {
+			return float64(sym.RowHeights[r]) * m
+		}
+		return m
+	}
+	// Rows can be several modules high; centre on their total height.
+	symH := 0.0
+	for r := range sym.Modules {
+		symH += rowHeight(r)
+	}
+	x0 := (float64(width) - m*float64(cols)) / 2
+	y := (float64(height) - symH) / 2
+
+	d := &drawing{width: width, height: height, fg: o.fg, bg: o.bg}
+	for r, row := range sym.Modules {
+		h := rowHeight(r)
+		// Merge horizontal runs to keep the SVG small.
+		for x := 0; x < cols; {
+			if !row[x] {
+				x++
+				continue
+			}
+			start := x
+			for x < cols && row[x] {
+				x++
+			}
+			d.rects = append(d.rects, rect{x: x0 + float64(start)*m, y: y, w: float64(x-start) * m, h: h})
+		}
+		y += h
+	}
+	return d, nil
+}
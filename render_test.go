@@ -0,0 +1,280 @@
+package barcode_pao
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"reflect"
+	"testing"
+)
+
+// decodePNG renders d as PNG and decodes it again.
+func decodePNG(t *testing.T, d *drawing) image.Image {
+	t.Helper()
+	data, err := d.encodeBytes(FormatPNG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != d.width || b.Dy() != d.height {
+		t.Fatalf("PNG is %v, want %dx%d", b, d.width, d.height)
+	}
+	return img
+}
+
+func isDark(img image.Image, x, y int) bool {
+	r, g, b, _ := img.At(x, y).RGBA()
+	return r+g+b < 3*0x8000
+}
+
+// runs returns the lengths of the alternating light and dark runs of row y,
+// starting with light.
+func runs(img image.Image, y int) []int {
+	b := img.Bounds()
+	out := []int{0}
+	dark := false
+	for x := b.Min.X; x < b.Max.X; x++ {
+		if isDark(img, x, y) != dark {
+			dark = !dark
+			out = append(out, 0)
+		}
+		out[len(out)-1]++
+	}
+	return out
+}
+
+// svgDoc is the part of the SVG output the tests look at.
+type svgDoc struct {
+	Width  int       `xml:"width,attr"`
+	Height int       `xml:"height,attr"`
+	Rects  []svgRect `xml:"g>rect"`
+	Texts  []string  `xml:"text"`
+}
+
+type svgRect struct {
+	X      float64 `xml:"x,attr"`
+	Y      float64 `xml:"y,attr"`
+	Width  float64 `xml:"width,attr"`
+	Height float64 `xml:"height,attr"`
+}
+
+func decodeSVG(t *testing.T, d *drawing) svgDoc {
+	t.Helper()
+	data, err := d.encodeBytes(FormatSVG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc svgDoc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("%v\n%s", err, data)
+	}
+	if doc.Width != d.width || doc.Height != d.height {
+		t.Fatalf("SVG is %dx%d, want %dx%d", doc.Width, doc.Height, d.width, d.height)
+	}
+	return doc
+}
+
+func TestRenderLinear(t *testing.T) {
+	sym, err := encodeCode128("HI345678", testSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	const scale = 3
+	total := sym.Width() + 2*sym.QuietZone
+	o := testSettings()
+	o.showText = false
+	d, err := layoutLinear(sym, total*scale, 40, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every scanline shows the quiet zones and the bars at three pixels
+	// per module.
+	want := []int{sym.QuietZone * scale}
+	for _, w := range sym.Bars {
+		want = append(want, w*scale)
+	}
+	want = append(want, sym.QuietZone*scale)
+	img := decodePNG(t, d)
+	for _, y := range []int{0, 20, 39} {
+		if got := runs(img, y); !reflect.DeepEqual(got, want) {
+			t.Errorf("row %d: runs %v\nwant %v", y, got, want)
+		}
+	}
+
+	doc := decodeSVG(t, d)
+	if len(doc.Rects) != (len(sym.Bars)+1)/2 {
+		t.Fatalf("SVG has %d bars, want %d", len(doc.Rects), (len(sym.Bars)+1)/2)
+	}
+	x := sym.QuietZone * scale
+	for i, w := range sym.Bars {
+		if i%2 == 0 {
+			r := doc.Rects[i/2]
+			if r.X != float64(x) || r.Width != float64(w*scale) || r.Y != 0 || r.Height != 40 {
+				t.Errorf("bar %d: %+v, want x %d width %d", i/2, r, x, w*scale)
+			}
+		}
+		x += w * scale
+	}
+
+	// JPEG output has the same size.
+	data, err := d.encodeBytes(FormatJPEG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err := jpeg.DecodeConfig(bytes.NewReader(data)); err != nil || cfg.Width != d.width || cfg.Height != d.height {
+		t.Errorf("JPEG %+v, %v", cfg, err)
+	}
+}
+
+func TestRenderLinearText(t *testing.T) {
+	sym, err := encodeCode128("HI345678", testSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := layoutLinear(sym, 300, 80, testSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc := decodeSVG(t, d); len(doc.Texts) != 1 || doc.Texts[0] != "HI345678" {
+		t.Errorf("SVG text %q", doc.Texts)
+	}
+	barH := d.rects[0].h
+	img := decodePNG(t, d)
+	text := false
+	for y := int(barH) + 1; y < 80 && !text; y++ {
+		for x := 0; x < 300; x++ {
+			if isDark(img, x, y) {
+				text = true
+				break
+			}
+		}
+	}
+	if barH >= 80 || !text {
+		t.Errorf("bars %v high, text drawn %v", barH, text)
+	}
+	if _, err := layoutLinear(sym, 300, 5, testSettings()); err == nil {
+		t.Error("bars and text fit into 5 pixels")
+	}
+}
+
+func TestRenderMatrix(t *testing.T) {
+	sym, err := encodeQR("https://example.com/", testSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	const scale = 5
+	n := sym.Width()
+	size := (n + 2*sym.QuietZone) * scale
+	d, err := layoutMatrix(sym, size, size, testSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The module centres of the PNG reproduce the grid, and the quiet zone
+	// is light.
+	img := decodePNG(t, d)
+	q := sym.QuietZone
+	for y := -q; y < n+q; y++ {
+		for x := -q; x < n+q; x++ {
+			want := x >= 0 && y >= 0 && x < n && y < n && sym.Modules[y][x]
+			px, py := (x+q)*scale+scale/2, (y+q)*scale+scale/2
+			if isDark(img, px, py) != want {
+				t.Fatalf("module (%d,%d) at pixel (%d,%d): dark %v", x, y, px, py, !want)
+			}
+		}
+	}
+
+	// The SVG rectangles cover exactly the dark modules.
+	cover := make([][]int, n)
+	for i := range cover {
+		cover[i] = make([]int, n)
+	}
+	for _, r := range decodeSVG(t, d).Rects {
+		if r.Height != scale || int(r.Width)%scale != 0 {
+			t.Fatalf("rect %+v is not whole modules", r)
+		}
+		y := int(r.Y)/scale - q
+		for x := int(r.X)/scale - q; x < int(r.X+r.Width)/scale-q; x++ {
+			cover[y][x]++
+		}
+	}
+	for y, row := range sym.Modules {
+		for x, dark := range row {
+			if want := map[bool]int{false: 0, true: 1}[dark]; cover[y][x] != want {
+				t.Fatalf("module (%d,%d) covered %d times, want %d", x, y, cover[y][x], want)
+			}
+		}
+	}
+}
+
+func TestRenderColors(t *testing.T) {
+	sym, err := encodeQR("A", testSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := testSettings()
+	o.fg = rgba(200, 0, 0, 255)
+	o.bg = rgba(0, 0, 255, 255)
+	d, err := layoutMatrix(sym, 100, 100, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := decodePNG(t, d)
+	if c := color.RGBAModel.Convert(img.At(0, 0)); c != o.bg {
+		t.Errorf("background %v, want %v", c, o.bg)
+	}
+	if c := color.RGBAModel.Convert(img.At(50, 50)); c != o.fg && c != o.bg {
+		t.Errorf("pixel %v is neither colour", c)
+	}
+	data, _ := d.encodeBytes(FormatSVG)
+	for _, s := range []string{`<g fill="#c80000">`, `fill="#0000ff"/>`} {
+		if !bytes.Contains(data, []byte(s)) {
+			t.Errorf("SVG lacks %s", s)
+		}
+	}
+	if got := svgFill(rgba(0, 0, 255, 128)); got != ` fill="#0000ff" fill-opacity="0.502"` {
+		t.Errorf("svgFill with alpha = %s", got)
+	}
+}
+
+func TestRenderMatrixTooSmall(t *testing.T) {
+	sym, err := encodeQR("A", testSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Version 1 is 21 modules wide plus a quiet zone of 4 on each side.
+	if _, err := layoutMatrix(sym, 29, 29, testSettings()); err != nil {
+		t.Errorf("29 pixels: %v", err)
+	}
+	for _, size := range [][2]int{{28, 28}, {28, 100}, {100, 28}, {0, 0}} {
+		_, err := layoutMatrix(sym, size[0], size[1], testSettings())
+		var de *DrawError
+		if !errors.As(err, &de) || de.Reason != ReasonSizeTooSmall {
+			t.Errorf("%dx%d: %v, want ReasonSizeTooSmall", size[0], size[1], err)
+		}
+	}
+}
+
+func TestRenderRowHeights(t *testing.T) {
+	sym := &Symbol{
+		Modules:    [][]bool{{true, true, true, true}, {true, false, true, false}},
+		RowHeights: []int{3, 1},
+	}
+	// 2 pixels per module; the 4 module high symbol is centred vertically.
+	d, err := layoutMatrix(sym, 8, 12, testSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []rect{{0, 2, 8, 6}, {0, 8, 2, 2}, {4, 8, 2, 2}}
+	if !reflect.DeepEqual(d.rects, want) {
+		t.Errorf("rects %v, want %v", d.rects, want)
+	}
+}
@@ -0,0 +1,179 @@
+package barcode_pao
+
+import "fmt"
+
+// ─── Logical symbols ───────────────────────────────────────────────────────
+
+// Symbol is the logical form of a barcode: what the encoder produces before
+// anything is rendered. All dimensions are in modules (the narrowest
+// element of the symbology), so a Symbol can be drawn at any resolution or
+// measured against physical size requirements.
+//
+// A linear symbol has Bars set; a 2D or multi-row symbol (stacked GS1
+// DataBar, the 4-state postal code) has Modules set.
+type Symbol struct {
+	// Bars holds the element widths of a linear symbol, alternating bar
+	// and space and starting with a bar. A leading 0 means the symbol
+	// starts with a space (GS1 DataBar).
+	Bars []int
+	// Modules is the module grid, row by row; true is dark.
+	Modules [][]bool
+	// RowHeights gives the height of each row of Modules in modules. It is
+	// nil when every row is one module high.
+	RowHeights []int
+	// QuietZone is the minimum light margin on each side, in modules.
+	QuietZone int
+	// Text is the human-readable interpretation, if the symbology has one.
+	Text string
+	// AddOn is the text of an EAN-2 or EAN-5 add-on. Its bars end Bars,
+	// after a light gap.
+	AddOn string
+	// Header is a line printed above the bars, such as the hyphenated ISBN
+	// of a book's JAN-13.
+	Header string
+}
+
+// IsLinear reports whether the symbol is a single row of bars.
+func (s *Symbol) IsLinear() bool { return s.Modules == nil }
+
+// Width returns the symbol width in modules, excluding quiet zones.
+func (s *Symbol) Width() int {
+	if s.IsLinear() {
+		n := 0
+		for _, w := range s.Bars {
+			n += w
+		}
+		return n
+	}
+	if len(s.Modules) == 0 {
+		return 0
+	}
+	return len(s.Modules[0])
+}
+
+// Height returns the symbol height in modules, excluding quiet zones. It
+// is 0 for linear symbols, whose bar height is not part of the encoding.
+func (s *Symbol) Height() int {
+	if s.IsLinear() {
+		return 0
+	}
+	if s.RowHeights == nil {
+		return len(s.Modules)
+	}
+	n := 0
+	for _, h := range s.RowHeights {
+		n += h
+	}
+	return n
+}
+
+// encoders maps type IDs to pure-Go encoders. Every symbology but NEC 2 of
+// 5, whose bar patterns are not published, has one; only those in
+// pureGoTypes are also drawn without the native library.
+var encoders = map[int]func(code string, opts *settings) (*Symbol, error){
+	typeCode39:             encodeCode39,
+	typeCode93:             encodeCode93,
+	typeCode128:            encodeCode128,
+	typeGS1128:             encodeGS1128,
+	typeNW7:                encodeNW7,
+	typeMatrix2of5:         encodeMatrix2of5,
+	typeJan8:               encodeJan8,
+	typeJan13:              encodeJan13,
+	typeUPCA:               encodeUPCA,
+	typeUPCE:               encodeUPCE,
+	typeITF:                encodeITF,
+	typeGS1DataBar14:       encodeDataBar14,
+	typeGS1DataBarLimited:  encodeDataBarLimited,
+	typeGS1DataBarExpanded: encodeDataBarExpanded,
+	typeYubinCustomer:      encodeYubin,
+	typeQR:                 encodeQR,
+	typeDataMatrix:         encodeDataMatrix,
+	typePDF417:             encodePDF417,
+	typeITF14:              encodeITF14,
+	typeCode32:             encodeCode32,
+	typeHIBC:               encodeHIBC,
+	typeCodabar:            encodeCodabar,
+	typeCode11:             encodeCode11,
+	typeMSI:                encodeMSI,
+	typePharma:             encodePharmacode,
+	typeTelepen:            encodeTelepen,
+}
+
+// Encode returns the logical symbol for code under the current settings,
+// without rendering it. It always runs the pure-Go encoder, whichever
+// backend Draw uses. Invalid input is reported as a *DrawError.
+func (b *BarcodeBase) Encode(code string) (*Symbol, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+	return b.encode(code)
+}
+
+// encode is Encode for callers that already hold the lock.
+func (b *BarcodeBase) encode(code string) (*Symbol, error) {
+	enc, ok := encoders[b.typeID]
+	if !ok {
+		return nil, fmt.Errorf("no encoder for barcode type %d", b.typeID)
+	}
+	jan, header := code, ""
+	if b.ident != nil {
+		var err error
+		if jan, header, err = b.ident.toJAN(code, &b.opts); err != nil {
+			return nil, b.drawError(code, err)
+		}
+	}
+	jan, err := applyCheckDigitPolicy(b.typeID, jan, &b.opts)
+	if err != nil {
+		return nil, b.drawError(code, err)
+	}
+	sym, err := enc(jan, &b.opts)
+	if err != nil {
+		return nil, b.drawError(code, err)
+	}
+	sym.Header = header
+	return sym, nil
+}
+
+// appendWidths appends a pattern of element widths written as digits.
+func appendWidths(bars []int, pattern string) []int {
+	for _, c := range pattern {
+		bars = append(bars, int(c-'0'))
+	}
+	return bars
+}
+
+// appendWideNarrow appends n elements of a wide/narrow pattern, most
+// significant bit first; set bits are wide.
+func appendWideNarrow(bars []int, pattern, n, wide int) []int {
+	for i := n - 1; i >= 0; i-- {
+		if pattern&(1<<i) != 0 {
+			bars = append(bars, wide)
+		} else {
+			bars = append(bars, 1)
+		}
+	}
+	return bars
+}
+
+// newGrid allocates a rows × cols module grid.
+func newGrid(rows, cols int) [][]bool {
+	cells := make([]bool, rows*cols)
+	grid := make([][]bool, rows)
+	for i := range grid {
+		grid[i] = cells[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+	return grid
+}
+
+// widthsToRow expands element widths, starting with a bar, into modules.
+func widthsToRow(widths []int) []bool {
+	var row []bool
+	for i, w := range widths {
+		for j := 0; j < w; j++ {
+			row = append(row, i%2 == 0)
+		}
+	}
+	return row
+}
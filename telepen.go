@@ -0,0 +1,114 @@
+package barcode_pao
+
+import "math/bits"
+
+// ─── Telepen ───────────────────────────────────────────────────────────────
+//
+// Telepen encodes full ASCII, or digit pairs in numeric mode, between the
+// start character '_' and the stop character 'z', with a modulo-127 check
+// character before the stop.
+
+const (
+	telepenStart = '_'
+	telepenStop  = 'z'
+)
+
+// validateTelepen accepts ASCII, or in numeric mode digits with an 'X'
+// allowed as the second digit of a pair. An odd number of digits is padded
+// with a leading zero.
+func validateTelepen(code string, opts *settings) error {
+	if opts == nil || !opts.telepenNumeric {
+		return validateASCII(code, opts)
+	}
+	if err := validateCharset(digitChars+"X", "only digits and 'X' are allowed")(code, opts); err != nil {
+		return err
+	}
+	for i := len(code) % 2; i < len(code); i += 2 {
+		if code[i] == 'X' {
+			return errInvalidChar(i, 'X', "'X' may only be the second digit of a pair")
+		}
+	}
+	return nil
+}
+
+// telepenValues returns the character values of code: its ASCII codes,
+// or in numeric mode 27 plus each digit pair and 17 plus a digit paired
+// with 'X'.
+func telepenValues(code string, numeric bool) []byte {
+	if !numeric {
+		return []byte(code)
+	}
+	if len(code)%2 == 1 {
+		code = "0" + code
+	}
+	vals := make([]byte, 0, len(code)/2)
+	for i := 0; i < len(code); i += 2 {
+		hi := code[i] - '0'
+		if code[i+1] == 'X' {
+			vals = append(vals, 17+hi)
+		} else {
+			vals = append(vals, 27+hi*10+code[i+1]-'0')
+		}
+	}
+	return vals
+}
+
+// appendTelepen appends the 16 modules of character value v. The seven
+// bits and an even parity bit are read least significant first; the
+// parity pairs up the zeros. A 1 is a narrow bar and narrow space, 00 a
+// wide bar and narrow space, 010 a wide bar and wide space, and 0 followed
+// by n > 1 ones and 0 a narrow bar and wide space, n-2 narrow pairs, and a
+// narrow bar and wide space.
+func appendTelepen(bars []int, v byte) []int {
+	if bits.OnesCount8(v)%2 == 1 {
+		v |= 0x80
+	}
+	for i := 0; i < 8; {
+		if v>>i&1 == 1 {
+			bars = append(bars, 1, 1)
+			i++
+			continue
+		}
+		j := i + 1
+		for v>>j&1 == 1 {
+			j++
+		}
+		switch ones := j - i - 1; ones {
+		case 0:
+			bars = append(bars, 3, 1)
+		case 1:
+			bars = append(bars, 3, 3)
+		default:
+			bars = append(bars, 1, 3)
+			for k := 2; k < ones; k++ {
+				bars = append(bars, 1, 1)
+			}
+			bars = append(bars, 1, 3)
+		}
+		i = j + 1
+	}
+	return bars
+}
+
+func encodeTelepen(code string, opts *settings) (*Symbol, error) {
+	if err := validators[typeTelepen](code, opts); err != nil {
+		return nil, err
+	}
+	vals := telepenValues(code, opts.telepenNumeric)
+	sum := 0
+	for _, v := range vals {
+		sum += int(v)
+	}
+	bars := appendTelepen(nil, telepenStart)
+	for _, v := range vals {
+		bars = appendTelepen(bars, v)
+	}
+	bars = appendTelepen(bars, byte((127-sum%127)%127))
+	bars = appendTelepen(bars, telepenStop)
+	text := printableText([]rune(code))
+	if opts.telepenNumeric && len(code)%2 == 1 {
+		text = "0" + text
+	}
+	// The stop character ends with a space, which the quiet zone covers.
+	return &Symbol{Bars: bars[:len(bars)-1], QuietZone: 10, Text: text}, nil
+}
@@ -0,0 +1,106 @@
+package barcode_pao
+
+import (
+	"fmt"
+	"math/bits"
+	"reflect"
+	"testing"
+)
+
+// readTelepen decodes Telepen elements back to character values, checking
+// the parity of each.
+func readTelepen(elems []int) ([]byte, error) {
+	if len(elems)%2 == 1 {
+		elems = append(elems, 1) // the trailing space of the stop character
+	}
+	var stream []int
+	inside := false // between the two narrow bar, wide space pairs of 0 1…1 0
+	for i := 0; i < len(elems); i += 2 {
+		switch b, s := elems[i], elems[i+1]; {
+		case b == 1 && s == 1:
+			stream = append(stream, 1)
+		case b == 1 && s == 3 && inside:
+			stream = append(stream, 1, 0)
+			inside = false
+		case b == 1 && s == 3:
+			stream = append(stream, 0, 1)
+			inside = true
+		case b == 3 && s == 1 && !inside:
+			stream = append(stream, 0, 0)
+		case b == 3 && s == 3 && !inside:
+			stream = append(stream, 0, 1, 0)
+		default:
+			return nil, fmt.Errorf("element pair %d is %d,%d", i/2, b, s)
+		}
+	}
+	if len(stream)%8 != 0 {
+		return nil, fmt.Errorf("%d bits", len(stream))
+	}
+	var vals []byte
+	for i := 0; i < len(stream); i += 8 {
+		var v byte
+		for j := 0; j < 8; j++ {
+			v |= byte(stream[i+j]) << j
+		}
+		if bits.OnesCount8(v)%2 != 0 {
+			return nil, fmt.Errorf("character %d (%#x) has odd parity", i/8, v)
+		}
+		vals = append(vals, v&0x7f)
+	}
+	return vals, nil
+}
+
+func TestTelepen(t *testing.T) {
+	tests := []struct {
+		code    string
+		numeric bool
+		vals    []byte // between start and stop, with the check character
+		text    string
+	}{
+		{"ABC", false, []byte{65, 66, 67, 56}, "ABC"},
+		{"a\tz", false, []byte{97, 9, 122, 26}, "az"},
+		// A sum that is a multiple of 127 has check character 0.
+		{"~\x01", false, []byte{126, 1, 0}, "~"},
+		{"1234", true, []byte{39, 61, 27}, "1234"},
+		// An odd number of digits gets a leading zero.
+		{"123", true, []byte{28, 50, 49}, "0123"},
+		{"12X", true, []byte{28, 19, 80}, "012X"},
+	}
+	for _, tt := range tests {
+		o := testSettings()
+		o.telepenNumeric = tt.numeric
+		sym, err := encodeTelepen(tt.code, o)
+		if err != nil {
+			t.Errorf("%q: %v", tt.code, err)
+			continue
+		}
+		got, err := readTelepen(sym.Bars)
+		if err != nil {
+			t.Errorf("%q: %v", tt.code, err)
+			continue
+		}
+		want := append(append([]byte{'_'}, tt.vals...), 'z')
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: values %v, want %v", tt.code, got, want)
+		}
+		if w := sym.Width() + 1; w != 16*len(want) {
+			t.Errorf("%q: %d modules, want %d", tt.code, w, 16*len(want))
+		}
+		if sym.Text != tt.text {
+			t.Errorf("%q: Text %q, want %q", tt.code, sym.Text, tt.text)
+		}
+	}
+
+	sym, _ := encodeTelepen("A", testSettings())
+	if start := sym.Bars[:12]; !reflect.DeepEqual(start, []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 3, 3}) {
+		t.Errorf("start character %v", start)
+	}
+
+	o := testSettings()
+	o.telepenNumeric = true
+	for _, code := range []string{"X123", "1X2", "12A4"} {
+		if _, err := encodeTelepen(code, o); err == nil {
+			t.Errorf("%s accepted in numeric mode", code)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package barcode_pao
+
+import "math"
+
+// ─── 2 of 5 family: ITF, ITF-14, Matrix 2 of 5, NEC 2 of 5 ─────────────────
+
+// itfPatterns are the wide/narrow patterns of the digits: 5 elements, most
+// significant bit first, set bits wide.
+var itfPatterns = [10]int{0x06, 0x11, 0x09, 0x18, 0x05, 0x14, 0x0C, 0x03, 0x12, 0x0A}
+
+const (
+	itfWide  = 3
+	itfStart = "1111"
+	itfStop  = "311"
+)
+
+func encodeITF(code string, opts *settings) (*Symbol, error) {
+	if err := validators[typeITF](code, opts); err != nil {
+		return nil, err
+	}
+	if len(code)%2 != 0 {
+		code = "0" + code
+	}
+	bars := appendWidths(nil, itfStart)
+	for i := 0; i < len(code); i += 2 {
+		// The first digit of a pair is carried by the bars, the second by
+		// the spaces.
+		b, s := itfPatterns[code[i]-'0'], itfPatterns[code[i+1]-'0']
+		for bit := 4; bit >= 0; bit-- {
+			bars = append(bars, itfWidth(b, bit), itfWidth(s, bit))
+		}
+	}
+	bars = appendWidths(bars, itfStop)
+	return &Symbol{Bars: bars, QuietZone: 10, Text: code}, nil
+}
+
+// encodeITF14 encodes the 14-digit GS1 key of a trade item grouping, with
+// its check digit appended if only 13 digits are given.
+func encodeITF14(code string, opts *settings) (*Symbol, error) {
+	code, err := withCheckDigit(code, 13, opts)
+	if err != nil {
+		return nil, err
+	}
+	return encodeITF(code, opts)
+}
+
+// layoutITF14 places an ITF-14 symbol in a width × height box, with the
+// bearer bars set in opts drawn bearerWidth modules thick: a frame around
+// the symbol and its quiet zones, or bars along their top and bottom. The
+// text goes below the bearer bars.
+func layoutITF14(sym *Symbol, width, height int, o *settings) (*drawing, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errDraw(ReasonSizeTooSmall, "invalid size %dx%d", width, height)
+	}
+	bw, side := o.bearerWidth, 0
+	switch o.bearerStyle {
+	case BearerNone:
+		bw = 0
+	case BearerFrame:
+		side = bw
+	}
+	total := sym.Width() + 2*(sym.QuietZone+side)
+	mw := float64(width) / float64(total)
+	if !o.fitWidth && mw >= 1 {
+		mw = math.Floor(mw)
+	}
+	x0 := (float64(width) - mw*float64(total)) / 2
+	outerW := mw * float64(total)
+	left := x0 + float64(side+sym.QuietZone)*mw
+	bearer := float64(bw) * mw
+
+	d := &drawing{width: width, height: height, fg: o.fg, bg: o.bg}
+	bottom := float64(height)
+	if o.showText && sym.Text != "" {
+		size, gap := textSize(sym.Text, width, height, o)
+		bottom -= size + gap
+		d.labels = append(d.labels, label{text: sym.Text, x: left, y: bottom + gap, w: float64(sym.Width()) * mw, size: size, even: o.textEvenSpacing})
+	}
+	top, barH := bearer, bottom-2*bearer
+	if barH < 1 {
+		return nil, errDraw(ReasonSizeTooSmall, "height %d is too small to fit bars, bearer bars and text", height)
+	}
+	if bw > 0 {
+		d.rects = append(d.rects, rect{x: x0, y: 0, w: outerW, h: bearer}, rect{x: x0, y: bottom - bearer, w: outerW, h: bearer})
+		if side > 0 {
+			d.rects = append(d.rects, rect{x: x0, y: top, w: bearer, h: barH}, rect{x: x0 + outerW - bearer, y: top, w: bearer, h: barH})
+		}
+	}
+
+	adj := float64(o.pxAdjustBlack - o.pxAdjustWhite)
+	x := left
+	for i, e := range sym.Bars {
+		w := float64(e) * mw
+		if i%2 == 0 && w+adj > 0 {
+			d.rects = append(d.rects, rect{x: x - adj/2, y: top, w: w + adj, h: barH})
+		}
+		x += w
+	}
+	return d, nil
+}
+
+func itfWidth(pattern, bit int) int {
+	if pattern&(1<<bit) != 0 {
+		return itfWide
+	}
+	return 1
+}
+
+// Matrix 2 of 5 carries each digit in three bars and two spaces, two of
+// them wide, in the patterns of itfPatterns, followed by a narrow space.
+// The start and stop characters open with a bar four modules wide.
+const (
+	matrix2of5Start = "411111"
+	matrix2of5Stop  = "41111"
+)
+
+func encodeMatrix2of5(code string, opts *settings) (*Symbol, error) {
+	if err := validators[typeMatrix2of5](code, opts); err != nil {
+		return nil, err
+	}
+	bars := appendWidths(nil, matrix2of5Start)
+	for i := 0; i < len(code); i++ {
+		p := itfPatterns[code[i]-'0']
+		for bit := 4; bit >= 0; bit-- {
+			bars = append(bars, itfWidth(p, bit))
+		}
+		bars = append(bars, 1)
+	}
+	bars = appendWidths(bars, matrix2of5Stop)
+	return &Symbol{Bars: bars, QuietZone: 10, Text: code}, nil
+}
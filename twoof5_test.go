@@ -0,0 +1,61 @@
+package barcode_pao
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMatrix2of5Digits(t *testing.T) {
+	seen := make(map[string]byte)
+	for d := byte('0'); d <= '9'; d++ {
+		sym, err := encodeMatrix2of5(string(d), testSettings())
+		if err != nil {
+			t.Fatalf("%c: %v", d, err)
+		}
+		bars := sym.Bars
+		if len(bars) != 6+6+5 {
+			t.Fatalf("%c: %d elements, want 17", d, len(bars))
+		}
+		if got := fmt.Sprint(bars[:6]); got != "[4 1 1 1 1 1]" {
+			t.Errorf("%c: start %s, want [4 1 1 1 1 1]", d, got)
+		}
+		if got := fmt.Sprint(bars[12:]); got != "[4 1 1 1 1]" {
+			t.Errorf("%c: stop %s, want [4 1 1 1 1]", d, got)
+		}
+		char := bars[6:12]
+		wide := 0
+		for _, w := range char[:5] {
+			if w == itfWide {
+				wide++
+			}
+		}
+		if wide != 2 || char[5] != 1 {
+			t.Errorf("%c: character %v, want two wide of five elements and a narrow gap", d, char)
+		}
+		key := fmt.Sprint(char)
+		if other, ok := seen[key]; ok {
+			t.Errorf("%c and %c both encode as %s", other, d, key)
+		}
+		seen[key] = d
+	}
+}
+
+func TestMatrix2of5Golden(t *testing.T) {
+	sym, err := encodeMatrix2of5("14", testSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int{
+		4, 1, 1, 1, 1, 1, // start
+		3, 1, 1, 1, 3, 1, // 1
+		1, 1, 3, 1, 3, 1, // 4
+		4, 1, 1, 1, 1, // stop
+	}
+	if !reflect.DeepEqual(sym.Bars, want) {
+		t.Errorf("Bars = %v, want %v", sym.Bars, want)
+	}
+	if _, err := encodeMatrix2of5("12A", testSettings()); err == nil {
+		t.Error("non-digit accepted")
+	}
+}
@@ -0,0 +1,296 @@
+package barcode_pao
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ─── Input validation ──────────────────────────────────────────────────────
+//
+// validators check an input against the rules of its symbology. They are
+// used to explain native draw failures and return *DrawError values. They
+// check only what the engine rejects as well: character sets, including
+// those of a Code128 code set, lengths, check digits, and the capacity of
+// the QR version set with SetVersion or else the maximum capacity.
+
+var validators = map[int]func(code string, opts *settings) error{
+	typeCode39:       validateCode39,
+	typeCode32:       validateCode32,
+	typeHIBC:         validateHIBC,
+	typeCode93:       validateASCII,
+	typeCode128:      validateCode128,
+	typeGS1128:       validateASCII,
+	typeNW7:          validateNW7,
+	typeCodabar:      validateNW7,
+	typeCode11:       validateCharset(code11Chars, "only digits and '-' are allowed"),
+	typeMSI:          validateCharset(digitChars, "only digits are allowed"),
+	typePharma:       validatePharmacode,
+	typeTelepen:      validateTelepen,
+	typeMatrix2of5:   validateCharset(digitChars, "only digits are allowed"),
+	typeNEC2of5:      validateCharset(digitChars, "only digits are allowed"),
+	typeITF:          validateCharset(digitChars, "only digits are allowed"),
+	typeITF14:        validateGTIN(13),
+	typeJan8:         validateGTIN(7),
+	typeJan13:        validateGTIN(12),
+	typeUPCA:         validateGTIN(11),
+	typeUPCE:         validateUPCE,
+	typeGS1DataBar14: validateGTIN(13),
+	typeGS1DataBarLimited: func(code string, opts *settings) error {
+		if err := validateGTIN(13)(code, opts); err != nil {
+			return err
+		}
+		if code[0] > '1' {
+			return &DrawError{Reason: ReasonInvalidCharacter, Position: 0,
+				Detail: "GS1 DataBar Limited requires a leading indicator digit of 0 or 1"}
+		}
+		return nil
+	},
+	typeGS1DataBarExpanded: validateASCII,
+	typeYubinCustomer:      validateCharset(yubinChars, "not in the customer barcode character set"),
+	typeQR:                 validateQR,
+	typeDataMatrix:         validateCapacity(3116, 2335, 1556),
+	typePDF417:             validateCapacity(2710, 1850, 1108),
+}
+
+const (
+	digitChars  = "0123456789"
+	code39Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ-. $/+%"
+	nw7Chars    = "0123456789-$:/.+"
+	yubinChars  = "0123456789-ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
+func validateNonEmpty(code string) error {
+	if code == "" {
+		return errDraw(ReasonEmptyInput, "empty input")
+	}
+	return nil
+}
+
+func validateCharset(chars, detail string) func(string, *settings) error {
+	return func(code string, _ *settings) error {
+		if err := validateNonEmpty(code); err != nil {
+			return err
+		}
+		for i, r := range []rune(code) {
+			if !strings.ContainsRune(chars, r) {
+				return errInvalidChar(i, r, detail)
+			}
+		}
+		return nil
+	}
+}
+
+// validateCode39 accepts the Code39 character set, or ASCII in Full ASCII
+// mode.
+func validateCode39(code string, opts *settings) error {
+	if opts != nil && opts.fullASCII {
+		return validateASCII(code, opts)
+	}
+	return validateCharset(code39Chars, "not in the Code39 character set")(code, opts)
+}
+
+func validateASCII(code string, _ *settings) error {
+	if err := validateNonEmpty(code); err != nil {
+		return err
+	}
+	for i, r := range []rune(code) {
+		if r > 127 {
+			return errInvalidChar(i, r, "only ASCII is allowed")
+		}
+	}
+	return nil
+}
+
+// validateCode128 accepts ASCII, restricted to the code set of a fixed
+// code mode.
+func validateCode128(code string, opts *settings) error {
+	if err := validateASCII(code, opts); err != nil {
+		return err
+	}
+	if opts == nil || opts.codeMode == "" || strings.EqualFold(opts.codeMode, "AUTO") {
+		return nil
+	}
+	_, err := code128Values([]rune(code), opts.codeMode)
+	return err
+}
+
+// validateQR checks code against the capacity of the QR version set in
+// opts at its error correction level and encode mode. With the version
+// chosen automatically, only the maximum capacity is checked.
+func validateQR(code string, opts *settings) error {
+	if opts == nil || opts.qrVersion == 0 {
+		return validateCapacity(7089, 4296, 2953)(code, opts)
+	}
+	ver := opts.qrVersion
+	if ver < 1 || ver > 40 {
+		return errDraw(ReasonInvalidOption, "version %d out of range 0-40", ver)
+	}
+	ecl, err := qrECCIndex(opts.eccLevel)
+	if err != nil {
+		return err
+	}
+	segs, err := qrSegments(code, opts)
+	if err != nil {
+		return err
+	}
+	if n := qrSegmentsBits(segs, ver); n < 0 || n > qrDataCodewords(ver, ecl)*8 {
+		return errDraw(ReasonDataTooLong, "data too long for QR version %d", ver)
+	}
+	return nil
+}
+
+// validateNW7 accepts NW-7 data, optionally between start and stop
+// characters A–D in either case.
+func validateNW7(code string, _ *settings) error {
+	if err := validateNonEmpty(code); err != nil {
+		return err
+	}
+	runes := []rune(strings.ToUpper(code))
+	isStartStop := func(r rune) bool { return r >= 'A' && r <= 'D' }
+	first, last := 0, len(runes)
+	switch {
+	case isStartStop(runes[0]):
+		if len(runes) < 2 || !isStartStop(runes[len(runes)-1]) {
+			return &DrawError{Reason: ReasonInvalidCharacter, Position: 0,
+				Detail: fmt.Sprintf("start character %q has no matching stop character", runes[0])}
+		}
+		first, last = 1, len(runes)-1
+	case isStartStop(runes[last-1]):
+		return &DrawError{Reason: ReasonInvalidCharacter, Position: last - 1,
+			Detail: fmt.Sprintf("stop character %q has no matching start character", runes[last-1])}
+	}
+	if first == last {
+		return errDraw(ReasonEmptyInput, "no data between the start and stop characters")
+	}
+	for i := first; i < last; i++ {
+		switch {
+		case isStartStop(runes[i]):
+			return errInvalidChar(i, []rune(code)[i], "start/stop characters are only allowed at both ends")
+		case !strings.ContainsRune(nw7Chars, runes[i]):
+			return errInvalidChar(i, []rune(code)[i], "not in the NW-7 character set")
+		}
+	}
+	return nil
+}
+
+// validateGTIN checks a GS1 key of n digits without check digit, or n+1
+// digits with one.
+func validateGTIN(n int) func(string, *settings) error {
+	return func(code string, _ *settings) error {
+		if err := validateCharset(digitChars, "only digits are allowed")(code, nil); err != nil {
+			return err
+		}
+		switch len(code) {
+		case n:
+			return nil
+		case n + 1:
+			return validateCheckDigit(code[:n], code[n], n)
+		}
+		return errDraw(ReasonInvalidLength, "expected %d or %d digits, got %d", n, n+1, len(code))
+	}
+}
+
+func validateUPCE(code string, _ *settings) error {
+	if err := validateCharset(digitChars, "only digits are allowed")(code, nil); err != nil {
+		return err
+	}
+	switch len(code) {
+	case 6:
+		return nil
+	case 7, 8:
+		if code[0] != '0' && code[0] != '1' {
+			return &DrawError{Reason: ReasonInvalidCharacter, Position: 0,
+				Detail: "UPC-E number system must be 0 or 1"}
+		}
+		if len(code) == 8 {
+			return validateCheckDigit(expandUPCE(code[:7]), code[7], 7)
+		}
+		return nil
+	}
+	return errDraw(ReasonInvalidLength, "expected 6, 7 or 8 digits, got %d", len(code))
+}
+
+// validateCheckDigit compares the check digit at position pos with the one
+// computed over body.
+func validateCheckDigit(body string, got byte, pos int) error {
+	if want := gs1CheckDigit(body); want != got {
+		return errCheckDigit(pos, got, want)
+	}
+	return nil
+}
+
+// validateCapacity bounds the input length for 2D symbologies by their
+// maximum numeric, ASCII and binary capacities.
+func validateCapacity(numeric, ascii, binary int) func(string, *settings) error {
+	return func(code string, _ *settings) error {
+		limit, kind := numeric, "digits"
+		for _, r := range code {
+			if r > 127 {
+				limit, kind = binary, "bytes"
+				break
+			}
+			if !isDigit(r) {
+				limit, kind = ascii, "characters"
+			}
+		}
+		n := len(code)
+		if kind != "bytes" {
+			n = utf8.RuneCountInString(code)
+		}
+		if n > limit {
+			return errDraw(ReasonDataTooLong, "%d %s exceed the maximum of %d", n, kind, limit)
+		}
+		return nil
+	}
+}
+
+// gs1CheckDigit returns the GS1 modulo-10 check digit for a string of
+// digits.
+func gs1CheckDigit(body string) byte {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		d := int(body[len(body)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// luhnCheckDigit returns the modulus 10 check digit with weights 2 and 1
+// from the right, two-digit products counting as their digit sum (Luhn).
+func luhnCheckDigit(body string) byte {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		p := int(body[len(body)-1-i]-'0') * (2 - i%2)
+		sum += p/10 + p%10
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// mod11Remainder returns the sum of the digits weighted 2 to 7 from the
+// right, cycling, modulo 11.
+func mod11Remainder(body string) int {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		sum += int(body[len(body)-1-i]-'0') * (i%6 + 2)
+	}
+	return sum % 11
+}
+
+// expandUPCE converts a 7-digit UPC-E (number system + 6 digits) to the
+// 11-digit UPC-A body it represents.
+func expandUPCE(upce string) string {
+	ns, d := upce[:1], upce[1:7]
+	switch d[5] {
+	case '0', '1', '2':
+		return ns + d[0:2] + d[5:6] + "0000" + d[2:5]
+	case '3':
+		return ns + d[0:3] + "00000" + d[3:5]
+	case '4':
+		return ns + d[0:4] + "00000" + d[4:5]
+	}
+	return ns + d[0:5] + "0000" + d[5:6]
+}
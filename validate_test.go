@@ -0,0 +1,68 @@
+package barcode_pao
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// drawFailureOf explains a native failure for code on a generator of
+// typeID configured by set.
+func drawFailureOf(t *testing.T, typeID int, code string, set func(o *settings)) *DrawError {
+	t.Helper()
+	b := newPureGoBase(typeID, FormatPNG)
+	set(&b.opts)
+	var de *DrawError
+	if !errors.As(b.drawFailure(code, 100, 100), &de) {
+		t.Fatalf("drawFailure(%q) is not a *DrawError", code)
+	}
+	return de
+}
+
+func TestQRFailureAtVersion(t *testing.T) {
+	tests := []struct {
+		version int
+		ecl     string
+		code    string
+		reason  DrawReason
+	}{
+		// Version 1-L holds 17 bytes, 1-H 7.
+		{1, "L", strings.Repeat("a", 17), ReasonUnknown},
+		{1, "L", strings.Repeat("a", 18), ReasonDataTooLong},
+		{1, "H", strings.Repeat("a", 8), ReasonDataTooLong},
+		{2, "H", strings.Repeat("a", 8), ReasonUnknown},
+		// Automatic versions are only limited by version 40.
+		{0, "H", strings.Repeat("a", 2000), ReasonUnknown},
+		{0, "L", strings.Repeat("A", 4297), ReasonDataTooLong},
+	}
+	for _, tt := range tests {
+		de := drawFailureOf(t, typeQR, tt.code, func(o *settings) { o.qrVersion, o.eccLevel = tt.version, tt.ecl })
+		if de.Reason != tt.reason {
+			t.Errorf("version %d-%s, %d bytes: %v (%s), want %v", tt.version, tt.ecl, len(tt.code), de.Reason, de.Detail, tt.reason)
+		}
+		if tt.reason == ReasonDataTooLong && tt.version != 0 && !strings.Contains(de.Detail, "version") {
+			t.Errorf("version %d: detail %q does not name the version", tt.version, de.Detail)
+		}
+	}
+}
+
+func TestCode128FailureInCodeSet(t *testing.T) {
+	tests := []struct {
+		mode, code string
+		reason     DrawReason
+		pos        int
+	}{
+		{"C", "12A4", ReasonInvalidCharacter, 2},
+		{"C", "123", ReasonInvalidLength, -1},
+		{"A", "ABc", ReasonInvalidCharacter, 2},
+		{"B", "ab\tc", ReasonInvalidCharacter, 2},
+		{"AUTO", "ab\tc", ReasonUnknown, -1},
+		{"AUTO", "abé", ReasonInvalidCharacter, 2},
+	}
+	for _, tt := range tests {
+		de := drawFailureOf(t, typeCode128, tt.code, func(o *settings) { o.codeMode = tt.mode })
+		if de.Reason != tt.reason || de.Position != tt.pos {
+			t.Errorf("mode %s, %q: %v at %d (%s), want %v at %d", tt.mode, tt.code, de.Reason, de.Position, de.Detail, tt.reason, tt.pos)
+		}
+	}
+}
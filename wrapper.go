@@ -3,17 +3,20 @@
 //
 // Architecture:
 //
-//	Go code → syscall → barcode_pao.dll/so/dylib → C++ engine
+//	Go code → syscall (Windows) / dlopen (Linux, macOS) → barcode_pao.dll/so/dylib → C++ engine
 package barcode_pao
 
 import (
+	"bytes"
 	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
 	"sync"
-	"syscall"
-	"unsafe"
 )
 
 // Output format constants.
@@ -23,61 +26,106 @@ const (
 	FormatSVG  = "svg"
 )
 
+// Barcode type IDs passed to barcode_create (BC_* in barcode_ffi.h).
+const (
+	typeCode39             = 0
+	typeCode93             = 1
+	typeCode128            = 2
+	typeGS1128             = 3
+	typeNW7                = 4
+	typeMatrix2of5         = 5
+	typeNEC2of5            = 6
+	typeJan8               = 7
+	typeJan13              = 8
+	typeUPCA               = 9
+	typeUPCE               = 10
+	typeITF                = 11
+	typeGS1DataBar14       = 12
+	typeGS1DataBarLimited  = 13
+	typeGS1DataBarExpanded = 14
+	typeYubinCustomer      = 15
+	typeQR                 = 16
+	typeDataMatrix         = 17
+	typePDF417             = 18
+)
+
+// firstGoOnlyType starts the type IDs of the symbologies drawn only by the
+// pure-Go backend. They are never passed to the engine.
+const firstGoOnlyType = 100
+
+// Go-only type IDs.
+const (
+	typeITF14 = firstGoOnlyType + iota
+	typeCode32
+	typeHIBC
+	typeCodabar
+	typeCode11
+	typeMSI
+	typePharma
+	typeTelepen
+)
+
 // ─── Native library loading ────────────────────────────────────────────────
 
 var (
 	libOnce sync.Once
 	libErr  error
 
-	procCreate  *syscall.LazyProc
-	procDestroy *syscall.LazyProc
+	procCreate  *nativeProc
+	procDestroy *nativeProc
 
 	// Common settings
-	procSetOutputFormat    *syscall.LazyProc
-	procSetForegroundColor *syscall.LazyProc
-	procSetBackgroundColor *syscall.LazyProc
-	procSetPxAdjustBlack   *syscall.LazyProc
-	procSetPxAdjustWhite   *syscall.LazyProc
-	procSetFitWidth        *syscall.LazyProc
+	procSetOutputFormat    *nativeProc
+	procSetForegroundColor *nativeProc
+	procSetBackgroundColor *nativeProc
+	procSetPxAdjustBlack   *nativeProc
+	procSetPxAdjustWhite   *nativeProc
+	procSetFitWidth        *nativeProc
 
 	// 1D settings
-	procSetShowText         *syscall.LazyProc
-	procSetTextFontScale    *syscall.LazyProc
-	procSetTextGap          *syscall.LazyProc
-	procSetTextEvenSpacing  *syscall.LazyProc
+	procSetShowText        *nativeProc
+	procSetTextFontScale   *nativeProc
+	procSetTextGap         *nativeProc
+	procSetTextEvenSpacing *nativeProc
 
 	// 2D settings
-	procSetStringEncoding *syscall.LazyProc
+	procSetStringEncoding *nativeProc
 
 	// Type-specific settings
-	procSetShowStartStop          *syscall.LazyProc
-	procSetCodeMode               *syscall.LazyProc
-	procSetExtendedGuard          *syscall.LazyProc
-	procSetErrorCorrectionLevel   *syscall.LazyProc
-	procSetVersion                *syscall.LazyProc
-	procSetEncodeMode             *syscall.LazyProc
-	procSetCodeSize               *syscall.LazyProc
-	procSetEncodeScheme           *syscall.LazyProc
-	procSetErrorLevel             *syscall.LazyProc
-	procSetColumns                *syscall.LazyProc
-	procSetRows                   *syscall.LazyProc
-	procSetAspectRatio            *syscall.LazyProc
-	procSetYHeight                *syscall.LazyProc
-	procSetSymbolType14           *syscall.LazyProc
-	procSetSymbolTypeExp          *syscall.LazyProc
-	procSetNoOfColumns            *syscall.LazyProc
+	procSetShowStartStop        *nativeProc
+	procSetCodeMode             *nativeProc
+	procSetExtendedGuard        *nativeProc
+	procSetErrorCorrectionLevel *nativeProc
+	procSetVersion              *nativeProc
+	procSetEncodeMode           *nativeProc
+	procSetCodeSize             *nativeProc
+	procSetEncodeScheme         *nativeProc
+	procSetErrorLevel           *nativeProc
+	procSetColumns              *nativeProc
+	procSetRows                 *nativeProc
+	procSetAspectRatio          *nativeProc
+	procSetYHeight              *nativeProc
+	procSetSymbolType14         *nativeProc
+	procSetSymbolTypeExp        *nativeProc
+	procSetNoOfColumns          *nativeProc
+	procGetSymbolType14         *nativeProc
+	procEncode14                *nativeProc
+	procCalculateCheckDigit14   *nativeProc
 
 	// Draw functions
-	procDraw1D            *syscall.LazyProc
-	procDraw2D            *syscall.LazyProc
-	procDraw2DRect        *syscall.LazyProc
-	procDrawYubin         *syscall.LazyProc
-	procDrawYubinWithWidth *syscall.LazyProc
+	procDraw1D             *nativeProc
+	procDraw2D             *nativeProc
+	procDraw2DRect         *nativeProc
+	procDrawYubin          *nativeProc
+	procDrawYubinWithWidth *nativeProc
+	procDrawConvenience    *nativeProc
+	procDrawStacked        *nativeProc
 
 	// Get results
-	procGetBase64    *syscall.LazyProc
-	procGetSvg       *syscall.LazyProc
-	procIsSvgOutput  *syscall.LazyProc
+	procGetBase64    *nativeProc
+	procGetImageData *nativeProc
+	procGetSvg       *nativeProc
+	procIsSvgOutput  *nativeProc
 )
 
 func getNativeDir() string {
@@ -98,8 +146,10 @@ func getNativeDir() string {
 		}
 		// Try same directory as executable
 		dir = filepath.Dir(exePath)
-		if _, err2 := os.Stat(filepath.Join(dir, "barcode_pao.dll")); err2 == nil {
-			return dir
+		for _, name := range nativeLibNames {
+			if _, err2 := os.Stat(filepath.Join(dir, name)); err2 == nil {
+				return dir
+			}
 		}
 	}
 	return "native"
@@ -107,67 +157,66 @@ func getNativeDir() string {
 
 func loadLibrary() error {
 	libOnce.Do(func() {
-		nativeDir := getNativeDir()
-
-		// Preload dependent DLLs
-		for _, dep := range []string{"SDL2.dll", "SDL2_image.dll", "SDL2_ttf.dll"} {
-			depPath := filepath.Join(nativeDir, dep)
-			if _, err := os.Stat(depPath); err == nil {
-				syscall.LoadDLL(depPath)
-			}
+		lib, libPath, err := openNativeLibrary(getNativeDir())
+		if err != nil {
+			libErr = fmt.Errorf("%w from %s: %w", ErrLibraryNotFound, libPath, err)
+			return
 		}
 
-		dllPath := filepath.Join(nativeDir, "barcode_pao.dll")
-		dll := syscall.NewLazyDLL(dllPath)
-
 		// Bind all functions
-		procCreate = dll.NewProc("barcode_create")
-		procDestroy = dll.NewProc("barcode_destroy")
-
-		procSetOutputFormat = dll.NewProc("barcode_set_output_format")
-		procSetForegroundColor = dll.NewProc("barcode_set_foreground_color")
-		procSetBackgroundColor = dll.NewProc("barcode_set_background_color")
-		procSetPxAdjustBlack = dll.NewProc("barcode_set_px_adjust_black")
-		procSetPxAdjustWhite = dll.NewProc("barcode_set_px_adjust_white")
-		procSetFitWidth = dll.NewProc("barcode_set_fit_width")
-
-		procSetShowText = dll.NewProc("barcode_set_show_text")
-		procSetTextFontScale = dll.NewProc("barcode_set_text_font_scale")
-		procSetTextGap = dll.NewProc("barcode_set_text_gap")
-		procSetTextEvenSpacing = dll.NewProc("barcode_set_text_even_spacing")
-
-		procSetStringEncoding = dll.NewProc("barcode_set_string_encoding")
-
-		procSetShowStartStop = dll.NewProc("barcode_set_show_start_stop")
-		procSetCodeMode = dll.NewProc("barcode_set_code_mode")
-		procSetExtendedGuard = dll.NewProc("barcode_set_extended_guard")
-		procSetErrorCorrectionLevel = dll.NewProc("barcode_set_error_correction_level")
-		procSetVersion = dll.NewProc("barcode_set_version")
-		procSetEncodeMode = dll.NewProc("barcode_set_encode_mode")
-		procSetCodeSize = dll.NewProc("barcode_set_code_size")
-		procSetEncodeScheme = dll.NewProc("barcode_set_encode_scheme")
-		procSetErrorLevel = dll.NewProc("barcode_set_error_level")
-		procSetColumns = dll.NewProc("barcode_set_columns")
-		procSetRows = dll.NewProc("barcode_set_rows")
-		procSetAspectRatio = dll.NewProc("barcode_set_aspect_ratio")
-		procSetYHeight = dll.NewProc("barcode_set_y_height")
-		procSetSymbolType14 = dll.NewProc("barcode_set_symbol_type_14")
-		procSetSymbolTypeExp = dll.NewProc("barcode_set_symbol_type_exp")
-		procSetNoOfColumns = dll.NewProc("barcode_set_no_of_columns")
-
-		procDraw1D = dll.NewProc("barcode_draw_1d")
-		procDraw2D = dll.NewProc("barcode_draw_2d")
-		procDraw2DRect = dll.NewProc("barcode_draw_2d_rect")
-		procDrawYubin = dll.NewProc("barcode_draw_yubin")
-		procDrawYubinWithWidth = dll.NewProc("barcode_draw_yubin_with_width")
-
-		procGetBase64 = dll.NewProc("barcode_get_base64")
-		procGetSvg = dll.NewProc("barcode_get_svg")
-		procIsSvgOutput = dll.NewProc("barcode_is_svg_output")
-
-		// Verify the DLL can be loaded
+		procCreate = lib.NewProc("barcode_create")
+		procDestroy = lib.NewProc("barcode_destroy")
+
+		procSetOutputFormat = lib.NewProc("barcode_set_output_format")
+		procSetForegroundColor = lib.NewProc("barcode_set_foreground_color")
+		procSetBackgroundColor = lib.NewProc("barcode_set_background_color")
+		procSetPxAdjustBlack = lib.NewProc("barcode_set_px_adjust_black")
+		procSetPxAdjustWhite = lib.NewProc("barcode_set_px_adjust_white")
+		procSetFitWidth = lib.NewProc("barcode_set_fit_width")
+
+		procSetShowText = lib.NewProc("barcode_set_show_text")
+		procSetTextFontScale = lib.NewProc("barcode_set_text_font_scale")
+		procSetTextGap = lib.NewProc("barcode_set_text_gap")
+		procSetTextEvenSpacing = lib.NewProc("barcode_set_text_even_spacing")
+
+		procSetStringEncoding = lib.NewProc("barcode_set_string_encoding")
+
+		procSetShowStartStop = lib.NewProc("barcode_set_show_start_stop")
+		procSetCodeMode = lib.NewProc("barcode_set_code_mode")
+		procSetExtendedGuard = lib.NewProc("barcode_set_extended_guard")
+		procSetErrorCorrectionLevel = lib.NewProc("barcode_set_error_correction_level")
+		procSetVersion = lib.NewProc("barcode_set_version")
+		procSetEncodeMode = lib.NewProc("barcode_set_encode_mode")
+		procSetCodeSize = lib.NewProc("barcode_set_code_size")
+		procSetEncodeScheme = lib.NewProc("barcode_set_encode_scheme")
+		procSetErrorLevel = lib.NewProc("barcode_set_error_level")
+		procSetColumns = lib.NewProc("barcode_set_columns")
+		procSetRows = lib.NewProc("barcode_set_rows")
+		procSetAspectRatio = lib.NewProc("barcode_set_aspect_ratio")
+		procSetYHeight = lib.NewProc("barcode_set_y_height")
+		procSetSymbolType14 = lib.NewProc("barcode_set_symbol_type_14")
+		procSetSymbolTypeExp = lib.NewProc("barcode_set_symbol_type_exp")
+		procSetNoOfColumns = lib.NewProc("barcode_set_no_of_columns")
+		procGetSymbolType14 = lib.NewProc("barcode_get_symbol_type_14")
+		procEncode14 = lib.NewProc("barcode_encode_14")
+		procCalculateCheckDigit14 = lib.NewProc("barcode_calculate_check_digit_14")
+
+		procDraw1D = lib.NewProc("barcode_draw_1d")
+		procDraw2D = lib.NewProc("barcode_draw_2d")
+		procDraw2DRect = lib.NewProc("barcode_draw_2d_rect")
+		procDrawYubin = lib.NewProc("barcode_draw_yubin")
+		procDrawYubinWithWidth = lib.NewProc("barcode_draw_yubin_with_width")
+		procDrawConvenience = lib.NewProc("barcode_draw_convenience")
+		procDrawStacked = lib.NewProc("barcode_draw_stacked")
+
+		procGetBase64 = lib.NewProc("barcode_get_base64")
+		procGetSvg = lib.NewProc("barcode_get_svg")
+		procGetImageData = lib.NewProc("barcode_get_image_data")
+		procIsSvgOutput = lib.NewProc("barcode_is_svg_output")
+
+		// Verify the library exports the expected API
 		if err := procCreate.Find(); err != nil {
-			libErr = fmt.Errorf("failed to load barcode native library from %s: %w", dllPath, err)
+			libErr = fmt.Errorf("%w from %s: %w", ErrLibraryNotFound, libPath, err)
 		}
 	})
 	return libErr
@@ -175,27 +224,6 @@ func loadLibrary() error {
 
 // ─── Helper functions ──────────────────────────────────────────────────────
 
-func toPtr(s string) uintptr {
-	b := append([]byte(s), 0)
-	return uintptr(unsafe.Pointer(&b[0]))
-}
-
-func fromPtr(ptr uintptr) string {
-	if ptr == 0 {
-		return ""
-	}
-	// Read null-terminated C string
-	var buf []byte
-	for i := 0; ; i++ {
-		b := *(*byte)(unsafe.Pointer(ptr + uintptr(i)))
-		if b == 0 {
-			break
-		}
-		buf = append(buf, b)
-	}
-	return string(buf)
-}
-
 func boolToInt(b bool) uintptr {
 	if b {
 		return 1
@@ -208,127 +236,491 @@ func boolToInt(b bool) uintptr {
 // ═════════════════════════════════════════════════════════════════════════════
 
 // BarcodeBase holds the native handle for all barcode types.
+//
+// When the native library cannot be loaded, types with a pure-Go encoder
+// (see pureGoTypes) get a BarcodeBase without a handle; their settings are
+// then only kept in opts and Draw renders in Go.
+//
+// A generator is safe for concurrent use by multiple goroutines. Setters,
+// Encode and every Draw method hold the generator's lock, so a draw and the
+// retrieval of its result cannot interleave with another call on the same
+// handle. Calls on one generator are therefore serialized; use a Pool to
+// draw in parallel.
+//
+// Close releases the native handle. A generator that is never closed is
+// released by a finalizer once it is garbage collected.
 type BarcodeBase struct {
+	mu           sync.Mutex
 	handle       uintptr
+	closed       bool
+	typeID       int
 	outputFormat string
+	opts         settings
+	// ident is set on generators of a publication identifier drawn as
+	// typeID.
+	ident *identifier
 }
 
+var _ io.Closer = (*BarcodeBase)(nil)
+
 func newBarcodeBase(typeID int, outputFormat string) (*BarcodeBase, error) {
+	if typeID >= firstGoOnlyType {
+		return newPureGoBase(typeID, outputFormat), nil
+	}
 	if err := loadLibrary(); err != nil {
-		return nil, err
+		if !pureGoTypes[typeID] {
+			return nil, err
+		}
+		return newPureGoBase(typeID, outputFormat), nil
 	}
 	handle, _, _ := procCreate.Call(uintptr(typeID))
 	if handle == 0 {
-		return nil, fmt.Errorf("failed to create barcode handle for type %d", typeID)
+		return nil, fmt.Errorf("%w for type %d", ErrHandleCreation, typeID)
 	}
-	b := &BarcodeBase{handle: handle, outputFormat: outputFormat}
+	b := &BarcodeBase{handle: handle, typeID: typeID, outputFormat: outputFormat, opts: defaultSettings()}
 	b.SetOutputFormat(outputFormat)
-	runtime.SetFinalizer(b, func(b *BarcodeBase) {
-		if b.handle != 0 {
-			procDestroy.Call(b.handle)
-			b.handle = 0
-		}
-	})
+	runtime.SetFinalizer(b, (*BarcodeBase).destroy)
 	return b, nil
 }
 
+// newPureGoBase creates a generator without a native handle.
+func newPureGoBase(typeID int, outputFormat string) *BarcodeBase {
+	b := &BarcodeBase{typeID: typeID, opts: defaultSettings()}
+	b.SetOutputFormat(outputFormat)
+	return b
+}
+
+// destroy releases the native handle. The caller must hold the lock, or be
+// the finalizer.
+func (b *BarcodeBase) destroy() {
+	if b.handle != 0 {
+		procDestroy.Call(b.handle)
+		b.handle = 0
+	}
+}
+
+// Close releases the native handle. It is safe to call more than once.
+// After Close, setters, Encode and the Draw methods return ErrClosed.
+func (b *BarcodeBase) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	b.destroy()
+	runtime.SetFinalizer(b, nil)
+	return nil
+}
+
+// call invokes a native setter on the handle. It is a no-op on the pure-Go
+// backend, and for settings the engine does not have (p == nil): those live
+// in opts only.
+func (b *BarcodeBase) call(p *nativeProc, args ...uintptr) {
+	if b.handle == 0 || p == nil {
+		return
+	}
+	p.Call(append([]uintptr{b.handle}, args...)...)
+}
+
+// callDouble is call for setters taking a double.
+func (b *BarcodeBase) callDouble(p *nativeProc, v float64) {
+	if b.handle == 0 {
+		return
+	}
+	callDouble(p, b.handle, v)
+}
+
+// callString is call for setters taking a string.
+func (b *BarcodeBase) callString(p *nativeProc, s string) {
+	if b.handle == 0 {
+		return
+	}
+	var a cArgs
+	defer a.free()
+	p.Call(b.handle, a.str(s))
+}
+
+// set records a setting with apply and passes it to the native setter p,
+// holding the lock for both.
+func (b *BarcodeBase) set(apply func(o *settings), p *nativeProc, args ...uintptr) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	apply(&b.opts)
+	b.call(p, args...)
+	return nil
+}
+
+// setString is set for setters taking a string.
+func (b *BarcodeBase) setString(apply func(o *settings), p *nativeProc, s string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	apply(&b.opts)
+	b.callString(p, s)
+	return nil
+}
+
+// setDouble is set for setters taking a double.
+func (b *BarcodeBase) setDouble(apply func(o *settings), p *nativeProc, v float64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	apply(&b.opts)
+	b.callDouble(p, v)
+	return nil
+}
+
+// get reads a setting under the lock. Settings stay readable after Close.
+func get[T any](b *BarcodeBase, read func(o *settings) T) T {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return read(&b.opts)
+}
+
 // SetOutputFormat sets the output format (png, jpg, svg).
-func (b *BarcodeBase) SetOutputFormat(format string) {
+func (b *BarcodeBase) SetOutputFormat(format string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
 	b.outputFormat = format
-	procSetOutputFormat.Call(b.handle, toPtr(format))
+	b.callString(procSetOutputFormat, format)
+	return nil
+}
+
+// GetOutputFormat returns the value set by SetOutputFormat.
+func (b *BarcodeBase) GetOutputFormat() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.outputFormat
 }
 
 // SetForegroundColor sets the foreground color (RGBA).
-func (b *BarcodeBase) SetForegroundColor(r, g, bl, a int) {
-	procSetForegroundColor.Call(b.handle, uintptr(r), uintptr(g), uintptr(bl), uintptr(a))
+func (b *BarcodeBase) SetForegroundColor(r, g, bl, a int) error {
+	return b.set(func(o *settings) { o.fg = rgba(r, g, bl, a) }, procSetForegroundColor, uintptr(r), uintptr(g), uintptr(bl), uintptr(a))
+}
+
+// GetForegroundColor returns the foreground color (RGBA).
+func (b *BarcodeBase) GetForegroundColor() (r, g, bl, a int) {
+	c := get(b, func(o *settings) color.RGBA { return o.fg })
+	return int(c.R), int(c.G), int(c.B), int(c.A)
 }
 
 // SetBackgroundColor sets the background color (RGBA).
-func (b *BarcodeBase) SetBackgroundColor(r, g, bl, a int) {
-	procSetBackgroundColor.Call(b.handle, uintptr(r), uintptr(g), uintptr(bl), uintptr(a))
+func (b *BarcodeBase) SetBackgroundColor(r, g, bl, a int) error {
+	return b.set(func(o *settings) { o.bg = rgba(r, g, bl, a) }, procSetBackgroundColor, uintptr(r), uintptr(g), uintptr(bl), uintptr(a))
+}
+
+// GetBackgroundColor returns the background color (RGBA).
+func (b *BarcodeBase) GetBackgroundColor() (r, g, bl, a int) {
+	c := get(b, func(o *settings) color.RGBA { return o.bg })
+	return int(c.R), int(c.G), int(c.B), int(c.A)
 }
 
 func (b *BarcodeBase) getResult() (string, error) {
 	isSvg, _, _ := procIsSvgOutput.Call(b.handle)
 	if isSvg == 1 {
 		ptr, _, _ := procGetSvg.Call(b.handle)
-		return fromPtr(ptr), nil
+		return goString(ptr)
 	}
 	ptr, _, _ := procGetBase64.Call(b.handle)
-	return fromPtr(ptr), nil
+	return goString(ptr)
+}
+
+// getBytes returns the raw image data (or SVG markup) of the last draw.
+func (b *BarcodeBase) getBytes() ([]byte, error) {
+	isSvg, _, _ := procIsSvgOutput.Call(b.handle)
+	if isSvg == 1 {
+		ptr, _, _ := procGetSvg.Call(b.handle)
+		return goStringBytes(ptr)
+	}
+	var a cArgs
+	defer a.free()
+	size := new(int32)
+	ptr, _, _ := procGetImageData.Call(b.handle, a.out(size))
+	if ptr == 0 || *size <= 0 {
+		return nil, fmt.Errorf("no image data available")
+	}
+	return goBytes(ptr, int(*size)), nil
+}
+
+// renderString runs render and returns its output the way Draw does:
+// Base64 for raster formats, markup for SVG. render returns a nil drawing
+// when the result is held by the native handle, so the lock is kept until
+// the result has been read back.
+func (b *BarcodeBase) renderString(render func() (*drawing, error)) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return "", ErrClosed
+	}
+	d, err := render()
+	if err != nil {
+		return "", err
+	}
+	if d != nil {
+		return d.encode(b.outputFormat)
+	}
+	return b.getResult()
+}
+
+// renderBytes is renderString for the raw-bytes API.
+func (b *BarcodeBase) renderBytes(render func() (*drawing, error)) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+	d, err := render()
+	if err != nil {
+		return nil, err
+	}
+	if d != nil {
+		return d.encodeBytes(b.outputFormat)
+	}
+	return b.getBytes()
+}
+
+// renderImage runs render and returns its output as an image. The native
+// handle is switched to PNG for the call so that the result decodes
+// losslessly whatever the configured output format.
+func (b *BarcodeBase) renderImage(render func() (*drawing, error)) (image.Image, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+	if b.handle != 0 && normalizeFormat(b.outputFormat) != FormatPNG {
+		b.callString(procSetOutputFormat, FormatPNG)
+		defer b.callString(procSetOutputFormat, b.outputFormat)
+	}
+	d, err := render()
+	if err != nil {
+		return nil, err
+	}
+	if d != nil {
+		return d.raster(), nil
+	}
+	data, err := b.getBytes()
+	if err != nil {
+		return nil, err
+	}
+	return png.Decode(bytes.NewReader(data))
+}
+
+// writeResult adapts a DrawBytes result for the DrawTo methods.
+func writeResult(w io.Writer) func([]byte, error) error {
+	return func(data []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
 }
 
 // Barcode1DBase provides common 1D barcode settings.
 type Barcode1DBase struct {
-	BarcodeBase
+	*BarcodeBase
 }
 
 // SetShowText sets whether to show text below the barcode.
-func (b *Barcode1DBase) SetShowText(show bool) {
-	procSetShowText.Call(b.handle, boolToInt(show))
+func (b *Barcode1DBase) SetShowText(show bool) error {
+	return b.set(func(o *settings) { o.showText = show }, procSetShowText, boolToInt(show))
+}
+
+// GetShowText returns the value set by SetShowText.
+func (b *Barcode1DBase) GetShowText() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.showText })
 }
 
 // SetTextGap sets the gap between barcode and text.
-func (b *Barcode1DBase) SetTextGap(gap float64) {
-	procSetTextGap.Call(b.handle, uintptr(*(*uint64)(unsafe.Pointer(&gap))))
+func (b *Barcode1DBase) SetTextGap(gap float64) error {
+	return b.setDouble(func(o *settings) { o.textGap = gap }, procSetTextGap, gap)
+}
+
+// GetTextGap returns the value set by SetTextGap.
+func (b *Barcode1DBase) GetTextGap() float64 {
+	return get(b.BarcodeBase, func(o *settings) float64 { return o.textGap })
 }
 
 // SetTextFontScale sets the text font scale.
-func (b *Barcode1DBase) SetTextFontScale(scale float64) {
-	procSetTextFontScale.Call(b.handle, uintptr(*(*uint64)(unsafe.Pointer(&scale))))
+func (b *Barcode1DBase) SetTextFontScale(scale float64) error {
+	return b.setDouble(func(o *settings) { o.textFontScale = scale }, procSetTextFontScale, scale)
+}
+
+// GetTextFontScale returns the value set by SetTextFontScale.
+func (b *Barcode1DBase) GetTextFontScale() float64 {
+	return get(b.BarcodeBase, func(o *settings) float64 { return o.textFontScale })
 }
 
 // SetTextEvenSpacing sets text even spacing mode.
-func (b *Barcode1DBase) SetTextEvenSpacing(even bool) {
-	procSetTextEvenSpacing.Call(b.handle, boolToInt(even))
+func (b *Barcode1DBase) SetTextEvenSpacing(even bool) error {
+	return b.set(func(o *settings) { o.textEvenSpacing = even }, procSetTextEvenSpacing, boolToInt(even))
+}
+
+// GetTextEvenSpacing returns the value set by SetTextEvenSpacing.
+func (b *Barcode1DBase) GetTextEvenSpacing() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.textEvenSpacing })
 }
 
 // SetFitWidth sets whether to fit the barcode to width.
-func (b *Barcode1DBase) SetFitWidth(fit bool) {
-	procSetFitWidth.Call(b.handle, boolToInt(fit))
+func (b *Barcode1DBase) SetFitWidth(fit bool) error {
+	return b.set(func(o *settings) { o.fitWidth = fit }, procSetFitWidth, boolToInt(fit))
+}
+
+// GetFitWidth returns the value set by SetFitWidth.
+func (b *Barcode1DBase) GetFitWidth() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.fitWidth })
 }
 
 // SetPxAdjustBlack sets pixel adjustment for black bars.
-func (b *Barcode1DBase) SetPxAdjustBlack(adj int) {
-	procSetPxAdjustBlack.Call(b.handle, uintptr(adj))
+func (b *Barcode1DBase) SetPxAdjustBlack(adj int) error {
+	return b.set(func(o *settings) { o.pxAdjustBlack = adj }, procSetPxAdjustBlack, uintptr(adj))
+}
+
+// GetPxAdjustBlack returns the value set by SetPxAdjustBlack.
+func (b *Barcode1DBase) GetPxAdjustBlack() int {
+	return get(b.BarcodeBase, func(o *settings) int { return o.pxAdjustBlack })
 }
 
 // SetPxAdjustWhite sets pixel adjustment for white bars.
-func (b *Barcode1DBase) SetPxAdjustWhite(adj int) {
-	procSetPxAdjustWhite.Call(b.handle, uintptr(adj))
+func (b *Barcode1DBase) SetPxAdjustWhite(adj int) error {
+	return b.set(func(o *settings) { o.pxAdjustWhite = adj }, procSetPxAdjustWhite, uintptr(adj))
+}
+
+// GetPxAdjustWhite returns the value set by SetPxAdjustWhite.
+func (b *Barcode1DBase) GetPxAdjustWhite() int {
+	return get(b.BarcodeBase, func(o *settings) int { return o.pxAdjustWhite })
 }
 
 // Draw generates a 1D barcode and returns Base64 or SVG string.
 func (b *Barcode1DBase) Draw(code string, width, height int) (string, error) {
-	ret, _, _ := procDraw1D.Call(b.handle, toPtr(code), uintptr(width), uintptr(height))
+	return b.renderString(func() (*drawing, error) { return b.render(code, width, height) })
+}
+
+// DrawBytes generates a 1D barcode and returns the raw PNG/JPEG data or SVG
+// markup.
+func (b *Barcode1DBase) DrawBytes(code string, width, height int) ([]byte, error) {
+	return b.renderBytes(func() (*drawing, error) { return b.render(code, width, height) })
+}
+
+// DrawTo generates a 1D barcode and writes the raw image to w.
+func (b *Barcode1DBase) DrawTo(w io.Writer, code string, width, height int) error {
+	return writeResult(w)(b.DrawBytes(code, width, height))
+}
+
+// DrawImage generates a 1D barcode as an image. The pure-Go backend returns
+// an *image.Paletted with the background at index 0 and bars at index 1.
+func (b *Barcode1DBase) DrawImage(code string, width, height int) (image.Image, error) {
+	return b.renderImage(func() (*drawing, error) { return b.render(code, width, height) })
+}
+
+// render draws with the pure-Go backend when an EAN/UPC add-on, a header
+// or a Code39 Full ASCII or check character option is set, which the
+// engine does not support. The NW-7 start, stop and check character
+// options are applied to the input before it is passed to the engine; as
+// long as none of them has been set, NW-7 input reaches the engine as
+// written.
+func (b *Barcode1DBase) render(code string, width, height int) (*drawing, error) {
+	if b.handle == 0 || b.ident != nil || b.opts.addOn != "" || b.opts.fullASCII || b.opts.code39Check {
+		return b.layoutPureGo1D(code, width, height)
+	}
+	code, err := applyCheckDigitPolicy(b.typeID, code, &b.opts)
+	if err != nil {
+		return nil, b.drawError(code, err)
+	}
+	if b.typeID == typeNW7 && b.opts.nw7Set {
+		full, err := completeNW7(code, &b.opts)
+		if err != nil {
+			return nil, b.drawError(code, err)
+		}
+		code = full
+	}
+	if err := b.checkNativeCode(code); err != nil {
+		return nil, err
+	}
+	var a cArgs
+	defer a.free()
+	ret, _, _ := procDraw1D.Call(b.handle, a.str(code), uintptr(width), uintptr(height))
 	if ret != 1 {
-		return "", fmt.Errorf("draw failed")
+		return nil, b.drawFailure(code, width, height)
 	}
-	return b.getResult()
+	return nil, nil
 }
 
 // Barcode2DBase provides common 2D barcode settings.
 type Barcode2DBase struct {
-	BarcodeBase
+	*BarcodeBase
 }
 
 // SetStringEncoding sets the string encoding (utf-8, shift-jis).
-func (b *Barcode2DBase) SetStringEncoding(enc string) {
-	procSetStringEncoding.Call(b.handle, toPtr(enc))
+func (b *Barcode2DBase) SetStringEncoding(enc string) error {
+	return b.setString(func(o *settings) { o.stringEncoding = enc }, procSetStringEncoding, enc)
+}
+
+// GetStringEncoding returns the value set by SetStringEncoding.
+func (b *Barcode2DBase) GetStringEncoding() string {
+	return get(b.BarcodeBase, func(o *settings) string { return o.stringEncoding })
 }
 
 // SetFitWidth sets whether to fit the barcode to width.
-func (b *Barcode2DBase) SetFitWidth(fit bool) {
-	procSetFitWidth.Call(b.handle, boolToInt(fit))
+func (b *Barcode2DBase) SetFitWidth(fit bool) error {
+	return b.set(func(o *settings) { o.fitWidth = fit }, procSetFitWidth, boolToInt(fit))
+}
+
+// GetFitWidth returns the value set by SetFitWidth.
+func (b *Barcode2DBase) GetFitWidth() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.fitWidth })
 }
 
 // Draw generates a 2D barcode and returns Base64 or SVG string.
 func (b *Barcode2DBase) Draw(code string, size int) (string, error) {
-	ret, _, _ := procDraw2D.Call(b.handle, toPtr(code), uintptr(size))
+	return b.renderString(func() (*drawing, error) { return b.render(code, size) })
+}
+
+// DrawBytes generates a 2D barcode and returns the raw PNG/JPEG data or SVG
+// markup.
+func (b *Barcode2DBase) DrawBytes(code string, size int) ([]byte, error) {
+	return b.renderBytes(func() (*drawing, error) { return b.render(code, size) })
+}
+
+// DrawTo generates a 2D barcode and writes the raw image to w.
+func (b *Barcode2DBase) DrawTo(w io.Writer, code string, size int) error {
+	return writeResult(w)(b.DrawBytes(code, size))
+}
+
+// DrawImage generates a 2D barcode as an image. The pure-Go backend returns
+// an *image.Paletted with the background at index 0 and modules at index 1.
+func (b *Barcode2DBase) DrawImage(code string, size int) (image.Image, error) {
+	return b.renderImage(func() (*drawing, error) { return b.render(code, size) })
+}
+
+// render draws with the pure-Go backend in GS1 mode, which the engine does
+// not support.
+func (b *Barcode2DBase) render(code string, size int) (*drawing, error) {
+	if b.handle == 0 || b.opts.gs1 {
+		return b.layoutPureGo2D(code, size)
+	}
+	if err := b.checkNativeCode(code); err != nil {
+		return nil, err
+	}
+	var a cArgs
+	defer a.free()
+	ret, _, _ := procDraw2D.Call(b.handle, a.str(code), uintptr(size))
 	if ret != 1 {
-		return "", fmt.Errorf("draw failed")
+		return nil, b.drawFailure(code, size)
 	}
-	return b.getResult()
+	return nil, nil
 }
 
 // ═════════════════════════════════════════════════════════════════════════════
@@ -339,182 +731,827 @@ func (b *Barcode2DBase) Draw(code string, size int) (string, error) {
 type Code39 struct{ Barcode1DBase }
 
 // NewCode39 creates a Code39 barcode generator.
+// It panics on failure; use NewCode39E to handle errors.
 func NewCode39(outputFormat string) *Code39 {
-	base, err := newBarcodeBase(0, outputFormat)
+	return must(NewCode39E(outputFormat))
+}
+
+// NewCode39E creates a Code39 barcode generator.
+func NewCode39E(outputFormat string) (*Code39, error) {
+	base, err := newBarcodeBase(typeCode39, outputFormat)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return &Code39{Barcode1DBase{*base}}
+	return &Code39{Barcode1DBase{base}}, nil
 }
 
 // SetShowStartStop sets whether to show start/stop characters.
-func (b *Code39) SetShowStartStop(show bool) {
-	procSetShowStartStop.Call(b.handle, boolToInt(show))
+func (b *Code39) SetShowStartStop(show bool) error {
+	return b.set(func(o *settings) { o.showStartStop = show }, procSetShowStartStop, boolToInt(show))
+}
+
+// GetShowStartStop returns the value set by SetShowStartStop.
+func (b *Code39) GetShowStartStop() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.showStartStop })
+}
+
+// SetFullASCII sets whether to encode all 128 ASCII characters, each
+// outside the Code39 set as a pair of Code39 characters. The text shows
+// the input. Full ASCII symbols are drawn by the pure-Go backend.
+func (b *Code39) SetFullASCII(on bool) error {
+	return b.set(func(o *settings) { o.fullASCII = on }, nil)
+}
+
+// GetFullASCII returns the value set by SetFullASCII.
+func (b *Code39) GetFullASCII() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.fullASCII })
+}
+
+// SetCheckCharacter sets whether to append the modulo-43 check character,
+// which is also printed. Symbols with it are drawn by the pure-Go backend.
+func (b *Code39) SetCheckCharacter(on bool) error {
+	return b.set(func(o *settings) { o.code39Check = on }, nil)
+}
+
+// GetCheckCharacter returns the value set by SetCheckCharacter.
+func (b *Code39) GetCheckCharacter() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.code39Check })
+}
+
+// Code32 generates Code 32 barcodes, the Italian pharmacode: the 9-digit
+// AIC code of a medicine drawn as Code39. Draw takes 8 digits, to which
+// the check digit is added, or 9, optionally after the 'A' printed before
+// them. It always draws with the pure-Go backend.
+type Code32 struct{ Barcode1DBase }
+
+// NewCode32 creates a Code 32 barcode generator.
+// It panics on failure; use NewCode32E to handle errors.
+func NewCode32(outputFormat string) *Code32 {
+	return must(NewCode32E(outputFormat))
+}
+
+// NewCode32E creates a Code 32 barcode generator. It does not need the
+// native library.
+func NewCode32E(outputFormat string) (*Code32, error) {
+	base, err := newBarcodeBase(typeCode32, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &Code32{Barcode1DBase{base}}, nil
+}
+
+// HIBC generates HIBC (Health Industry Bar Code) Code39 barcodes for
+// medical devices. Draw takes the data without the leading '+' flag
+// character, which is added along with the mandatory check character.
+// It always draws with the pure-Go backend.
+type HIBC struct{ Barcode1DBase }
+
+// NewHIBC creates an HIBC barcode generator.
+// It panics on failure; use NewHIBCE to handle errors.
+func NewHIBC(outputFormat string) *HIBC {
+	return must(NewHIBCE(outputFormat))
+}
+
+// NewHIBCE creates an HIBC barcode generator. It does not need the native
+// library.
+func NewHIBCE(outputFormat string) (*HIBC, error) {
+	base, err := newBarcodeBase(typeHIBC, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &HIBC{Barcode1DBase{base}}, nil
+}
+
+// SetStandard sets the data structure the input is checked against (LIC,
+// PAS).
+func (b *HIBC) SetStandard(std HIBCStandard) error {
+	std, err := parseOption("HIBC standard", std, hibcStandards)
+	if err != nil {
+		return err
+	}
+	return b.set(func(o *settings) { o.hibcStandard = std }, nil)
+}
+
+// GetStandard returns the value set by SetStandard.
+func (b *HIBC) GetStandard() HIBCStandard {
+	return get(b.BarcodeBase, func(o *settings) HIBCStandard { return o.hibcStandard })
+}
+
+// SetShowStartStop sets whether to show the '*' start/stop characters.
+func (b *HIBC) SetShowStartStop(show bool) error {
+	return b.set(func(o *settings) { o.showStartStop = show }, nil)
+}
+
+// GetShowStartStop returns the value set by SetShowStartStop.
+func (b *HIBC) GetShowStartStop() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.showStartStop })
 }
 
 // Code93 generates Code93 barcodes.
 type Code93 struct{ Barcode1DBase }
 
 // NewCode93 creates a Code93 barcode generator.
+// It panics on failure; use NewCode93E to handle errors.
 func NewCode93(outputFormat string) *Code93 {
-	base, err := newBarcodeBase(1, outputFormat)
+	return must(NewCode93E(outputFormat))
+}
+
+// NewCode93E creates a Code93 barcode generator.
+func NewCode93E(outputFormat string) (*Code93, error) {
+	base, err := newBarcodeBase(typeCode93, outputFormat)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return &Code93{Barcode1DBase{*base}}
+	return &Code93{Barcode1DBase{base}}, nil
 }
 
 // Code128 generates Code128 barcodes.
 type Code128 struct{ Barcode1DBase }
 
 // NewCode128 creates a Code128 barcode generator.
+// It panics on failure; use NewCode128E to handle errors.
 func NewCode128(outputFormat string) *Code128 {
-	base, err := newBarcodeBase(2, outputFormat)
+	return must(NewCode128E(outputFormat))
+}
+
+// NewCode128E creates a Code128 barcode generator.
+func NewCode128E(outputFormat string) (*Code128, error) {
+	base, err := newBarcodeBase(typeCode128, outputFormat)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return &Code128{Barcode1DBase{*base}}
+	return &Code128{Barcode1DBase{base}}, nil
 }
 
 // SetCodeMode sets the code mode (AUTO, A, B, C).
-func (b *Code128) SetCodeMode(mode string) {
-	procSetCodeMode.Call(b.handle, toPtr(mode))
+func (b *Code128) SetCodeMode(mode Code128Mode) error {
+	mode, err := parseOption("Code128 mode", mode, code128Modes)
+	if err != nil {
+		return err
+	}
+	return b.setString(func(o *settings) { o.codeMode = string(mode) }, procSetCodeMode, string(mode))
+}
+
+// SetCodeModeString is SetCodeMode for a mode held in a string, the
+// parameter type SetCodeMode took before Code128Mode was introduced. The
+// value is validated the same way.
+func (b *Code128) SetCodeModeString(mode string) error {
+	return b.SetCodeMode(Code128Mode(mode))
+}
+
+// GetCodeMode returns the value set by SetCodeMode.
+func (b *Code128) GetCodeMode() Code128Mode {
+	return get(b.BarcodeBase, func(o *settings) Code128Mode { return Code128Mode(o.codeMode) })
 }
 
 // GS1128 generates GS1-128 barcodes.
 type GS1128 struct{ Barcode1DBase }
 
 // NewGS1128 creates a GS1-128 barcode generator.
+// It panics on failure; use NewGS1128E to handle errors.
 func NewGS1128(outputFormat string) *GS1128 {
-	base, err := newBarcodeBase(3, outputFormat)
-	if err != nil {
-		panic(err)
-	}
-	return &GS1128{Barcode1DBase{*base}}
+	return must(NewGS1128E(outputFormat))
 }
 
-// NW7 generates NW-7 (Codabar) barcodes.
-type NW7 struct{ Barcode1DBase }
-
-// NewNW7 creates a NW-7 barcode generator.
-func NewNW7(outputFormat string) *NW7 {
-	base, err := newBarcodeBase(4, outputFormat)
+// NewGS1128E creates a GS1-128 barcode generator.
+func NewGS1128E(outputFormat string) (*GS1128, error) {
+	base, err := newBarcodeBase(typeGS1128, outputFormat)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return &NW7{Barcode1DBase{*base}}
+	return &GS1128{Barcode1DBase{base}}, nil
 }
 
-// SetShowStartStop sets whether to show start/stop characters.
-func (b *NW7) SetShowStartStop(show bool) {
-	procSetShowStartStop.Call(b.handle, boolToInt(show))
+// DrawConvenience generates a convenience-store payment barcode
+// (標準料金代理収納) and returns Base64 or SVG string. code is the 44-digit
+// payment code, or its first 43 digits to have the check digit appended;
+// see ValidateConvenience for the checks applied before drawing.
+func (b *GS1128) DrawConvenience(code string, width, height int) (string, error) {
+	return b.renderString(func() (*drawing, error) { return b.renderConvenience(code, width, height) })
 }
 
-// ITF generates ITF (Interleaved 2 of 5) barcodes.
-type ITF struct{ Barcode1DBase }
+// DrawConvenienceBytes is DrawConvenience returning the raw PNG/JPEG data or
+// SVG markup.
+func (b *GS1128) DrawConvenienceBytes(code string, width, height int) ([]byte, error) {
+	return b.renderBytes(func() (*drawing, error) { return b.renderConvenience(code, width, height) })
+}
 
-// NewITF creates an ITF barcode generator.
-func NewITF(outputFormat string) *ITF {
-	base, err := newBarcodeBase(11, outputFormat)
-	if err != nil {
-		panic(err)
-	}
-	return &ITF{Barcode1DBase{*base}}
+// DrawConvenienceTo is DrawConvenience writing the raw image to w.
+func (b *GS1128) DrawConvenienceTo(w io.Writer, code string, width, height int) error {
+	return writeResult(w)(b.DrawConvenienceBytes(code, width, height))
 }
 
-// Matrix2of5 generates Matrix 2 of 5 barcodes.
-type Matrix2of5 struct{ Barcode1DBase }
+// DrawConvenienceImage is DrawConvenience returning an image.
+func (b *GS1128) DrawConvenienceImage(code string, width, height int) (image.Image, error) {
+	return b.renderImage(func() (*drawing, error) { return b.renderConvenience(code, width, height) })
+}
 
-// NewMatrix2of5 creates a Matrix 2 of 5 barcode generator.
-func NewMatrix2of5(outputFormat string) *Matrix2of5 {
-	base, err := newBarcodeBase(5, outputFormat)
+func (b *GS1128) renderConvenience(code string, width, height int) (*drawing, error) {
+	digits, err := validateConvenience(code)
 	if err != nil {
-		panic(err)
+		return nil, b.drawError(code, err)
 	}
-	return &Matrix2of5{Barcode1DBase{*base}}
+	var a cArgs
+	defer a.free()
+	ret, _, _ := procDrawConvenience.Call(b.handle, a.str(digits), uintptr(width), uintptr(height))
+	if ret != 1 {
+		return nil, b.drawFailure(code, width, height)
+	}
+	return nil, nil
 }
 
-// NEC2of5 generates NEC 2 of 5 barcodes.
-type NEC2of5 struct{ Barcode1DBase }
+// NW7 generates NW-7 (Codabar) barcodes.
+type NW7 struct{ Barcode1DBase }
 
-// NewNEC2of5 creates a NEC 2 of 5 barcode generator.
-func NewNEC2of5(outputFormat string) *NEC2of5 {
-	base, err := newBarcodeBase(6, outputFormat)
+// NewNW7 creates a NW-7 barcode generator.
+// It panics on failure; use NewNW7E to handle errors.
+func NewNW7(outputFormat string) *NW7 {
+	return must(NewNW7E(outputFormat))
+}
+
+// NewNW7E creates a NW-7 barcode generator.
+func NewNW7E(outputFormat string) (*NW7, error) {
+	base, err := newBarcodeBase(typeNW7, outputFormat)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return &NEC2of5{Barcode1DBase{*base}}
+	return &NW7{Barcode1DBase{base}}, nil
 }
 
-// Jan8 generates JAN-8 (EAN-8) barcodes.
-type Jan8 struct{ Barcode1DBase }
+// SetShowStartStop sets whether to show start/stop characters.
+func (b *NW7) SetShowStartStop(show bool) error {
+	return b.set(func(o *settings) { o.showStartStop = show }, procSetShowStartStop, boolToInt(show))
+}
 
-// NewJAN8 creates a JAN-8 barcode generator.
-func NewJAN8(outputFormat string) *Jan8 {
-	base, err := newBarcodeBase(7, outputFormat)
+// GetShowStartStop returns the value set by SetShowStartStop.
+func (b *NW7) GetShowStartStop() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.showStartStop })
+}
+
+// SetStartCharacter sets the start character (A, B, C, D) added to input
+// that has none. Input such as "B1234D" keeps its own characters. Until
+// this, SetStopCharacter or SetCheckScheme is called, the native engine
+// receives the input unchanged.
+func (b *NW7) SetStartCharacter(c string) error {
+	c, err := parseNW7StartStop("start", c)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	return &Jan8{Barcode1DBase{*base}}
+	return b.set(func(o *settings) { o.nw7Start, o.nw7Set = c, true }, nil)
 }
 
-// SetExtendedGuard sets whether to use extended guard bars.
-func (b *Jan8) SetExtendedGuard(ext bool) {
-	procSetExtendedGuard.Call(b.handle, boolToInt(ext))
+// GetStartCharacter returns the value set by SetStartCharacter.
+func (b *NW7) GetStartCharacter() string {
+	return get(b.BarcodeBase, func(o *settings) string { return o.nw7Start })
 }
 
-// Jan13 generates JAN-13 (EAN-13) barcodes.
-type Jan13 struct{ Barcode1DBase }
-
-// NewJAN13 creates a JAN-13 barcode generator.
-func NewJAN13(outputFormat string) *Jan13 {
-	base, err := newBarcodeBase(8, outputFormat)
+// SetStopCharacter sets the stop character (A, B, C, D) added to input
+// that has none.
+func (b *NW7) SetStopCharacter(c string) error {
+	c, err := parseNW7StartStop("stop", c)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	return &Jan13{Barcode1DBase{*base}}
+	return b.set(func(o *settings) { o.nw7Stop, o.nw7Set = c, true }, nil)
 }
 
-// SetExtendedGuard sets whether to use extended guard bars.
-func (b *Jan13) SetExtendedGuard(ext bool) {
-	procSetExtendedGuard.Call(b.handle, boolToInt(ext))
+// GetStopCharacter returns the value set by SetStopCharacter.
+func (b *NW7) GetStopCharacter() string {
+	return get(b.BarcodeBase, func(o *settings) string { return o.nw7Stop })
 }
 
-// UPCA generates UPC-A barcodes.
-type UPCA struct{ Barcode1DBase }
-
-// NewUPCA creates a UPC-A barcode generator.
-func NewUPCA(outputFormat string) *UPCA {
-	base, err := newBarcodeBase(9, outputFormat)
+// SetCheckScheme sets the check character scheme (NONE, MOD16, MOD11,
+// MOD10_W21, MOD10_W31, 7DR, 7DSR, 9DR, 9DSR). The check character is
+// added before the stop character and printed.
+func (b *NW7) SetCheckScheme(scheme NW7CheckScheme) error {
+	scheme, err := parseOption("NW-7 check scheme", scheme, nw7CheckSchemes)
 	if err != nil {
-		panic(err)
+		return err
 	}
-	return &UPCA{Barcode1DBase{*base}}
+	return b.set(func(o *settings) { o.nw7Check, o.nw7Set = scheme, true }, nil)
 }
 
-// SetExtendedGuard sets whether to use extended guard bars.
-func (b *UPCA) SetExtendedGuard(ext bool) {
-	procSetExtendedGuard.Call(b.handle, boolToInt(ext))
+// GetCheckScheme returns the value set by SetCheckScheme.
+func (b *NW7) GetCheckScheme() NW7CheckScheme {
+	return get(b.BarcodeBase, func(o *settings) NW7CheckScheme { return o.nw7Check })
 }
 
-// UPCE generates UPC-E barcodes.
-type UPCE struct{ Barcode1DBase }
+// RationalizedCodabar generates Codabar barcodes with a selectable
+// wide-to-narrow ratio, as specified by AIM for Codabar outside Japan. It
+// has the settings of NW7 and always draws with the pure-Go backend.
+type RationalizedCodabar struct{ NW7 }
 
-// NewUPCE creates a UPC-E barcode generator.
-func NewUPCE(outputFormat string) *UPCE {
-	base, err := newBarcodeBase(10, outputFormat)
+// NewRationalizedCodabar creates a Codabar barcode generator.
+// It panics on failure; use NewRationalizedCodabarE to handle errors.
+func NewRationalizedCodabar(outputFormat string) *RationalizedCodabar {
+	return must(NewRationalizedCodabarE(outputFormat))
+}
+
+// NewRationalizedCodabarE creates a Codabar barcode generator. It does
+// not need the native library.
+func NewRationalizedCodabarE(outputFormat string) (*RationalizedCodabar, error) {
+	base, err := newBarcodeBase(typeCodabar, outputFormat)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return &UPCE{Barcode1DBase{*base}}
+	return &RationalizedCodabar{NW7{Barcode1DBase{base}}}, nil
 }
 
-// SetExtendedGuard sets whether to use extended guard bars.
-func (b *UPCE) SetExtendedGuard(ext bool) {
-	procSetExtendedGuard.Call(b.handle, boolToInt(ext))
+// SetWideRatio sets the wide-to-narrow element ratio: 2, 2.5 or 3 (the
+// default, as NW7).
+func (b *RationalizedCodabar) SetWideRatio(ratio float64) error {
+	if ratio != 2 && ratio != 2.5 && ratio != 3 {
+		return fmt.Errorf("%w: Codabar wide ratio must be 2, 2.5 or 3, got %g", ErrInvalidOption, ratio)
+	}
+	return b.set(func(o *settings) { o.codabarRatio = ratio }, nil)
 }
 
-// ═════════════════════════════════════════════════════════════════════════════
+// GetWideRatio returns the value set by SetWideRatio.
+func (b *RationalizedCodabar) GetWideRatio() float64 {
+	return get(b.BarcodeBase, func(o *settings) float64 { return o.codabarRatio })
+}
+
+// ITF generates ITF (Interleaved 2 of 5) barcodes.
+type ITF struct{ Barcode1DBase }
+
+// NewITF creates an ITF barcode generator.
+// It panics on failure; use NewITFE to handle errors.
+func NewITF(outputFormat string) *ITF {
+	return must(NewITFE(outputFormat))
+}
+
+// NewITFE creates an ITF barcode generator.
+func NewITFE(outputFormat string) (*ITF, error) {
+	base, err := newBarcodeBase(typeITF, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &ITF{Barcode1DBase{base}}, nil
+}
+
+// ITF14 generates ITF-14 barcodes, the Interleaved 2 of 5 symbol of a
+// 14-digit GTIN on shipping cartons, with bearer bars and 10-module quiet
+// zones. Draw takes 13 digits, to which the check digit is added, or 14.
+// It always draws with the pure-Go backend.
+type ITF14 struct{ Barcode1DBase }
+
+// NewITF14 creates an ITF-14 barcode generator.
+// It panics on failure; use NewITF14E to handle errors.
+func NewITF14(outputFormat string) *ITF14 {
+	return must(NewITF14E(outputFormat))
+}
+
+// NewITF14E creates an ITF-14 barcode generator. It does not need the
+// native library.
+func NewITF14E(outputFormat string) (*ITF14, error) {
+	base, err := newBarcodeBase(typeITF14, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &ITF14{Barcode1DBase{base}}, nil
+}
+
+// SetBearerStyle sets the bearer bars (FRAME, TOP_BOTTOM, NONE).
+func (b *ITF14) SetBearerStyle(style BearerStyle) error {
+	style, err := parseOption("ITF-14 bearer style", style, bearerStyles)
+	if err != nil {
+		return err
+	}
+	return b.set(func(o *settings) { o.bearerStyle = style }, nil)
+}
+
+// GetBearerStyle returns the value set by SetBearerStyle.
+func (b *ITF14) GetBearerStyle() BearerStyle {
+	return get(b.BarcodeBase, func(o *settings) BearerStyle { return o.bearerStyle })
+}
+
+// SetBearerWidth sets the bearer bar thickness in modules (narrow bar
+// widths). GS1 asks for at least 2; the default is 5.
+func (b *ITF14) SetBearerWidth(modules int) error {
+	if modules < 1 {
+		return fmt.Errorf("%w: bearer width must be at least 1 module, got %d", ErrInvalidOption, modules)
+	}
+	return b.set(func(o *settings) { o.bearerWidth = modules }, nil)
+}
+
+// GetBearerWidth returns the value set by SetBearerWidth.
+func (b *ITF14) GetBearerWidth() int {
+	return get(b.BarcodeBase, func(o *settings) int { return o.bearerWidth })
+}
+
+// SetCheckDigitPolicy sets how a wrong check digit is handled (STRICT,
+// LENIENT).
+func (b *ITF14) SetCheckDigitPolicy(policy CheckDigitPolicy) error {
+	policy, err := parseOption("check digit policy", policy, checkDigitPolicies)
+	if err != nil {
+		return err
+	}
+	return b.set(func(o *settings) { o.checkDigitPolicy = policy }, nil)
+}
+
+// GetCheckDigitPolicy returns the value set by SetCheckDigitPolicy.
+func (b *ITF14) GetCheckDigitPolicy() CheckDigitPolicy {
+	return get(b.BarcodeBase, func(o *settings) CheckDigitPolicy { return o.checkDigitPolicy })
+}
+
+// Code11 generates Code 11 (USD-8) barcodes of digits and '-', used to
+// label telecommunications equipment. It always draws with the pure-Go
+// backend.
+type Code11 struct{ Barcode1DBase }
+
+// NewCode11 creates a Code 11 barcode generator.
+// It panics on failure; use NewCode11E to handle errors.
+func NewCode11(outputFormat string) *Code11 {
+	return must(NewCode11E(outputFormat))
+}
+
+// NewCode11E creates a Code 11 barcode generator. It does not need the
+// native library.
+func NewCode11E(outputFormat string) (*Code11, error) {
+	base, err := newBarcodeBase(typeCode11, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &Code11{Barcode1DBase{base}}, nil
+}
+
+// SetCheckDigits sets the number of check digits appended: 0, 1 (C) or 2
+// (C and K, the default).
+func (b *Code11) SetCheckDigits(n int) error {
+	if n < 0 || n > 2 {
+		return fmt.Errorf("%w: Code 11 takes 0 to 2 check digits, got %d", ErrInvalidOption, n)
+	}
+	return b.set(func(o *settings) { o.code11Checks = n }, nil)
+}
+
+// GetCheckDigits returns the value set by SetCheckDigits.
+func (b *Code11) GetCheckDigits() int {
+	return get(b.BarcodeBase, func(o *settings) int { return o.code11Checks })
+}
+
+// MSI generates MSI Plessey barcodes of digits, used for shelf labels. It
+// always draws with the pure-Go backend.
+type MSI struct{ Barcode1DBase }
+
+// NewMSI creates an MSI Plessey barcode generator.
+// It panics on failure; use NewMSIE to handle errors.
+func NewMSI(outputFormat string) *MSI {
+	return must(NewMSIE(outputFormat))
+}
+
+// NewMSIE creates an MSI Plessey barcode generator. It does not need the
+// native library.
+func NewMSIE(outputFormat string) (*MSI, error) {
+	base, err := newBarcodeBase(typeMSI, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &MSI{Barcode1DBase{base}}, nil
+}
+
+// SetCheckScheme sets the check digit scheme (NONE, MOD10, MOD10_MOD10,
+// MOD11, MOD11_MOD10).
+func (b *MSI) SetCheckScheme(scheme MSICheckScheme) error {
+	scheme, err := parseOption("MSI check scheme", scheme, msiCheckSchemes)
+	if err != nil {
+		return err
+	}
+	return b.set(func(o *settings) { o.msiCheck = scheme }, nil)
+}
+
+// GetCheckScheme returns the value set by SetCheckScheme.
+func (b *MSI) GetCheckScheme() MSICheckScheme {
+	return get(b.BarcodeBase, func(o *settings) MSICheckScheme { return o.msiCheck })
+}
+
+// Pharmacode generates Laetus Pharmacode (one-track) barcodes, which
+// encode a number from 3 to 131070 on pharmaceutical packaging. The text
+// is off by default, as the code is not meant to be read by people. It
+// always draws with the pure-Go backend.
+type Pharmacode struct{ Barcode1DBase }
+
+// NewPharmacode creates a Pharmacode barcode generator.
+// It panics on failure; use NewPharmacodeE to handle errors.
+func NewPharmacode(outputFormat string) *Pharmacode {
+	return must(NewPharmacodeE(outputFormat))
+}
+
+// NewPharmacodeE creates a Pharmacode barcode generator. It does not need
+// the native library.
+func NewPharmacodeE(outputFormat string) (*Pharmacode, error) {
+	base, err := newBarcodeBase(typePharma, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	base.opts.showText = false
+	return &Pharmacode{Barcode1DBase{base}}, nil
+}
+
+// Telepen generates Telepen barcodes of full ASCII, used in UK libraries
+// and industry. It always draws with the pure-Go backend.
+type Telepen struct{ Barcode1DBase }
+
+// NewTelepen creates a Telepen barcode generator.
+// It panics on failure; use NewTelepenE to handle errors.
+func NewTelepen(outputFormat string) *Telepen {
+	return must(NewTelepenE(outputFormat))
+}
+
+// NewTelepenE creates a Telepen barcode generator. It does not need the
+// native library.
+func NewTelepenE(outputFormat string) (*Telepen, error) {
+	base, err := newBarcodeBase(typeTelepen, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &Telepen{Barcode1DBase{base}}, nil
+}
+
+// SetNumeric sets whether to encode digits in pairs (Telepen Numeric),
+// which halves the symbol length. An 'X' may stand for the second digit of
+// a pair, and an odd number of digits gets a leading zero.
+func (b *Telepen) SetNumeric(on bool) error {
+	return b.set(func(o *settings) { o.telepenNumeric = on }, nil)
+}
+
+// GetNumeric returns the value set by SetNumeric.
+func (b *Telepen) GetNumeric() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.telepenNumeric })
+}
+
+// Matrix2of5 generates Matrix 2 of 5 barcodes.
+type Matrix2of5 struct{ Barcode1DBase }
+
+// NewMatrix2of5 creates a Matrix 2 of 5 barcode generator.
+// It panics on failure; use NewMatrix2of5E to handle errors.
+func NewMatrix2of5(outputFormat string) *Matrix2of5 {
+	return must(NewMatrix2of5E(outputFormat))
+}
+
+// NewMatrix2of5E creates a Matrix 2 of 5 barcode generator.
+func NewMatrix2of5E(outputFormat string) (*Matrix2of5, error) {
+	base, err := newBarcodeBase(typeMatrix2of5, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &Matrix2of5{Barcode1DBase{base}}, nil
+}
+
+// NEC2of5 generates NEC 2 of 5 barcodes.
+type NEC2of5 struct{ Barcode1DBase }
+
+// NewNEC2of5 creates a NEC 2 of 5 barcode generator.
+// It panics on failure; use NewNEC2of5E to handle errors.
+func NewNEC2of5(outputFormat string) *NEC2of5 {
+	return must(NewNEC2of5E(outputFormat))
+}
+
+// NewNEC2of5E creates a NEC 2 of 5 barcode generator.
+func NewNEC2of5E(outputFormat string) (*NEC2of5, error) {
+	base, err := newBarcodeBase(typeNEC2of5, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &NEC2of5{Barcode1DBase{base}}, nil
+}
+
+// Jan8 generates JAN-8 (EAN-8) barcodes.
+type Jan8 struct{ Barcode1DBase }
+
+// NewJAN8 creates a JAN-8 barcode generator.
+// It panics on failure; use NewJAN8E to handle errors.
+func NewJAN8(outputFormat string) *Jan8 {
+	return must(NewJAN8E(outputFormat))
+}
+
+// NewJAN8E creates a JAN-8 barcode generator.
+func NewJAN8E(outputFormat string) (*Jan8, error) {
+	base, err := newBarcodeBase(typeJan8, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &Jan8{Barcode1DBase{base}}, nil
+}
+
+// SetExtendedGuard sets whether to use extended guard bars.
+func (b *Jan8) SetExtendedGuard(ext bool) error {
+	return b.set(func(o *settings) { o.extendedGuard = ext }, procSetExtendedGuard, boolToInt(ext))
+}
+
+// GetExtendedGuard returns the value set by SetExtendedGuard.
+func (b *Jan8) GetExtendedGuard() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.extendedGuard })
+}
+
+// SetCheckDigitPolicy sets how a wrong check digit is handled (STRICT,
+// LENIENT). The policy is applied before the native engine is called, so
+// with the default, STRICT, input the engine used to draw with a wrong
+// check digit now fails; LENIENT draws it with the digit corrected.
+func (b *Jan8) SetCheckDigitPolicy(policy CheckDigitPolicy) error {
+	policy, err := parseOption("check digit policy", policy, checkDigitPolicies)
+	if err != nil {
+		return err
+	}
+	return b.set(func(o *settings) { o.checkDigitPolicy = policy }, nil)
+}
+
+// GetCheckDigitPolicy returns the value set by SetCheckDigitPolicy.
+func (b *Jan8) GetCheckDigitPolicy() CheckDigitPolicy {
+	return get(b.BarcodeBase, func(o *settings) CheckDigitPolicy { return o.checkDigitPolicy })
+}
+
+// SetAddOn sets the EAN-2 or EAN-5 add-on drawn after the symbol, e.g. the
+// price of a book or the issue of a magazine. "" removes it.
+func (b *Jan8) SetAddOn(addOn string) error {
+	if err := validateAddOn(addOn); err != nil {
+		return err
+	}
+	return b.set(func(o *settings) { o.addOn = addOn }, nil)
+}
+
+// GetAddOn returns the value set by SetAddOn.
+func (b *Jan8) GetAddOn() string {
+	return get(b.BarcodeBase, func(o *settings) string { return o.addOn })
+}
+
+// Jan13 generates JAN-13 (EAN-13) barcodes.
+type Jan13 struct{ Barcode1DBase }
+
+// NewJAN13 creates a JAN-13 barcode generator.
+// It panics on failure; use NewJAN13E to handle errors.
+func NewJAN13(outputFormat string) *Jan13 {
+	return must(NewJAN13E(outputFormat))
+}
+
+// NewJAN13E creates a JAN-13 barcode generator.
+func NewJAN13E(outputFormat string) (*Jan13, error) {
+	base, err := newBarcodeBase(typeJan13, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &Jan13{Barcode1DBase{base}}, nil
+}
+
+// SetExtendedGuard sets whether to use extended guard bars.
+func (b *Jan13) SetExtendedGuard(ext bool) error {
+	return b.set(func(o *settings) { o.extendedGuard = ext }, procSetExtendedGuard, boolToInt(ext))
+}
+
+// GetExtendedGuard returns the value set by SetExtendedGuard.
+func (b *Jan13) GetExtendedGuard() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.extendedGuard })
+}
+
+// SetCheckDigitPolicy sets how a wrong check digit is handled (STRICT,
+// LENIENT). The policy is applied before the native engine is called, so
+// with the default, STRICT, input the engine used to draw with a wrong
+// check digit now fails; LENIENT draws it with the digit corrected.
+func (b *Jan13) SetCheckDigitPolicy(policy CheckDigitPolicy) error {
+	policy, err := parseOption("check digit policy", policy, checkDigitPolicies)
+	if err != nil {
+		return err
+	}
+	return b.set(func(o *settings) { o.checkDigitPolicy = policy }, nil)
+}
+
+// GetCheckDigitPolicy returns the value set by SetCheckDigitPolicy.
+func (b *Jan13) GetCheckDigitPolicy() CheckDigitPolicy {
+	return get(b.BarcodeBase, func(o *settings) CheckDigitPolicy { return o.checkDigitPolicy })
+}
+
+// SetAddOn sets the EAN-2 or EAN-5 add-on drawn after the symbol, e.g. the
+// price of a book or the issue of a magazine. "" removes it.
+func (b *Jan13) SetAddOn(addOn string) error {
+	if err := validateAddOn(addOn); err != nil {
+		return err
+	}
+	return b.set(func(o *settings) { o.addOn = addOn }, nil)
+}
+
+// GetAddOn returns the value set by SetAddOn.
+func (b *Jan13) GetAddOn() string {
+	return get(b.BarcodeBase, func(o *settings) string { return o.addOn })
+}
+
+// UPCA generates UPC-A barcodes.
+type UPCA struct{ Barcode1DBase }
+
+// NewUPCA creates a UPC-A barcode generator.
+// It panics on failure; use NewUPCAE to handle errors.
+func NewUPCA(outputFormat string) *UPCA {
+	return must(NewUPCAE(outputFormat))
+}
+
+// NewUPCAE creates a UPC-A barcode generator.
+func NewUPCAE(outputFormat string) (*UPCA, error) {
+	base, err := newBarcodeBase(typeUPCA, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &UPCA{Barcode1DBase{base}}, nil
+}
+
+// SetExtendedGuard sets whether to use extended guard bars.
+func (b *UPCA) SetExtendedGuard(ext bool) error {
+	return b.set(func(o *settings) { o.extendedGuard = ext }, procSetExtendedGuard, boolToInt(ext))
+}
+
+// GetExtendedGuard returns the value set by SetExtendedGuard.
+func (b *UPCA) GetExtendedGuard() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.extendedGuard })
+}
+
+// SetCheckDigitPolicy sets how a wrong check digit is handled (STRICT,
+// LENIENT). The policy is applied before the native engine is called, so
+// with the default, STRICT, input the engine used to draw with a wrong
+// check digit now fails; LENIENT draws it with the digit corrected.
+func (b *UPCA) SetCheckDigitPolicy(policy CheckDigitPolicy) error {
+	policy, err := parseOption("check digit policy", policy, checkDigitPolicies)
+	if err != nil {
+		return err
+	}
+	return b.set(func(o *settings) { o.checkDigitPolicy = policy }, nil)
+}
+
+// GetCheckDigitPolicy returns the value set by SetCheckDigitPolicy.
+func (b *UPCA) GetCheckDigitPolicy() CheckDigitPolicy {
+	return get(b.BarcodeBase, func(o *settings) CheckDigitPolicy { return o.checkDigitPolicy })
+}
+
+// SetAddOn sets the EAN-2 or EAN-5 add-on drawn after the symbol, e.g. the
+// price of a book or the issue of a magazine. "" removes it.
+func (b *UPCA) SetAddOn(addOn string) error {
+	if err := validateAddOn(addOn); err != nil {
+		return err
+	}
+	return b.set(func(o *settings) { o.addOn = addOn }, nil)
+}
+
+// GetAddOn returns the value set by SetAddOn.
+func (b *UPCA) GetAddOn() string {
+	return get(b.BarcodeBase, func(o *settings) string { return o.addOn })
+}
+
+// UPCE generates UPC-E barcodes.
+type UPCE struct{ Barcode1DBase }
+
+// NewUPCE creates a UPC-E barcode generator.
+// It panics on failure; use NewUPCEE to handle errors.
+func NewUPCE(outputFormat string) *UPCE {
+	return must(NewUPCEE(outputFormat))
+}
+
+// NewUPCEE creates a UPC-E barcode generator.
+func NewUPCEE(outputFormat string) (*UPCE, error) {
+	base, err := newBarcodeBase(typeUPCE, outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return &UPCE{Barcode1DBase{base}}, nil
+}
+
+// SetExtendedGuard sets whether to use extended guard bars.
+func (b *UPCE) SetExtendedGuard(ext bool) error {
+	return b.set(func(o *settings) { o.extendedGuard = ext }, procSetExtendedGuard, boolToInt(ext))
+}
+
+// GetExtendedGuard returns the value set by SetExtendedGuard.
+func (b *UPCE) GetExtendedGuard() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.extendedGuard })
+}
+
+// SetCheckDigitPolicy sets how a wrong check digit is handled (STRICT,
+// LENIENT). The policy is applied before the native engine is called, so
+// with the default, STRICT, input the engine used to draw with a wrong
+// check digit now fails; LENIENT draws it with the digit corrected.
+func (b *UPCE) SetCheckDigitPolicy(policy CheckDigitPolicy) error {
+	policy, err := parseOption("check digit policy", policy, checkDigitPolicies)
+	if err != nil {
+		return err
+	}
+	return b.set(func(o *settings) { o.checkDigitPolicy = policy }, nil)
+}
+
+// GetCheckDigitPolicy returns the value set by SetCheckDigitPolicy.
+func (b *UPCE) GetCheckDigitPolicy() CheckDigitPolicy {
+	return get(b.BarcodeBase, func(o *settings) CheckDigitPolicy { return o.checkDigitPolicy })
+}
+
+// SetAddOn sets the EAN-2 or EAN-5 add-on drawn after the symbol, e.g. the
+// price of a book or the issue of a magazine. "" removes it.
+func (b *UPCE) SetAddOn(addOn string) error {
+	if err := validateAddOn(addOn); err != nil {
+		return err
+	}
+	return b.set(func(o *settings) { o.addOn = addOn }, nil)
+}
+
+// GetAddOn returns the value set by SetAddOn.
+func (b *UPCE) GetAddOn() string {
+	return get(b.BarcodeBase, func(o *settings) string { return o.addOn })
+}
+
+// ═════════════════════════════════════════════════════════════════════════════
 // GS1 DataBar
 // ═════════════════════════════════════════════════════════════════════════════
 
@@ -522,51 +1559,199 @@ func (b *UPCE) SetExtendedGuard(ext bool) {
 type GS1DataBar14 struct{ Barcode1DBase }
 
 // NewGS1DataBar14 creates a GS1 DataBar 14 barcode generator.
+// It panics on failure; use NewGS1DataBar14E to handle errors.
 func NewGS1DataBar14(outputFormat string) *GS1DataBar14 {
-	base, err := newBarcodeBase(12, outputFormat)
+	return must(NewGS1DataBar14E(outputFormat))
+}
+
+// NewGS1DataBar14E creates a GS1 DataBar 14 barcode generator.
+func NewGS1DataBar14E(outputFormat string) (*GS1DataBar14, error) {
+	base, err := newBarcodeBase(typeGS1DataBar14, outputFormat)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return &GS1DataBar14{Barcode1DBase{*base}}
+	return &GS1DataBar14{Barcode1DBase{base}}, nil
 }
 
 // SetSymbolType sets the symbol type (OMNIDIRECTIONAL, STACKED, STACKED_OMNIDIRECTIONAL).
-func (b *GS1DataBar14) SetSymbolType(symbolType string) {
-	procSetSymbolType14.Call(b.handle, toPtr(symbolType))
+func (b *GS1DataBar14) SetSymbolType(symbolType DataBarSymbolType) error {
+	symbolType, err := parseOption("GS1 DataBar 14 symbol type", symbolType, dataBar14Types)
+	if err != nil {
+		return err
+	}
+	return b.setString(func(o *settings) { o.symbolType14 = string(symbolType) }, procSetSymbolType14, string(symbolType))
+}
+
+// SetSymbolTypeString is SetSymbolType for a symbol type held in a string,
+// the parameter type SetSymbolType took before DataBarSymbolType was
+// introduced. The value is validated the same way.
+func (b *GS1DataBar14) SetSymbolTypeString(symbolType string) error {
+	return b.SetSymbolType(DataBarSymbolType(symbolType))
+}
+
+// GetSymbolType returns the symbol type the engine draws: OMNIDIRECTIONAL,
+// STACKED or STACKED_OMNIDIRECTIONAL.
+func (b *GS1DataBar14) GetSymbolType() DataBarSymbolType {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.handle == 0 {
+		return DataBarSymbolType(b.opts.symbolType14)
+	}
+	ptr, _, _ := procGetSymbolType14.Call(b.handle)
+	s, _ := goString(ptr)
+	return DataBarSymbolType(s)
+}
+
+// Validate runs the encoder on code without drawing: 13 digits, or 14 with
+// a valid check digit. It returns a *DrawError describing why code cannot
+// be encoded.
+func (b *GS1DataBar14) Validate(code string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	if b.handle == 0 {
+		return b.drawFailure(code)
+	}
+	if err := b.checkNativeCode(code); err != nil {
+		return err
+	}
+	var a cArgs
+	defer a.free()
+	ret, _, _ := procEncode14.Call(b.handle, a.str(code))
+	if ret != 1 {
+		return b.drawFailure(code)
+	}
+	return nil
+}
+
+// checkDigit14Mu serializes barcode_calculate_check_digit_14, which returns
+// its result in a buffer shared by all callers.
+var checkDigit14Mu sync.Mutex
+
+// CalculateCheckDigit14 returns the check digit the GS1 DataBar 14 encoder
+// appends to a 13-digit GTIN body. Without the native library it is
+// computed in Go.
+func CalculateCheckDigit14(src string) (string, error) {
+	if err := validateCharset(digitChars, "only digits are allowed")(src, nil); err != nil {
+		return "", inputError(typeGS1DataBar14, src, err)
+	}
+	if len(src) != 13 {
+		return "", inputError(typeGS1DataBar14, src, errDraw(ReasonInvalidLength, "expected 13 digits, got %d", len(src)))
+	}
+	if loadLibrary() != nil {
+		return string(gs1CheckDigit(src)), nil
+	}
+	checkDigit14Mu.Lock()
+	defer checkDigit14Mu.Unlock()
+	var a cArgs
+	defer a.free()
+	ptr, _, _ := procCalculateCheckDigit14.Call(a.str(src))
+	return goString(ptr)
 }
 
 // GS1DataBarLimited generates GS1 DataBar Limited barcodes.
 type GS1DataBarLimited struct{ Barcode1DBase }
 
 // NewGS1DataBarLimited creates a GS1 DataBar Limited barcode generator.
+// It panics on failure; use NewGS1DataBarLimitedE to handle errors.
 func NewGS1DataBarLimited(outputFormat string) *GS1DataBarLimited {
-	base, err := newBarcodeBase(13, outputFormat)
+	return must(NewGS1DataBarLimitedE(outputFormat))
+}
+
+// NewGS1DataBarLimitedE creates a GS1 DataBar Limited barcode generator.
+func NewGS1DataBarLimitedE(outputFormat string) (*GS1DataBarLimited, error) {
+	base, err := newBarcodeBase(typeGS1DataBarLimited, outputFormat)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return &GS1DataBarLimited{Barcode1DBase{*base}}
+	return &GS1DataBarLimited{Barcode1DBase{base}}, nil
 }
 
 // GS1DataBarExpanded generates GS1 DataBar Expanded barcodes.
 type GS1DataBarExpanded struct{ Barcode1DBase }
 
 // NewGS1DataBarExpanded creates a GS1 DataBar Expanded barcode generator.
+// It panics on failure; use NewGS1DataBarExpandedE to handle errors.
 func NewGS1DataBarExpanded(outputFormat string) *GS1DataBarExpanded {
-	base, err := newBarcodeBase(14, outputFormat)
+	return must(NewGS1DataBarExpandedE(outputFormat))
+}
+
+// NewGS1DataBarExpandedE creates a GS1 DataBar Expanded barcode generator.
+func NewGS1DataBarExpandedE(outputFormat string) (*GS1DataBarExpanded, error) {
+	base, err := newBarcodeBase(typeGS1DataBarExpanded, outputFormat)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return &GS1DataBarExpanded{Barcode1DBase{*base}}
+	return &GS1DataBarExpanded{Barcode1DBase{base}}, nil
 }
 
 // SetSymbolType sets the symbol type (UNSTACKED, STACKED).
-func (b *GS1DataBarExpanded) SetSymbolType(symbolType string) {
-	procSetSymbolTypeExp.Call(b.handle, toPtr(symbolType))
+func (b *GS1DataBarExpanded) SetSymbolType(symbolType DataBarSymbolType) error {
+	symbolType, err := parseOption("GS1 DataBar Expanded symbol type", symbolType, dataBarExpTypes)
+	if err != nil {
+		return err
+	}
+	return b.setString(func(o *settings) { o.symbolTypeExp = string(symbolType) }, procSetSymbolTypeExp, string(symbolType))
+}
+
+// SetSymbolTypeString is SetSymbolType for a symbol type held in a string,
+// the parameter type SetSymbolType took before DataBarSymbolType was
+// introduced. The value is validated the same way.
+func (b *GS1DataBarExpanded) SetSymbolTypeString(symbolType string) error {
+	return b.SetSymbolType(DataBarSymbolType(symbolType))
+}
+
+// GetSymbolType returns the value set by SetSymbolType.
+func (b *GS1DataBarExpanded) GetSymbolType() DataBarSymbolType {
+	return get(b.BarcodeBase, func(o *settings) DataBarSymbolType { return DataBarSymbolType(o.symbolTypeExp) })
 }
 
 // SetNoOfColumns sets the number of columns for stacked version.
-func (b *GS1DataBarExpanded) SetNoOfColumns(cols int) {
-	procSetNoOfColumns.Call(b.handle, uintptr(cols))
+func (b *GS1DataBarExpanded) SetNoOfColumns(cols int) error {
+	return b.set(func(o *settings) { o.expColumns = cols }, procSetNoOfColumns, uintptr(cols))
+}
+
+// GetNoOfColumns returns the value set by SetNoOfColumns.
+func (b *GS1DataBarExpanded) GetNoOfColumns() int {
+	return get(b.BarcodeBase, func(o *settings) int { return o.expColumns })
+}
+
+// DrawStacked generates a stacked GS1 DataBar Expanded barcode whatever the
+// symbol type, with SetNoOfColumns segment pairs per row, and returns
+// Base64 or SVG string.
+func (b *GS1DataBarExpanded) DrawStacked(code string, width, height int) (string, error) {
+	return b.renderString(func() (*drawing, error) { return b.renderStacked(code, width, height) })
+}
+
+// DrawStackedBytes is DrawStacked returning the raw PNG/JPEG data or SVG
+// markup.
+func (b *GS1DataBarExpanded) DrawStackedBytes(code string, width, height int) ([]byte, error) {
+	return b.renderBytes(func() (*drawing, error) { return b.renderStacked(code, width, height) })
+}
+
+// DrawStackedTo is DrawStacked writing the raw image to w.
+func (b *GS1DataBarExpanded) DrawStackedTo(w io.Writer, code string, width, height int) error {
+	return writeResult(w)(b.DrawStackedBytes(code, width, height))
+}
+
+// DrawStackedImage is DrawStacked returning an image.
+func (b *GS1DataBarExpanded) DrawStackedImage(code string, width, height int) (image.Image, error) {
+	return b.renderImage(func() (*drawing, error) { return b.renderStacked(code, width, height) })
+}
+
+func (b *GS1DataBarExpanded) renderStacked(code string, width, height int) (*drawing, error) {
+	if err := b.checkNativeCode(code); err != nil {
+		return nil, err
+	}
+	var a cArgs
+	defer a.free()
+	ret, _, _ := procDrawStacked.Call(b.handle, a.str(code), uintptr(width), uintptr(height))
+	if ret != 1 {
+		return nil, b.drawFailure(code, width, height)
+	}
+	return nil, nil
 }
 
 // ═════════════════════════════════════════════════════════════════════════════
@@ -575,44 +1760,106 @@ func (b *GS1DataBarExpanded) SetNoOfColumns(cols int) {
 
 // YubinCustomer generates Japanese postal customer barcodes.
 type YubinCustomer struct {
-	BarcodeBase
+	*BarcodeBase
 }
 
 // NewYubinCustomer creates a YubinCustomer barcode generator.
+// It panics on failure; use NewYubinCustomerE to handle errors.
 func NewYubinCustomer(outputFormat string) *YubinCustomer {
-	base, err := newBarcodeBase(15, outputFormat)
+	return must(NewYubinCustomerE(outputFormat))
+}
+
+// NewYubinCustomerE creates a YubinCustomer barcode generator.
+func NewYubinCustomerE(outputFormat string) (*YubinCustomer, error) {
+	base, err := newBarcodeBase(typeYubinCustomer, outputFormat)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return &YubinCustomer{*base}
+	return &YubinCustomer{base}, nil
 }
 
 // SetPxAdjustBlack sets pixel adjustment for black bars.
-func (b *YubinCustomer) SetPxAdjustBlack(adj int) {
-	procSetPxAdjustBlack.Call(b.handle, uintptr(adj))
+func (b *YubinCustomer) SetPxAdjustBlack(adj int) error {
+	return b.set(func(o *settings) { o.pxAdjustBlack = adj }, procSetPxAdjustBlack, uintptr(adj))
+}
+
+// GetPxAdjustBlack returns the value set by SetPxAdjustBlack.
+func (b *YubinCustomer) GetPxAdjustBlack() int {
+	return get(b.BarcodeBase, func(o *settings) int { return o.pxAdjustBlack })
 }
 
 // SetPxAdjustWhite sets pixel adjustment for white bars.
-func (b *YubinCustomer) SetPxAdjustWhite(adj int) {
-	procSetPxAdjustWhite.Call(b.handle, uintptr(adj))
+func (b *YubinCustomer) SetPxAdjustWhite(adj int) error {
+	return b.set(func(o *settings) { o.pxAdjustWhite = adj }, procSetPxAdjustWhite, uintptr(adj))
+}
+
+// GetPxAdjustWhite returns the value set by SetPxAdjustWhite.
+func (b *YubinCustomer) GetPxAdjustWhite() int {
+	return get(b.BarcodeBase, func(o *settings) int { return o.pxAdjustWhite })
 }
 
 // Draw generates a postal barcode. Width is auto-calculated.
 func (b *YubinCustomer) Draw(code string, height int) (string, error) {
-	ret, _, _ := procDrawYubin.Call(b.handle, toPtr(code), uintptr(height))
-	if ret != 1 {
-		return "", fmt.Errorf("draw failed")
-	}
-	return b.getResult()
+	return b.renderString(func() (*drawing, error) { return b.render(code, 0, height) })
 }
 
 // DrawWithWidth generates a postal barcode with explicit width.
 func (b *YubinCustomer) DrawWithWidth(code string, width, height int) (string, error) {
-	ret, _, _ := procDrawYubinWithWidth.Call(b.handle, toPtr(code), uintptr(width), uintptr(height))
+	return b.renderString(func() (*drawing, error) { return b.render(code, width, height) })
+}
+
+// DrawBytes generates a postal barcode and returns the raw PNG/JPEG data or
+// SVG markup. Width is auto-calculated.
+func (b *YubinCustomer) DrawBytes(code string, height int) ([]byte, error) {
+	return b.renderBytes(func() (*drawing, error) { return b.render(code, 0, height) })
+}
+
+// DrawBytesWithWidth is DrawBytes with explicit width.
+func (b *YubinCustomer) DrawBytesWithWidth(code string, width, height int) ([]byte, error) {
+	return b.renderBytes(func() (*drawing, error) { return b.render(code, width, height) })
+}
+
+// DrawTo generates a postal barcode and writes the raw image to w. Width is
+// auto-calculated.
+func (b *YubinCustomer) DrawTo(w io.Writer, code string, height int) error {
+	return writeResult(w)(b.DrawBytes(code, height))
+}
+
+// DrawToWithWidth is DrawTo with explicit width.
+func (b *YubinCustomer) DrawToWithWidth(w io.Writer, code string, width, height int) error {
+	return writeResult(w)(b.DrawBytesWithWidth(code, width, height))
+}
+
+// DrawImage generates a postal barcode as an image. Width is
+// auto-calculated.
+func (b *YubinCustomer) DrawImage(code string, height int) (image.Image, error) {
+	return b.renderImage(func() (*drawing, error) { return b.render(code, 0, height) })
+}
+
+// DrawImageWithWidth is DrawImage with explicit width.
+func (b *YubinCustomer) DrawImageWithWidth(code string, width, height int) (image.Image, error) {
+	return b.renderImage(func() (*drawing, error) { return b.render(code, width, height) })
+}
+
+// render draws with the engine's automatic width when width is 0.
+func (b *YubinCustomer) render(code string, width, height int) (*drawing, error) {
+	if err := b.checkNativeCode(code); err != nil {
+		return nil, err
+	}
+	var a cArgs
+	defer a.free()
+	if width == 0 {
+		ret, _, _ := procDrawYubin.Call(b.handle, a.str(code), uintptr(height))
+		if ret != 1 {
+			return nil, b.drawFailure(code, height)
+		}
+		return nil, nil
+	}
+	ret, _, _ := procDrawYubinWithWidth.Call(b.handle, a.str(code), uintptr(width), uintptr(height))
 	if ret != 1 {
-		return "", fmt.Errorf("draw failed")
+		return nil, b.drawFailure(code, width, height)
 	}
-	return b.getResult()
+	return nil, nil
 }
 
 // ═════════════════════════════════════════════════════════════════════════════
@@ -623,95 +1870,262 @@ func (b *YubinCustomer) DrawWithWidth(code string, width, height int) (string, e
 type QR struct{ Barcode2DBase }
 
 // NewQRCode creates a QR code generator.
+// It panics on failure; use NewQRCodeE to handle errors.
 func NewQRCode(outputFormat string) *QR {
-	base, err := newBarcodeBase(16, outputFormat)
+	return must(NewQRCodeE(outputFormat))
+}
+
+// NewQRCodeE creates a QR code generator.
+func NewQRCodeE(outputFormat string) (*QR, error) {
+	base, err := newBarcodeBase(typeQR, outputFormat)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return &QR{Barcode2DBase{*base}}
+	return &QR{Barcode2DBase{base}}, nil
 }
 
 // SetErrorCorrectionLevel sets the error correction level (L, M, Q, H).
-func (b *QR) SetErrorCorrectionLevel(level string) {
-	procSetErrorCorrectionLevel.Call(b.handle, toPtr(level))
+func (b *QR) SetErrorCorrectionLevel(level ECCLevel) error {
+	level, err := parseOption("QR error correction level", level, eccLevels)
+	if err != nil {
+		return err
+	}
+	return b.setString(func(o *settings) { o.eccLevel = string(level) }, procSetErrorCorrectionLevel, string(level))
+}
+
+// SetErrorCorrectionLevelString is SetErrorCorrectionLevel for a level held
+// in a string, the parameter type SetErrorCorrectionLevel took before
+// ECCLevel was introduced. The value is validated the same way.
+func (b *QR) SetErrorCorrectionLevelString(level string) error {
+	return b.SetErrorCorrectionLevel(ECCLevel(level))
+}
+
+// GetErrorCorrectionLevel returns the value set by SetErrorCorrectionLevel.
+func (b *QR) GetErrorCorrectionLevel() ECCLevel {
+	return get(b.BarcodeBase, func(o *settings) ECCLevel { return ECCLevel(o.eccLevel) })
 }
 
 // SetVersion sets QR version (0=auto, 1-40).
-func (b *QR) SetVersion(version int) {
-	procSetVersion.Call(b.handle, uintptr(version))
+func (b *QR) SetVersion(version int) error {
+	if version < 0 || version > 40 {
+		return fmt.Errorf("%w: QR version must be 0 (auto) to 40, got %d", ErrInvalidOption, version)
+	}
+	return b.set(func(o *settings) { o.qrVersion = version }, procSetVersion, uintptr(version))
+}
+
+// GetVersion returns the value set by SetVersion.
+func (b *QR) GetVersion() int {
+	return get(b.BarcodeBase, func(o *settings) int { return o.qrVersion })
 }
 
 // SetEncodeMode sets the encode mode (NUMERIC, ALPHANUMERIC, BYTE, KANJI).
-func (b *QR) SetEncodeMode(mode string) {
-	procSetEncodeMode.Call(b.handle, toPtr(mode))
+func (b *QR) SetEncodeMode(mode QREncodeMode) error {
+	mode, err := parseOption("QR encode mode", mode, qrEncodeModes)
+	if err != nil {
+		return err
+	}
+	return b.setString(func(o *settings) { o.encodeMode = string(mode) }, procSetEncodeMode, string(mode))
+}
+
+// SetEncodeModeString is SetEncodeMode for a mode held in a string, the
+// parameter type SetEncodeMode took before QREncodeMode was introduced. The
+// value is validated the same way.
+func (b *QR) SetEncodeModeString(mode string) error {
+	return b.SetEncodeMode(QREncodeMode(mode))
+}
+
+// GetEncodeMode returns the value set by SetEncodeMode.
+func (b *QR) GetEncodeMode() QREncodeMode {
+	return get(b.BarcodeBase, func(o *settings) QREncodeMode { return QREncodeMode(o.encodeMode) })
+}
+
+// SetGS1Mode makes Draw take GS1 element strings and produce GS1 QR Codes:
+// the input is validated against the GS1 AI table and encoded after an
+// FNC1 mode indicator, with the encode mode chosen per segment. GS1 QR
+// Codes are drawn by the pure-Go backend.
+func (b *QR) SetGS1Mode(on bool) error {
+	return b.set(func(o *settings) { o.gs1 = on }, nil)
+}
+
+// GetGS1Mode returns the value set by SetGS1Mode.
+func (b *QR) GetGS1Mode() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.gs1 })
 }
 
 // DataMatrix generates DataMatrix barcodes.
 type DataMatrix struct{ Barcode2DBase }
 
 // NewDataMatrix creates a DataMatrix barcode generator.
+// It panics on failure; use NewDataMatrixE to handle errors.
 func NewDataMatrix(outputFormat string) *DataMatrix {
-	base, err := newBarcodeBase(17, outputFormat)
+	return must(NewDataMatrixE(outputFormat))
+}
+
+// NewDataMatrixE creates a DataMatrix barcode generator.
+func NewDataMatrixE(outputFormat string) (*DataMatrix, error) {
+	base, err := newBarcodeBase(typeDataMatrix, outputFormat)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return &DataMatrix{Barcode2DBase{*base}}
+	return &DataMatrix{Barcode2DBase{base}}, nil
 }
 
 // SetCodeSize sets the code size (AUTO, 10x10, 12x12, etc.).
-func (b *DataMatrix) SetCodeSize(size string) {
-	procSetCodeSize.Call(b.handle, toPtr(size))
+func (b *DataMatrix) SetCodeSize(size DataMatrixSize) error {
+	size, err := parseOption("DataMatrix code size", size, dataMatrixSizeList)
+	if err != nil {
+		return err
+	}
+	return b.setString(func(o *settings) { o.dmCodeSize = string(size) }, procSetCodeSize, string(size))
+}
+
+// SetCodeSizeString is SetCodeSize for a size held in a string, the
+// parameter type SetCodeSize took before DataMatrixSize was introduced. The
+// value is validated the same way.
+func (b *DataMatrix) SetCodeSizeString(size string) error {
+	return b.SetCodeSize(DataMatrixSize(size))
+}
+
+// GetCodeSize returns the value set by SetCodeSize.
+func (b *DataMatrix) GetCodeSize() DataMatrixSize {
+	return get(b.BarcodeBase, func(o *settings) DataMatrixSize { return DataMatrixSize(o.dmCodeSize) })
 }
 
 // SetEncodeScheme sets the encode scheme (AUTO, ASCII, C40, TEXT, X12, EDIFACT, BASE256).
-func (b *DataMatrix) SetEncodeScheme(scheme string) {
-	procSetEncodeScheme.Call(b.handle, toPtr(scheme))
+func (b *DataMatrix) SetEncodeScheme(scheme DataMatrixScheme) error {
+	scheme, err := parseOption("DataMatrix encode scheme", scheme, dataMatrixSchemes)
+	if err != nil {
+		return err
+	}
+	return b.setString(func(o *settings) { o.dmEncodeScheme = string(scheme) }, procSetEncodeScheme, string(scheme))
+}
+
+// SetEncodeSchemeString is SetEncodeScheme for a scheme held in a string,
+// the parameter type SetEncodeScheme took before DataMatrixScheme was
+// introduced. The value is validated the same way.
+func (b *DataMatrix) SetEncodeSchemeString(scheme string) error {
+	return b.SetEncodeScheme(DataMatrixScheme(scheme))
+}
+
+// GetEncodeScheme returns the value set by SetEncodeScheme.
+func (b *DataMatrix) GetEncodeScheme() DataMatrixScheme {
+	return get(b.BarcodeBase, func(o *settings) DataMatrixScheme { return DataMatrixScheme(o.dmEncodeScheme) })
+}
+
+// SetGS1Mode makes Draw take GS1 element strings and produce GS1
+// DataMatrix: the input is validated against the GS1 AI table and encoded
+// with FNC1 in first position and as separator, in ASCII encodation (the
+// AUTO and ASCII encode schemes). GS1 DataMatrix is drawn by the pure-Go
+// backend.
+func (b *DataMatrix) SetGS1Mode(on bool) error {
+	return b.set(func(o *settings) { o.gs1 = on }, nil)
+}
+
+// GetGS1Mode returns the value set by SetGS1Mode.
+func (b *DataMatrix) GetGS1Mode() bool {
+	return get(b.BarcodeBase, func(o *settings) bool { return o.gs1 })
 }
 
 // PDF417 generates PDF417 barcodes.
 type PDF417 struct{ Barcode2DBase }
 
 // NewPDF417 creates a PDF417 barcode generator.
+// It panics on failure; use NewPDF417E to handle errors.
 func NewPDF417(outputFormat string) *PDF417 {
-	base, err := newBarcodeBase(18, outputFormat)
+	return must(NewPDF417E(outputFormat))
+}
+
+// NewPDF417E creates a PDF417 barcode generator.
+func NewPDF417E(outputFormat string) (*PDF417, error) {
+	base, err := newBarcodeBase(typePDF417, outputFormat)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return &PDF417{Barcode2DBase{*base}}
+	return &PDF417{Barcode2DBase{base}}, nil
 }
 
 // SetErrorLevel sets the error correction level (-1=auto, 0-8).
-func (b *PDF417) SetErrorLevel(level int) {
-	procSetErrorLevel.Call(b.handle, uintptr(level))
+func (b *PDF417) SetErrorLevel(level int) error {
+	return b.set(func(o *settings) { o.pdfErrorLevel = level }, procSetErrorLevel, uintptr(level))
+}
+
+// GetErrorLevel returns the value set by SetErrorLevel.
+func (b *PDF417) GetErrorLevel() int {
+	return get(b.BarcodeBase, func(o *settings) int { return o.pdfErrorLevel })
 }
 
 // SetColumns sets the number of columns.
-func (b *PDF417) SetColumns(cols int) {
-	procSetColumns.Call(b.handle, uintptr(cols))
+func (b *PDF417) SetColumns(cols int) error {
+	return b.set(func(o *settings) { o.pdfColumns = cols }, procSetColumns, uintptr(cols))
+}
+
+// GetColumns returns the value set by SetColumns.
+func (b *PDF417) GetColumns() int {
+	return get(b.BarcodeBase, func(o *settings) int { return o.pdfColumns })
 }
 
 // SetRows sets the number of rows.
-func (b *PDF417) SetRows(rows int) {
-	procSetRows.Call(b.handle, uintptr(rows))
+func (b *PDF417) SetRows(rows int) error {
+	return b.set(func(o *settings) { o.pdfRows = rows }, procSetRows, uintptr(rows))
+}
+
+// GetRows returns the value set by SetRows.
+func (b *PDF417) GetRows() int {
+	return get(b.BarcodeBase, func(o *settings) int { return o.pdfRows })
 }
 
 // SetAspectRatio sets the aspect ratio.
-func (b *PDF417) SetAspectRatio(ratio float64) {
-	procSetAspectRatio.Call(b.handle, uintptr(*(*uint64)(unsafe.Pointer(&ratio))))
+func (b *PDF417) SetAspectRatio(ratio float64) error {
+	return b.setDouble(func(o *settings) { o.pdfAspectRatio = ratio }, procSetAspectRatio, ratio)
+}
+
+// GetAspectRatio returns the value set by SetAspectRatio.
+func (b *PDF417) GetAspectRatio() float64 {
+	return get(b.BarcodeBase, func(o *settings) float64 { return o.pdfAspectRatio })
 }
 
 // SetYHeight sets the Y height.
-func (b *PDF417) SetYHeight(yHeight int) {
-	procSetYHeight.Call(b.handle, uintptr(yHeight))
+func (b *PDF417) SetYHeight(yHeight int) error {
+	return b.set(func(o *settings) { o.pdfYHeight = yHeight }, procSetYHeight, uintptr(yHeight))
+}
+
+// GetYHeight returns the value set by SetYHeight.
+func (b *PDF417) GetYHeight() int {
+	return get(b.BarcodeBase, func(o *settings) int { return o.pdfYHeight })
 }
 
 // Draw generates a PDF417 barcode (width × height).
 func (b *PDF417) Draw(code string, width, height int) (string, error) {
-	ret, _, _ := procDraw2DRect.Call(b.handle, toPtr(code), uintptr(width), uintptr(height))
+	return b.renderString(func() (*drawing, error) { return b.render(code, width, height) })
+}
+
+// DrawBytes generates a PDF417 barcode and returns the raw PNG/JPEG data or
+// SVG markup.
+func (b *PDF417) DrawBytes(code string, width, height int) ([]byte, error) {
+	return b.renderBytes(func() (*drawing, error) { return b.render(code, width, height) })
+}
+
+// DrawTo generates a PDF417 barcode and writes the raw image to w.
+func (b *PDF417) DrawTo(w io.Writer, code string, width, height int) error {
+	return writeResult(w)(b.DrawBytes(code, width, height))
+}
+
+// DrawImage generates a PDF417 barcode as an image.
+func (b *PDF417) DrawImage(code string, width, height int) (image.Image, error) {
+	return b.renderImage(func() (*drawing, error) { return b.render(code, width, height) })
+}
+
+func (b *PDF417) render(code string, width, height int) (*drawing, error) {
+	if err := b.checkNativeCode(code); err != nil {
+		return nil, err
+	}
+	var a cArgs
+	defer a.free()
+	ret, _, _ := procDraw2DRect.Call(b.handle, a.str(code), uintptr(width), uintptr(height))
 	if ret != 1 {
-		return "", fmt.Errorf("draw failed")
+		return nil, b.drawFailure(code, width, height)
 	}
-	return b.getResult()
+	return nil, nil
 }
 
 // ═════════════════════════════════════════════════════════════════════════════
@@ -0,0 +1,80 @@
+package barcode_pao
+
+import "strings"
+
+// ─── Japan Post customer barcode ───────────────────────────────────────────
+
+// yubinBars holds the three bars of each character value: digits 0–9, '-'
+// (10) and the control codes CC1–CC8 (11–18). Bars are 1 long, 2
+// ascender, 3 descender and 4 timing.
+var yubinBars = [19]string{
+	"144", "114", "132", "312", "123", "141", "321", "213", "231", "411", // 0-9
+	"414",                                                  // -
+	"324", "342", "234", "432", "243", "423", "441", "111", // CC1-CC8
+}
+
+const (
+	yubinHyphen = 10
+	yubinCC1    = 11
+	yubinCC4    = 14
+
+	yubinStart = "13"
+	yubinStop  = "31"
+
+	// yubinDataLen is the fixed number of data characters.
+	yubinDataLen = 20
+)
+
+// yubinValues converts an address code to the 20 data character values.
+// Letters take a control code and a digit; the data is cut at 20
+// characters, which may leave a lone control code, and padded with CC4.
+func yubinValues(code string) []int {
+	var vals []int
+	for _, r := range strings.ToUpper(code) {
+		switch {
+		case isDigit(r):
+			vals = append(vals, int(r-'0'))
+		case r == '-':
+			vals = append(vals, yubinHyphen)
+		default:
+			n := int(r - 'A')
+			vals = append(vals, yubinCC1+n/10, n%10)
+		}
+	}
+	if len(vals) > yubinDataLen {
+		vals = vals[:yubinDataLen]
+	}
+	for len(vals) < yubinDataLen {
+		vals = append(vals, yubinCC4)
+	}
+	return vals
+}
+
+func encodeYubin(code string, opts *settings) (*Symbol, error) {
+	if err := validators[typeYubinCustomer](code, opts); err != nil {
+		return nil, err
+	}
+	vals := yubinValues(code)
+	sum := 0
+	for _, v := range vals {
+		sum += v
+	}
+	vals = append(vals, (19-sum%19)%19)
+
+	bars := yubinStart
+	for _, v := range vals {
+		bars += yubinBars[v]
+	}
+	bars += yubinStop
+
+	// Bars are one module wide with one-module gaps. The three rows are
+	// the ascender, timing and descender zones.
+	grid := newGrid(3, 2*len(bars)-1)
+	for i, c := range bars {
+		x := 2 * i
+		grid[1][x] = true
+		grid[0][x] = c == '1' || c == '2'
+		grid[2][x] = c == '1' || c == '3'
+	}
+	return &Symbol{Modules: grid, RowHeights: []int{2, 2, 2}, QuietZone: 2}, nil
+}